@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	infrahttp "github.com/axarus/vectrag/internal/infrastructure/http"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adminPasswordStdin bool
+	adminRole          string
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Manage the admin users of the project and their roles",
+}
+
+var adminCreateUserCmd = &cobra.Command{
+	Use:   "create-user <username>",
+	Short: "Create an admin user",
+	Long: `The create-user command adds a user allowed to log in to the admin panel and
+to change the models and the content through the API. Its password is
+prompted for, or read from the standard input with --password-stdin, and only
+its bcrypt hash is stored in .vectrag/users.json. Its role, admin unless
+given with --role, decides what it may do; roles are defined under roles in
+vectrag.config.yaml.
+
+Once a project has an admin user, the admin panel, the models API, the trash
+API and the requests changing content require a session opened with
+POST /api/auth/login, or an API token created with vectrag token create.`,
+	Example: `  vectrag admin create-user alice
+  vectrag admin create-user bob --role editor
+  echo "$ADMIN_PASSWORD" | vectrag admin create-user alice --password-stdin`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		password, err := readPassword()
+		if err != nil {
+			return err
+		}
+
+		user, err := project.CreateUser(args[0], password, adminRole)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Admin user %s created with the %s role\n", user.Username, user.RoleName())
+		return nil
+	},
+}
+
+var adminListUsersCmd = &cobra.Command{
+	Use:   "list-users",
+	Short: "List the admin users",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		users, err := project.Users()
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			fmt.Println("No admin users, create one with vectrag admin create-user")
+			return nil
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "USERNAME\tROLE\tCREATED")
+		for _, u := range users {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", u.Username, u.RoleName(), u.CreatedAt.Local().Format(time.DateTime))
+		}
+		return tw.Flush()
+	},
+}
+
+var adminSetRoleCmd = &cobra.Command{
+	Use:   "set-role <username> <role>",
+	Short: "Change the role of an admin user",
+	Long: `The set-role command gives an admin user another role. The sessions of the
+user get the permissions of the new role right away.`,
+	Example: `  vectrag admin set-role bob author`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		user, err := project.SetUserRole(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Admin user %s now has the %s role\n", user.Username, user.RoleName())
+		return nil
+	},
+}
+
+var adminListRolesCmd = &cobra.Command{
+	Use:   "list-roles",
+	Short: "List the roles and their permissions",
+	Long: `The list-roles command shows the roles admin users may have: the built-in
+admin and public roles, and the ones defined under roles in
+vectrag.config.yaml. The public role applies to anonymous callers.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ROLE\tACTIONS\tMODELS\tFIELDS\tOWN ENTRIES")
+		for _, role := range project.Roles() {
+			if len(role.Permissions) == 0 {
+				fmt.Fprintf(tw, "%s\t-\t-\t-\t-\n", role.Name)
+			}
+			for _, p := range role.Permissions {
+				actions := make([]string, len(p.Actions))
+				for i, a := range p.Actions {
+					actions[i] = string(a)
+				}
+				fields := "*"
+				if len(p.Fields) > 0 {
+					fields = strings.Join(p.Fields, ",")
+				}
+				own := "no"
+				if p.Own {
+					own = "yes"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", role.Name, strings.Join(actions, ","), strings.Join(p.Models, ","), fields, own)
+			}
+		}
+		return tw.Flush()
+	},
+}
+
+func readPassword() (string, error) {
+	if adminPasswordStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read the password from stdin: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	validate := func(s string) error {
+		if len(s) < application.MinPasswordLength {
+			return fmt.Errorf("password must be at least %d characters", application.MinPasswordLength)
+		}
+		return nil
+	}
+	password, err := (&promptui.Prompt{Label: "Password", Mask: '*', Validate: validate}).Run()
+	if err != nil {
+		return "", err
+	}
+	confirm, err := (&promptui.Prompt{Label: "Confirm password", Mask: '*'}).Run()
+	if err != nil {
+		return "", err
+	}
+	if confirm != password {
+		return "", errors.New("passwords do not match")
+	}
+	return password, nil
+}
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+	adminCmd.AddCommand(adminCreateUserCmd, adminListUsersCmd, adminSetRoleCmd, adminListRolesCmd)
+
+	adminCreateUserCmd.Flags().BoolVar(&adminPasswordStdin, "password-stdin", false, "Read the password from the standard input")
+	adminCreateUserCmd.Flags().StringVar(&adminRole, "role", "", "Role of the user, defined in vectrag.config.yaml (default admin)")
+}
@@ -5,36 +5,71 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 
+	"github.com/axarus/vectrag/internal/application"
+	infrahttp "github.com/axarus/vectrag/internal/infrastructure/http"
 	"github.com/spf13/cobra"
 )
 
+var (
+	buildOpenAPI bool
+	buildOut     string
+)
+
 // buildCmd represents the build command
 var buildCmd = &cobra.Command{
 	Use:   "build",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("build called")
+	Short: "Generate build artifacts from the project models",
+	Long: `The build command generates artifacts describing the project from its models.
+
+  --openapi  writes the OpenAPI 3.1 document of the HTTP API, also served at
+             /api/openapi.json, to openapi.json in the project root or to --out`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !buildOpenAPI {
+			return fmt.Errorf("nothing to build, pass --openapi")
+		}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		root, err := application.FindProjectRoot(wd)
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		doc, err := project.OpenAPI()
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		out := buildOut
+		if out == "" {
+			out = filepath.Join(root, "openapi.json")
+		}
+		if err := os.WriteFile(out, append(data, '\n'), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", out, err)
+		}
+		fmt.Println("OpenAPI document written to", out)
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(buildCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// buildCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// buildCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	buildCmd.Flags().BoolVar(&buildOpenAPI, "openapi", false, "Generate the OpenAPI document")
+	buildCmd.Flags().StringVarP(&buildOut, "out", "o", "", "Output file (default: openapi.json in the project root)")
 }
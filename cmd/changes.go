@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+	infrahttp "github.com/axarus/vectrag/internal/infrastructure/http"
+	"github.com/spf13/cobra"
+)
+
+// changesPollInterval is how often vectrag changes --follow reads the log.
+const changesPollInterval = time.Second
+
+var (
+	changesAfter  int64
+	changesLimit  int
+	changesFollow bool
+	changesModels []string
+)
+
+// changeLine is a change printed by vectrag changes, in the format of the
+// file publishers of the outbox.
+type changeLine struct {
+	Seq   int64      `json:"seq"`
+	Event changeItem `json:"event"`
+}
+
+type changeItem struct {
+	Type          domain.EventType `json:"type"`
+	Model         string           `json:"model"`
+	EntryID       string           `json:"entryId,omitempty"`
+	PreviousModel string           `json:"previousModel,omitempty"`
+	Scheduled     bool             `json:"scheduled,omitempty"`
+	OccurredAt    time.Time        `json:"occurredAt"`
+}
+
+var changesCmd = &cobra.Command{
+	Use:   "changes",
+	Short: "Print the change log of the project",
+	Long: `The changes command prints the changes recorded in the change log of the
+project after an offset, one JSON object per line, oldest first. Every write of
+models and entries, by vectrag develop or the vectrag commands, is recorded in
+the same transaction as the write.
+
+The seq of the last change printed is the offset to read the next changes
+after. With --follow, the command keeps printing new changes until
+interrupted.`,
+	Example: `  vectrag changes
+  vectrag changes --after 120 --limit 50
+  vectrag changes --follow --model article`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if changesAfter < 0 {
+			return fmt.Errorf("--after must be a non-negative offset")
+		}
+
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		after := changesAfter
+		printed := 0
+		for {
+			limit := 0
+			if changesLimit > 0 {
+				limit = changesLimit - printed
+			}
+			page, err := project.Changes(after, limit)
+			if err != nil {
+				return err
+			}
+			if !page.Complete {
+				fmt.Fprintf(os.Stderr, "warning: the log does not hold every change after %d\n", after)
+			}
+			for _, c := range page.Changes {
+				printed++
+				if len(changesModels) > 0 && !slices.Contains(changesModels, c.Event.Model) && !slices.Contains(changesModels, c.Event.PreviousModel) {
+					continue
+				}
+				if err := enc.Encode(newChangeLine(c)); err != nil {
+					return err
+				}
+			}
+			after = page.Next
+
+			if !changesFollow || (changesLimit > 0 && printed >= changesLimit) {
+				return nil
+			}
+			time.Sleep(changesPollInterval)
+		}
+	},
+}
+
+func newChangeLine(c domain.Change) changeLine {
+	return changeLine{
+		Seq: c.Seq,
+		Event: changeItem{
+			Type:          c.Event.Type,
+			Model:         c.Event.Model,
+			EntryID:       c.Event.EntryID,
+			PreviousModel: c.Event.PreviousModel,
+			Scheduled:     c.Event.Scheduled,
+			OccurredAt:    c.Event.OccurredAt,
+		},
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(changesCmd)
+
+	changesCmd.Flags().Int64Var(&changesAfter, "after", 0, "Offset to print the changes after (default: the oldest change logged)")
+	changesCmd.Flags().IntVar(&changesLimit, "limit", 0, "Maximum number of changes to read (default: all)")
+	changesCmd.Flags().BoolVarP(&changesFollow, "follow", "f", false, "Keep printing new changes until interrupted")
+	changesCmd.Flags().StringSliceVar(&changesModels, "model", nil, "Only print the changes of these models, repeatable or separated by commas")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/spf13/cobra"
+)
+
+var (
+	codegenLang    string
+	codegenOut     string
+	codegenPackage string
+	codegenCheck   bool
+)
+
+var codegenCmd = &cobra.Command{
+	Use:   "codegen",
+	Short: "Generate typed code from the project models",
+	Long: `The codegen command generates a type for the data of every model and a typed
+client for the content API, in TypeScript (types.ts, client.ts) or Go
+(types.go, client.go).
+
+The output only depends on the models, so it can be committed. Use --check in
+CI to fail when the committed code is stale.`,
+	Example: `  vectrag codegen --lang ts --out ./web/src/content
+  vectrag codegen --lang go --out ./internal/content --check`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if codegenOut == "" {
+			return fmt.Errorf("--out is required")
+		}
+
+		repo, err := loadModelsRepository(nil)
+		if err != nil {
+			return err
+		}
+
+		pkg := codegenPackage
+		if pkg == "" {
+			pkg = application.GoPackageName(codegenOut)
+		}
+
+		svc := application.NewCodegenService(repo)
+		files, err := svc.Generate(application.CodegenOptions{
+			Lang:    application.CodegenLang(codegenLang),
+			Package: pkg,
+		})
+		if err != nil {
+			return err
+		}
+
+		if codegenCheck {
+			stale, err := svc.Stale(codegenOut, files)
+			if err != nil {
+				return err
+			}
+			if len(stale) > 0 {
+				return fmt.Errorf("generated code in %s is stale: %s, run vectrag codegen --lang %s --out %s",
+					codegenOut, strings.Join(stale, ", "), codegenLang, codegenOut)
+			}
+			fmt.Println("Generated code is up to date")
+			return nil
+		}
+
+		if err := svc.Write(codegenOut, files); err != nil {
+			return err
+		}
+		fmt.Println("Code generated in", codegenOut)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(codegenCmd)
+
+	codegenCmd.Flags().StringVar(&codegenLang, "lang", "ts", "Language to generate: ts or go")
+	codegenCmd.Flags().StringVarP(&codegenOut, "out", "o", "", "Directory to generate the code in")
+	codegenCmd.Flags().StringVar(&codegenPackage, "package", "", "Go package name (default: name of the output directory)")
+	codegenCmd.Flags().BoolVar(&codegenCheck, "check", false, "Fail if the generated code differs from the code in --out instead of writing it")
+}
@@ -26,11 +26,13 @@ This mode is intended for local environments and early-stage development.`,
 		basePort := 51987
 		host := "localhost"
 
+		routes := &infrahttp.APIRoutesProvider{}
 		svc := application.NewDevelopService(
 			infrahttp.ListenerProvider{},
 			infrahttp.ServerStarter{},
-			infrahttp.AdminHandlerProvider{},
-			infrahttp.APIRoutesProvider{},
+			infrahttp.AdminHandlerProvider{Routes: routes},
+			routes,
+			routes,
 		)
 
 		url, shutdown, err := svc.Start(basePort, host)
@@ -6,7 +6,6 @@ package cmd
 import (
 	"fmt"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"github.com/axarus/vectrag/internal/application"
@@ -14,6 +13,18 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	initName     string
+	initPort     string
+	initDatabase string
+	initTemplate string
+	initYes      bool
+)
+
+// databaseLabels are the names of the databases shown by the prompt, in the
+// order of application.Databases.
+var databaseLabels = []string{"PostgreSQL", "MySQL", "SQLite"}
+
 // initCmd represents the init command
 var initCmd = &cobra.Command{
 	Use:   "init [path]",
@@ -26,65 +37,90 @@ This command will create:
   - models/ (directory for model definitions)
   - config/(configuration files)
   - .vectrag/ (internal state directory)
+
+Settings passed as flags are not prompted for. With --yes, the defaults are
+used for the settings that are not passed, so that no prompt is shown.
+
+--template scaffolds a starter kit with ready-made models and seed content:
+  - blog       authors, categories, articles and site settings
+  - ecommerce  categories, products, customers, orders and store settings
+  - docs-rag   sections and documents to answer questions from, and
+               assistant settings
 `,
+	Example: `  vectrag init
+  vectrag init --name my-blog --template blog --yes
+  vectrag init ./projects --name shop --port 8080 --database postgres --template ecommerce`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		path := "."
 		if len(args) == 1 {
 			path = args[0]
 		}
 
-		// Prompt for project name
-		projectPrompt := promptui.Prompt{
-			Label:   "Project name",
-			Default: "my-app",
-			Validate: func(input string) error {
-				if input == "" {
-					return fmt.Errorf("project name cannot be empty")
-				}
-
-				if strings.Contains(input, " ") {
-					return fmt.Errorf("project name cannot contain spaces")
-				}
-				return nil
-			},
+		projectName := initName
+		if !cmd.Flags().Changed("name") && !initYes {
+			// Prompt for project name
+			projectPrompt := promptui.Prompt{
+				Label:    "Project name",
+				Default:  initName,
+				Validate: application.ValidateProjectName,
+			}
+
+			var err error
+			if projectName, err = projectPrompt.Run(); err != nil {
+				return err
+			}
 		}
 
-		projectName, err := projectPrompt.Run()
-		if err != nil {
-			return err
+		port := initPort
+		if !cmd.Flags().Changed("port") && !initYes {
+			// Prompt for port
+			portPrompt := promptui.Prompt{
+				Label:    "Port",
+				Default:  initPort,
+				Validate: application.ValidatePort,
+			}
+
+			var err error
+			if port, err = portPrompt.Run(); err != nil {
+				return err
+			}
 		}
 
-		// Prompt for port
-		portPrompt := promptui.Prompt{
-			Label:   "Port",
-			Default: "3000",
-			Validate: func(input string) error {
-				if input == "" {
-					return fmt.Errorf("port cannot be empty")
-				}
-				port, err := strconv.Atoi(input)
-				if err != nil {
-					return fmt.Errorf("port must be a number")
-				}
-				if port < 1 || port > 65535 {
-					return fmt.Errorf("port must be between 1 and 65535")
-				}
-				return nil
-			},
-		}
-		port, err := portPrompt.Run()
-		if err != nil {
-			return err
+		database := initDatabase
+		if !cmd.Flags().Changed("database") && !initYes {
+			// Prompt for database
+			dbPrompt := promptui.Select{
+				Label:     "Database",
+				Items:     databaseLabels,
+				CursorPos: 2,
+			}
+
+			i, _, err := dbPrompt.Run()
+			if err != nil {
+				return err
+			}
+			database = application.Databases[i]
 		}
 
-		// Prompt for database
-		dbPrompt := promptui.Select{
-			Label:     "Database",
-			Items:     []string{"PostgreSQL", "MySQL", "SQLite"},
-			CursorPos: 2,
+		template := initTemplate
+		if !cmd.Flags().Changed("template") && !initYes {
+			// Prompt for template
+			templatePrompt := promptui.Select{
+				Label: "Template",
+				Items: append([]string{"none"}, application.ProjectTemplates...),
+			}
+
+			i, selected, err := templatePrompt.Run()
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				template = selected
+			}
 		}
 
-		_, database, err := dbPrompt.Run()
+		database, err := application.NormalizeDatabase(database)
 		if err != nil {
 			return err
 		}
@@ -95,12 +131,16 @@ This command will create:
 			ProjectName: projectName,
 			Port:        port,
 			Database:    database,
+			Template:    template,
 		}
 
 		fmt.Printf("\nInitializing VectraG project at: %s\n", path)
 		fmt.Printf("Project name: %s\n", projectName)
 		fmt.Printf("Port: %s\n", port)
 		fmt.Printf("Database: %s\n", database)
+		if template != "" {
+			fmt.Printf("Template: %s\n", template)
+		}
 
 		if err := initService.InitializeProject(path, config); err != nil {
 			return fmt.Errorf("failed to initialize project: %w", err)
@@ -120,13 +160,9 @@ This command will create:
 func init() {
 	rootCmd.AddCommand(initCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// initCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// initCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	initCmd.Flags().StringVar(&initName, "name", "my-app", "Project name")
+	initCmd.Flags().StringVar(&initPort, "port", "3000", "Port of the development server")
+	initCmd.Flags().StringVar(&initDatabase, "database", "sqlite", "Database: "+strings.Join(application.Databases, ", "))
+	initCmd.Flags().StringVar(&initTemplate, "template", "", "Starter kit to scaffold: "+strings.Join(application.ProjectTemplates, ", "))
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "Use the defaults for settings not passed as flags instead of prompting")
 }
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/axarus/vectrag/internal/infrastructure/filestore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modelExportFormat string
+	modelExportOut    string
+)
+
+var modelCmd = &cobra.Command{
+	Use:   "model",
+	Short: "Manage the project models",
+}
+
+var modelExportCmd = &cobra.Command{
+	Use:   "export <slug>",
+	Short: "Export a model as a JSON Schema",
+	Long: `The export command converts a model to a JSON Schema (draft 2020-12) of the
+data of its entries. Field types, required fields and the properties JSON
+Schema cannot express, such as relation targets and unique fields, are kept
+in x-vectrag-* keywords so that vectrag model import restores the model.`,
+	Example: `  vectrag model export --format jsonschema article
+  vectrag model export --format jsonschema article --out schemas/article.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if modelExportFormat != "jsonschema" {
+			return fmt.Errorf("unsupported format %q, supported formats: jsonschema", modelExportFormat)
+		}
+
+		repo, err := loadModelsRepository(nil)
+		if err != nil {
+			return err
+		}
+		model, err := application.NewModelService(repo, nil).Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(application.ExportJSONSchema(model), "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+
+		if modelExportOut == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(modelExportOut, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", modelExportOut, err)
+		}
+		fmt.Println("JSON Schema written to", modelExportOut)
+		return nil
+	},
+}
+
+var modelImportCmd = &cobra.Command{
+	Use:   "import <file.json>",
+	Short: "Create a model from a JSON Schema",
+	Long: `The import command creates a model from a JSON Schema describing an object.
+Each property becomes a field. The model is named after the title of the
+schema, or after the file when it has none, and is created as a draft.
+
+Constructs a model cannot represent, such as nested objects, arrays, $ref,
+combinators or validation keywords, are skipped or approximated and listed
+after the import.`,
+	Example: `  vectrag model import schemas/article.json`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+		model, notes, err := application.ImportJSONSchema(data, name)
+		if err != nil {
+			return err
+		}
+
+		err = withModelService(func(svc *application.ModelService) error {
+			return svc.Create(model)
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Model %s created with %d fields\n", model.Slug, len(model.Fields))
+		if len(notes) > 0 {
+			fmt.Println("\nThe following constructs could not be represented:")
+			for _, n := range notes {
+				fmt.Println("  -", n)
+			}
+		}
+		return nil
+	},
+}
+
+func findProjectRoot() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return application.FindProjectRoot(wd)
+}
+
+// loadModelsRepository opens the models of the project containing the
+// working directory, writing through journal when it is not nil.
+func loadModelsRepository(journal *filestore.Journal) (domain.Repository, error) {
+	root, err := findProjectRoot()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := application.LoadProjectConfig(root)
+	if err != nil {
+		return nil, err
+	}
+	modelsDir, err := application.ResolveModelsDir(root, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return filestore.NewYamlRepository(modelsDir, journal)
+}
+
+// loadEventQueue returns the publisher recording the events of the vectrag
+// commands in the change log of the project containing the working
+// directory, and queueing them for its webhooks. vectrag develop streams and
+// delivers them, when it runs.
+func loadEventQueue(journal *filestore.Journal) (application.EventPublisher, error) {
+	root, err := findProjectRoot()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := application.LoadProjectConfig(root)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := application.WebhooksFromConfig(cfg.Webhooks)
+	if err != nil {
+		return nil, err
+	}
+	stateDir := application.ResolveStateDir(root)
+	deliveries, err := filestore.NewJSONDeliveryRepository(filepath.Join(stateDir, "webhook_deliveries.json"), journal)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := filestore.NewJSONChangeRepository(filepath.Join(stateDir, "changes.json"), journal)
+	if err != nil {
+		return nil, err
+	}
+
+	events := application.NewEventBus()
+	events.Subscribe(application.NewChangeFeed(changes, cfg.Events.LogSize, nil).Publish)
+	events.Subscribe(application.NewWebhookDispatcher(hooks, deliveries, nil, nil).Publish)
+	return events, nil
+}
+
+// inProject runs fn in a transaction of the project containing the working
+// directory, holding the lock shared with a running vectrag develop. fn is
+// given the journal of the transaction, whose writes are discarded when it
+// fails.
+func inProject(fn func(journal *filestore.Journal) error) error {
+	root, err := findProjectRoot()
+	if err != nil {
+		return err
+	}
+	lock, err := filestore.NewFileLock(application.ResolveLockFile(root))
+	if err != nil {
+		return err
+	}
+	journal, err := filestore.NewJournal(application.ResolveJournalFile(root))
+	if err != nil {
+		return err
+	}
+	return filestore.NewTxLock(lock, journal).Tx(func() error {
+		return fn(journal)
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(modelCmd)
+	modelCmd.AddCommand(modelExportCmd)
+	modelCmd.AddCommand(modelImportCmd)
+
+	modelExportCmd.Flags().StringVar(&modelExportFormat, "format", "jsonschema", "Export format: jsonschema")
+	modelExportCmd.Flags().StringVarP(&modelExportOut, "out", "o", "", "Output file (default: standard output)")
+}
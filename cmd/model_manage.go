@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/axarus/vectrag/internal/infrastructure/filestore"
+	infrahttp "github.com/axarus/vectrag/internal/infrastructure/http"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	modelOutput string
+
+	modelCreateDescription string
+	modelCreateKind        string
+	modelCreateStatus      string
+	modelCreateFields      []string
+
+	fieldType        string
+	fieldTarget      string
+	fieldDescription string
+	fieldRequired    bool
+	fieldUnique      bool
+	fieldStatus      string
+
+	modelRenameSlug string
+)
+
+var modelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the models",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var models []domain.Model
+		err := withModelService(func(svc *application.ModelService) error {
+			var err error
+			models, err = svc.List()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if modelOutput != "table" {
+			return printOutput(models, models...)
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "SLUG\tNAME\tKIND\tSTATUS\tFIELDS")
+		for _, m := range models {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\n", m.Slug, m.Name, m.Kind, m.Status, len(m.ActiveFields()))
+		}
+		return tw.Flush()
+	},
+}
+
+var modelShowCmd = &cobra.Command{
+	Use:   "show <slug>",
+	Short: "Show a model and its fields",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var model domain.Model
+		err := withModelService(func(svc *application.ModelService) error {
+			var err error
+			model, err = svc.Get(args[0])
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		return printModel(model)
+	},
+}
+
+var modelCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a model",
+	Long: `The create command creates a model. Its slug is derived from its name.
+
+Fields are given as name:type[:option,...] with the options required, unique,
+target=<slug> for relations and status=<status>. Fields have the status of the
+model unless set.`,
+	Example: `  vectrag model create Article --field title:string:required,unique --field body:text
+  vectrag model create "Site Settings" --kind single --status publish --field title:string
+  vectrag model create Comment --field article:relation:target=article,required`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		slug, err := application.Slugify(args[0])
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		model := domain.Model{
+			ID:            uuid.New().String(),
+			Name:          args[0],
+			Slug:          slug,
+			Description:   modelCreateDescription,
+			Kind:          domain.ModelKind(modelCreateKind),
+			Status:        domain.Status(modelCreateStatus),
+			SchemaVersion: 1,
+		}
+		for _, spec := range modelCreateFields {
+			field, err := parseFieldSpec(spec, model.Status, now)
+			if err != nil {
+				return err
+			}
+			model.Fields = append(model.Fields, field)
+		}
+		if err := domain.ValidateModel(model); err != nil {
+			return err
+		}
+
+		err = withModelService(func(svc *application.ModelService) error {
+			return svc.Create(model)
+		})
+		if err != nil {
+			return err
+		}
+		return printModel(model)
+	},
+}
+
+var modelAddFieldCmd = &cobra.Command{
+	Use:   "add-field <slug> <field>",
+	Short: "Add a field to a model",
+	Example: `  vectrag model add-field article views --type number
+  vectrag model add-field article author --type relation --target author --status publish`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now().UTC()
+		return updateModel(args[0], func(model *domain.Model) error {
+			model.Fields = append(model.Fields, domain.Field{
+				ID:          uuid.New().String(),
+				Name:        args[1],
+				Type:        domain.FieldType(fieldType),
+				Target:      fieldTarget,
+				Description: fieldDescription,
+				Unique:      fieldUnique,
+				Required:    fieldRequired,
+				Status:      domain.Status(fieldStatus),
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			})
+			return nil
+		})
+	},
+}
+
+var modelRemoveFieldCmd = &cobra.Command{
+	Use:   "remove-field <slug> <field>",
+	Short: "Move a field of a model to the trash",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateModel(args[0], func(model *domain.Model) error {
+			for i, f := range model.Fields {
+				if f.Name == args[1] && !f.IsDeleted() {
+					model.Fields = append(model.Fields[:i], model.Fields[i+1:]...)
+					return nil
+				}
+			}
+			return fmt.Errorf("model %s has no field %s", args[0], args[1])
+		})
+	},
+}
+
+var modelRenameCmd = &cobra.Command{
+	Use:   "rename <slug> <name>",
+	Short: "Change the name and slug of a model",
+	Long: `The rename command changes the name of a model and, with --slug, its slug.
+
+Changing the slug moves the entries of the model, rewrites the relation
+fields targeting it and records the change in the migration history. The
+former slug keeps redirecting to the model in the API for models.aliasDays
+days.`,
+	Example: `  vectrag model rename blog-post "Article"
+  vectrag model rename blog-post "Article" --slug article`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		model, warnings, err := project.RenameModel(args[0], args[1], modelRenameSlug)
+		if err != nil {
+			return err
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+		}
+		return printModel(model)
+	},
+}
+
+var modelHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the migration history of the models",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		migrations, err := project.Migrations()
+		if err != nil {
+			return err
+		}
+
+		switch modelOutput {
+		case "table":
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "AT\tTYPE\tMODEL\tFIELD\tFROM\tTO")
+			for _, m := range migrations {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", m.At.Format(time.RFC3339), m.Type, m.Model, m.Field, m.From, m.To)
+			}
+			return tw.Flush()
+		case "json":
+			return printOutput(migrations)
+		}
+		return fmt.Errorf("unsupported output %q, supported outputs: json, table", modelOutput)
+	},
+}
+
+var modelDeleteCmd = &cobra.Command{
+	Use:   "delete <slug>",
+	Short: "Move a model to the trash",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := withModelService(func(svc *application.ModelService) error {
+			return svc.Delete(args[0])
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Model %s moved to the trash\n", args[0])
+		return nil
+	},
+}
+
+// withModelService runs fn with the model service of the project containing
+// the working directory, in a transaction of the project, see inProject. The
+// changes it makes are logged and queued for the webhooks of the project.
+func withModelService(fn func(svc *application.ModelService) error) error {
+	return inProject(func(journal *filestore.Journal) error {
+		repo, err := loadModelsRepository(journal)
+		if err != nil {
+			return err
+		}
+		events, err := loadEventQueue(journal)
+		if err != nil {
+			return err
+		}
+		return fn(application.NewModelService(repo, events))
+	})
+}
+
+// updateModel applies change to a model and saves it.
+func updateModel(slug string, change func(*domain.Model) error) error {
+	var model domain.Model
+	err := withModelService(func(svc *application.ModelService) error {
+		var err error
+		if model, err = svc.Get(slug); err != nil {
+			return err
+		}
+		if err := change(&model); err != nil {
+			return err
+		}
+		if err := domain.ValidateModel(model); err != nil {
+			return err
+		}
+		model, err = svc.Update(model)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return printModel(model)
+}
+
+// parseFieldSpec parses a field given as name:type[:option,...].
+func parseFieldSpec(spec string, status domain.Status, now time.Time) (domain.Field, error) {
+	parts := strings.SplitN(spec, ":", 3)
+	if len(parts) < 2 {
+		return domain.Field{}, fmt.Errorf("invalid field %q, expected name:type[:option,...]", spec)
+	}
+
+	field := domain.Field{
+		ID:        uuid.New().String(),
+		Name:      parts[0],
+		Type:      domain.FieldType(parts[1]),
+		Status:    status,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if len(parts) == 3 {
+		for _, option := range strings.Split(parts[2], ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+			switch key {
+			case "required":
+				field.Required = true
+			case "unique":
+				field.Unique = true
+			case "target":
+				field.Target = value
+			case "status":
+				field.Status = domain.Status(value)
+			default:
+				return domain.Field{}, fmt.Errorf("invalid field %q: unknown option %q", spec, option)
+			}
+		}
+	}
+	return field, nil
+}
+
+func printModel(model domain.Model) error {
+	if modelOutput != "table" {
+		return printOutput(model, model)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Name:\t%s\n", model.Name)
+	fmt.Fprintf(tw, "Slug:\t%s\n", model.Slug)
+	fmt.Fprintf(tw, "Kind:\t%s\n", model.Kind)
+	fmt.Fprintf(tw, "Status:\t%s\n", model.Status)
+	if model.Description != "" {
+		fmt.Fprintf(tw, "Description:\t%s\n", model.Description)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	tw = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tTYPE\tTARGET\tREQUIRED\tUNIQUE\tSTATUS")
+	for _, f := range model.ActiveFields() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", f.Name, f.Type, f.Target, yesNo(f.Required), yesNo(f.Unique), f.Status)
+	}
+	return tw.Flush()
+}
+
+// printOutput prints v as JSON, the format of the models API, or models as
+// YAML, the format of the model files.
+func printOutput(v any, models ...domain.Model) error {
+	switch modelOutput {
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	case "yaml":
+		for i, m := range models {
+			data, err := filestore.MarshalModelYAML(m)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				fmt.Println("---")
+			}
+			fmt.Print(string(data))
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported output %q, supported outputs: json, yaml, table", modelOutput)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{modelListCmd, modelShowCmd, modelCreateCmd, modelAddFieldCmd, modelRemoveFieldCmd, modelRenameCmd, modelDeleteCmd, modelHistoryCmd} {
+		modelCmd.AddCommand(cmd)
+		if cmd != modelDeleteCmd {
+			cmd.Flags().StringVar(&modelOutput, "output", "table", "Output format: json, yaml or table")
+		}
+	}
+
+	modelCreateCmd.Flags().StringVar(&modelCreateDescription, "description", "", "Description of the model")
+	modelCreateCmd.Flags().StringVar(&modelCreateKind, "kind", string(domain.KindCollection), "Kind of model: collection or single")
+	modelCreateCmd.Flags().StringVar(&modelCreateStatus, "status", string(domain.StatusDraft), "Status of the model: draft or publish")
+	modelCreateCmd.Flags().StringArrayVar(&modelCreateFields, "field", nil, "Field as name:type[:option,...], repeatable")
+
+	modelAddFieldCmd.Flags().StringVar(&fieldType, "type", "", "Type of the field: string, text, number, boolean, date, datetime or relation")
+	modelAddFieldCmd.Flags().StringVar(&fieldTarget, "target", "", "Slug of the model a relation field points to")
+	modelAddFieldCmd.Flags().StringVar(&fieldDescription, "description", "", "Description of the field")
+	modelAddFieldCmd.Flags().BoolVar(&fieldRequired, "required", false, "Require a value")
+	modelAddFieldCmd.Flags().BoolVar(&fieldUnique, "unique", false, "Require values to be unique across entries")
+	modelAddFieldCmd.Flags().StringVar(&fieldStatus, "status", string(domain.StatusDraft), "Status of the field: draft or publish")
+	_ = modelAddFieldCmd.MarkFlagRequired("type")
+
+	modelRenameCmd.Flags().StringVar(&modelRenameSlug, "slug", "", "New slug of the model (default: unchanged)")
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	infrahttp "github.com/axarus/vectrag/internal/infrastructure/http"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tokenScopes  []string
+	tokenExpires string
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage the API tokens of machine clients",
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an API token",
+	Long: `The create command creates an API token and prints it. The token cannot be
+shown again: only its hash is stored, in .vectrag/tokens.json.
+
+Clients send the token as a bearer token. Its scopes limit what it may do,
+each written action:model where either may be *. Actions are read, preview
+(read drafts), create, update, delete, publish, schema (manage models) and
+admin (manage tokens). The read-only and full-access presets stand for
+read:* and *:*.`,
+	Example: `  vectrag token create website --scope read-only
+  vectrag token create importer --scope read:article --scope create:article --expires 30d
+  vectrag token create ci --scope full-access --expires 2026-12-31`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scopes, err := application.ParseScopes(tokenScopes)
+		if err != nil {
+			return err
+		}
+		expiresAt, err := parseExpiry(tokenExpires, time.Now())
+		if err != nil {
+			return err
+		}
+
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		token, secret, err := project.CreateToken(args[0], scopes, expiresAt)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("API token %s created (%s), copy it now, it will not be shown again:\n\n%s\n", token.Name, token.ID, secret)
+		return nil
+	},
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the API tokens",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		tokens, err := project.Tokens()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tPREFIX\tSCOPES\tEXPIRES\tLAST USED")
+		for _, t := range tokens {
+			scopes := make([]string, len(t.Scopes))
+			for i, s := range t.Scopes {
+				scopes[i] = s.String()
+			}
+			expires := "never"
+			if !t.ExpiresAt.IsZero() {
+				expires = t.ExpiresAt.Local().Format(time.DateTime)
+				if t.IsExpired(now) {
+					expires += " (expired)"
+				}
+			}
+			lastUsed := "never"
+			if !t.LastUsedAt.IsZero() {
+				lastUsed = t.LastUsedAt.Local().Format(time.DateTime)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.Name, t.Prefix, strings.Join(scopes, ","), expires, lastUsed)
+		}
+		return tw.Flush()
+	},
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <id|prefix>",
+	Short: "Revoke an API token",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := findProjectRoot()
+		if err != nil {
+			return err
+		}
+		project, err := infrahttp.LoadProject(root)
+		if err != nil {
+			return err
+		}
+
+		token, err := project.RevokeToken(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("API token %s (%s) revoked\n", token.Name, token.Prefix)
+		return nil
+	},
+}
+
+// parseExpiry parses a delay such as 30d or 12h, or a date or time. Empty
+// values never expire.
+func parseExpiry(s string, now time.Time) (time.Time, error) {
+	if s == "" || s == "never" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return now.AddDate(0, 0, n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry %q, use a delay such as 30d or 12h, or a date such as 2026-12-31", s)
+}
+
+func init() {
+	rootCmd.AddCommand(tokenCmd)
+	tokenCmd.AddCommand(tokenCreateCmd, tokenListCmd, tokenRevokeCmd)
+
+	tokenCreateCmd.Flags().StringArrayVar(&tokenScopes, "scope", []string{"read-only"}, "Scope as action:model, or a preset: read-only or full-access, repeatable")
+	tokenCreateCmd.Flags().StringVar(&tokenExpires, "expires", "", "Expiry as a delay such as 30d or 12h, or a date (never expires by default)")
+}
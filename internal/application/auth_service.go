@@ -0,0 +1,201 @@
+package application
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength is the minimum length of the passwords of admin users.
+const MinPasswordLength = 8
+
+// DefaultSessionTTL is how long admin users stay logged in when the project
+// does not configure it.
+const DefaultSessionTTL = 7 * 24 * time.Hour
+
+// dummyHash is compared to the passwords of unknown users so that logins take
+// as long whether the user exists or not.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("vectrag"), bcrypt.DefaultCost)
+	return hash
+})
+
+// AuthService manages the admin users of a project and their sessions.
+// Sessions are identified by tokens signed with the secret of the project, so
+// forged tokens are rejected without looking them up.
+type AuthService struct {
+	users      domain.UserRepository
+	sessions   domain.SessionRepository
+	roles      *RoleService
+	secret     []byte
+	sessionTTL time.Duration
+}
+
+func NewAuthService(users domain.UserRepository, sessions domain.SessionRepository, roles *RoleService, secret []byte, sessionTTL time.Duration) *AuthService {
+	if sessionTTL <= 0 {
+		sessionTTL = DefaultSessionTTL
+	}
+	return &AuthService{users: users, sessions: sessions, roles: roles, secret: secret, sessionTTL: sessionTTL}
+}
+
+// Enabled reports whether the project has admin users. Projects without any
+// do not require authentication.
+func (s *AuthService) Enabled() (bool, error) {
+	users, err := s.users.GetUsers()
+	if err != nil {
+		return false, err
+	}
+	return len(users) > 0, nil
+}
+
+// CreateUser adds a user with the given role, the admin role when empty.
+func (s *AuthService) CreateUser(username, password, role string) (domain.AdminUser, error) {
+	if err := domain.ValidateUsername(username); err != nil {
+		return domain.AdminUser{}, err
+	}
+	if role == "" {
+		role = domain.RoleAdmin
+	}
+	if _, err := s.roles.Role(role); err != nil {
+		return domain.AdminUser{}, err
+	}
+	if len(password) < MinPasswordLength {
+		return domain.AdminUser{}, &domain.ValidationError{Field: "password", Message: fmt.Sprintf("must be at least %d characters", MinPasswordLength)}
+	}
+	// bcrypt ignores the bytes past the 72nd.
+	if len(password) > 72 {
+		return domain.AdminUser{}, &domain.ValidationError{Field: "password", Message: "must be at most 72 bytes"}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return domain.AdminUser{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user := domain.AdminUser{
+		ID:           uuid.New().String(),
+		Username:     username,
+		PasswordHash: string(hash),
+		Role:         role,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err := s.users.CreateUser(user); err != nil {
+		return domain.AdminUser{}, err
+	}
+	return user, nil
+}
+
+func (s *AuthService) ListUsers() ([]domain.AdminUser, error) {
+	return s.users.GetUsers()
+}
+
+// SetRole changes the role of a user. Its sessions get the permissions of
+// the new role right away.
+func (s *AuthService) SetRole(username, role string) (domain.AdminUser, error) {
+	if _, err := s.roles.Role(role); err != nil {
+		return domain.AdminUser{}, err
+	}
+	user, err := s.users.GetUser(username)
+	if err != nil {
+		return domain.AdminUser{}, err
+	}
+	user.Role = role
+	if err := s.users.UpdateUser(user); err != nil {
+		return domain.AdminUser{}, err
+	}
+	return user, nil
+}
+
+// Login checks the password of a user and opens a session, returning the
+// token identifying it.
+func (s *AuthService) Login(username, password string) (string, domain.Session, error) {
+	user, err := s.users.GetUser(username)
+	if err != nil {
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			return "", domain.Session{}, err
+		}
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return "", domain.Session{}, domain.ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return "", domain.Session{}, domain.ErrInvalidCredentials
+	}
+
+	now := time.Now().UTC()
+	if err := s.sessions.DeleteExpiredSessions(now); err != nil {
+		return "", domain.Session{}, err
+	}
+
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", domain.Session{}, fmt.Errorf("failed to generate session: %w", err)
+	}
+	session := domain.Session{
+		ID:        base64.RawURLEncoding.EncodeToString(id),
+		Username:  user.Username,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.sessionTTL),
+	}
+	if err := s.sessions.PutSession(session); err != nil {
+		return "", domain.Session{}, err
+	}
+	return session.ID + "." + s.sign(session.ID), session, nil
+}
+
+// Authenticate returns the user of the session identified by token. It fails
+// with domain.ErrSessionNotFound when the token is forged, the session ended
+// or its user no longer exists.
+func (s *AuthService) Authenticate(token string) (domain.AdminUser, error) {
+	session, err := s.session(token)
+	if err != nil {
+		return domain.AdminUser{}, err
+	}
+	user, err := s.users.GetUser(session.Username)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return domain.AdminUser{}, domain.ErrSessionNotFound
+	}
+	return user, err
+}
+
+// Logout ends the session identified by token.
+func (s *AuthService) Logout(token string) error {
+	session, err := s.session(token)
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.sessions.DeleteSession(session.ID)
+}
+
+func (s *AuthService) session(token string) (domain.Session, error) {
+	id, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(id))) {
+		return domain.Session{}, domain.ErrSessionNotFound
+	}
+
+	session, err := s.sessions.GetSession(id)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	if session.IsExpired(time.Now()) {
+		return domain.Session{}, domain.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (s *AuthService) sign(id string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
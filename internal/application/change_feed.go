@@ -0,0 +1,252 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+const (
+	// defaultChangeLogSize is how many changes are kept for the clients
+	// resuming the event stream when the config sets no events.logSize.
+	defaultChangeLogSize      = 1000
+	defaultChangeFeedInterval = time.Second
+	// changeSubscriptionBuffer is how many changes a subscriber may lag
+	// behind before its subscription is ended.
+	changeSubscriptionBuffer = 256
+)
+
+// ChangeFeed records the events of a project in its change log and streams
+// them to subscribers. Changes recorded by other processes, such as the
+// vectrag commands, are streamed once Run finds them in the log.
+type ChangeFeed struct {
+	changes  domain.ChangeRepository
+	size     int
+	tx       Transactor
+	interval time.Duration
+	wake     chan struct{}
+
+	mu sync.Mutex
+	// last is the number of the last change sent to subscribers, read from
+	// the log on first use.
+	last        int64
+	started     bool
+	subscribers map[*ChangeSubscription]bool
+}
+
+// ChangeSubscription receives the changes of a ChangeFeed on C. C is closed
+// when the feed stops, or when the subscriber falls too far behind; it may
+// then subscribe again from the last change it received.
+type ChangeSubscription struct {
+	C     <-chan domain.Change
+	c     chan domain.Change
+	after int64
+	feed  *ChangeFeed
+}
+
+// NewChangeFeed creates a feed keeping the last size changes, a default of
+// 1000 when size is zero or less. Publish must be called in a transaction of
+// tx, which the feed runs to read the log. Feeds that only record events, and
+// are never run, need no tx.
+func NewChangeFeed(changes domain.ChangeRepository, size int, tx Transactor) *ChangeFeed {
+	if size <= 0 {
+		size = defaultChangeLogSize
+	}
+	return &ChangeFeed{
+		changes:     changes,
+		size:        size,
+		tx:          tx,
+		interval:    defaultChangeFeedInterval,
+		wake:        make(chan struct{}, 1),
+		subscribers: make(map[*ChangeSubscription]bool),
+	}
+}
+
+// Publish records e in the change log.
+func (f *ChangeFeed) Publish(e domain.Event) error {
+	if _, err := f.changes.AppendChange(e, f.size); err != nil {
+		return fmt.Errorf("recording %s %s: %w", e.Type, e.Model, err)
+	}
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Subscribe streams the changes numbered after seq, or only the new changes
+// when seq is negative. It returns the recorded changes the subscription
+// will not repeat, and reports false when the log no longer holds every
+// change after seq, e.g. because the subscriber was away for too long.
+func (f *ChangeFeed) Subscribe(after int64) (*ChangeSubscription, []domain.Change, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var missed []domain.Change
+	complete := true
+	err := runTx(f.tx, func() error {
+		if err := f.start(); err != nil {
+			return err
+		}
+		if after < 0 {
+			after = f.last
+			return nil
+		}
+		changes, oldest, err := f.changes.GetChanges(after)
+		if err != nil {
+			return err
+		}
+		last, err := f.changes.LastSeq()
+		if err != nil {
+			return err
+		}
+		complete = after+1 >= oldest && after <= last
+		for _, c := range changes {
+			if c.Seq <= f.last {
+				missed = append(missed, c)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	c := make(chan domain.Change, changeSubscriptionBuffer)
+	sub := &ChangeSubscription{C: c, c: c, after: after, feed: f}
+	f.subscribers[sub] = true
+	return sub, missed, complete, nil
+}
+
+// ChangePage is a slice of the change log, see ChangeFeed.Changes.
+type ChangePage struct {
+	Changes []domain.Change
+	// Next is the number of the last change of the page, or of the change
+	// it was read after when it is empty: the offset of the next page.
+	Next int64
+	// More reports whether changes follow the page.
+	More bool
+	// Complete reports false when the log no longer holds every change
+	// after the offset the page was read from.
+	Complete bool
+}
+
+// Changes returns at most limit changes numbered after seq, oldest first.
+// Every change is returned when limit is zero or less.
+func (f *ChangeFeed) Changes(after int64, limit int) (ChangePage, error) {
+	var changes []domain.Change
+	var oldest, last int64
+	err := runTx(f.tx, func() error {
+		var err error
+		if changes, oldest, err = f.changes.GetChanges(after); err != nil {
+			return err
+		}
+		last, err = f.changes.LastSeq()
+		return err
+	})
+	if err != nil {
+		return ChangePage{}, err
+	}
+
+	page := ChangePage{Changes: changes, Next: after, Complete: after+1 >= oldest && after <= last}
+	if limit > 0 && len(changes) > limit {
+		page.Changes = changes[:limit]
+		page.More = true
+	}
+	if len(page.Changes) > 0 {
+		page.Next = page.Changes[len(page.Changes)-1].Seq
+	}
+	return page, nil
+}
+
+// Close ends the subscription.
+func (s *ChangeSubscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	s.feed.drop(s)
+}
+
+// Run streams the new changes of the log to the subscribers until ctx is
+// cancelled, then ends every subscription.
+func (f *ChangeFeed) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.broadcast(); err != nil {
+			log.Printf("changes: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			f.mu.Lock()
+			for sub := range f.subscribers {
+				f.drop(sub)
+			}
+			f.mu.Unlock()
+			return
+		case <-ticker.C:
+		case <-f.wake:
+		}
+	}
+}
+
+func (f *ChangeFeed) broadcast() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var changes []domain.Change
+	err := runTx(f.tx, func() error {
+		if err := f.start(); err != nil {
+			return err
+		}
+		var err error
+		changes, _, err = f.changes.GetChanges(f.last)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		for sub := range f.subscribers {
+			if c.Seq <= sub.after {
+				continue
+			}
+			select {
+			case sub.c <- c:
+			default:
+				f.drop(sub)
+			}
+		}
+		f.last = c.Seq
+	}
+	return nil
+}
+
+// start reads the number of the last change on first use, so that changes
+// recorded before the feed started are only sent to the subscribers asking
+// for them.
+func (f *ChangeFeed) start() error {
+	if f.started {
+		return nil
+	}
+	last, err := f.changes.LastSeq()
+	if err != nil {
+		return err
+	}
+	f.last = last
+	f.started = true
+	return nil
+}
+
+func (f *ChangeFeed) drop(sub *ChangeSubscription) {
+	if f.subscribers[sub] {
+		delete(f.subscribers, sub)
+		close(sub.c)
+	}
+}
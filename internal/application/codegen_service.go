@@ -0,0 +1,286 @@
+package application
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+//go:embed resources/codegen/*
+var codegenTemplates embed.FS
+
+// CodegenLang is a language code can be generated for.
+type CodegenLang string
+
+const (
+	CodegenTypeScript CodegenLang = "ts"
+	CodegenGo         CodegenLang = "go"
+)
+
+// codegenFiles lists the files generated for each language, by template.
+var codegenFiles = map[CodegenLang]map[string]string{
+	CodegenTypeScript: {
+		"types.ts":  "types.ts.tmpl",
+		"client.ts": "client.ts.tmpl",
+	},
+	CodegenGo: {
+		"types.go":  "types.go.tmpl",
+		"client.go": "client.go.tmpl",
+	},
+}
+
+// CodegenService generates typed code for the content of the models: a type
+// per model and a client for the content API. The output only depends on the
+// models, so it can be committed and checked for staleness.
+type CodegenService struct {
+	models domain.Repository
+}
+
+func NewCodegenService(models domain.Repository) *CodegenService {
+	return &CodegenService{models: models}
+}
+
+// CodegenOptions configures code generation.
+type CodegenOptions struct {
+	Lang CodegenLang
+	// Package is the name of the generated Go package.
+	Package string
+}
+
+type codegenModel struct {
+	Slug        string
+	Name        string
+	Description string
+	TypeName    string
+	Single      bool
+	Fields      []codegenField
+}
+
+type codegenField struct {
+	Name        string
+	Description string
+	Ident       string
+	Type        string
+	Required    bool
+	Draft       bool
+	Target      string
+}
+
+// Generate returns the content of the generated files by name.
+func (s *CodegenService) Generate(opts CodegenOptions) (map[string][]byte, error) {
+	files, ok := codegenFiles[opts.Lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language '%s', expected 'ts' or 'go'", opts.Lang)
+	}
+	if opts.Lang == CodegenGo && !goPackageName.MatchString(opts.Package) {
+		return nil, fmt.Errorf("invalid Go package name '%s'", opts.Package)
+	}
+
+	models, err := s.models.GetModels()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Slug < models[j].Slug })
+
+	data := struct {
+		Package string
+		Models  []codegenModel
+	}{Package: opts.Package}
+	for _, m := range models {
+		if !m.IsDeleted() {
+			data.Models = append(data.Models, newCodegenModel(m, opts.Lang))
+		}
+	}
+
+	generated := make(map[string][]byte, len(files))
+	for name, tmplName := range files {
+		content, err := codegenTemplates.ReadFile("resources/codegen/" + tmplName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", tmplName, err)
+		}
+		tmpl, err := template.New(name).Funcs(codegenFuncs).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", tmplName, err)
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to execute template %s: %w", tmplName, err)
+		}
+
+		out := buf.Bytes()
+		if opts.Lang == CodegenGo {
+			if out, err = format.Source(out); err != nil {
+				return nil, fmt.Errorf("failed to format %s: %w", name, err)
+			}
+		}
+		generated[name] = out
+	}
+	return generated, nil
+}
+
+// Write writes generated files to dir, creating it if needed.
+func (s *CodegenService) Write(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	for _, name := range sortedNames(files) {
+		if err := os.WriteFile(filepath.Join(dir, name), files[name], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Stale returns the generated files that are missing from dir or differ
+// from their content there.
+func (s *CodegenService) Stale(dir string, files map[string][]byte) ([]string, error) {
+	var stale []string
+	for _, name := range sortedNames(files) {
+		existing, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if err != nil || !bytes.Equal(existing, files[name]) {
+			stale = append(stale, name)
+		}
+	}
+	return stale, nil
+}
+
+func sortedNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var goPackageName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// GoPackageName derives a package name from the directory code is generated
+// in, e.g. "content" for "./gen/content".
+func GoPackageName(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	name := strings.ToLower(identifier(filepath.Base(abs), "pkg"))
+	if !goPackageName.MatchString(name) {
+		return "content"
+	}
+	return name
+}
+
+// newCodegenModel prepares a model for the templates. Deleted fields are left
+// out. Identifiers are made unique once converted for the target language.
+func newCodegenModel(m domain.Model, lang CodegenLang) codegenModel {
+	typeName := ModelTypeName(m.Slug)
+	if lang == CodegenGo {
+		typeName = identifier(pascalCase(m.Slug), "M")
+	}
+
+	cm := codegenModel{
+		Slug:        m.Slug,
+		Name:        m.Name,
+		Description: m.Description,
+		TypeName:    typeName,
+		Single:      m.Kind == domain.KindSingle,
+	}
+
+	taken := map[string]bool{}
+	for _, f := range m.ActiveFields() {
+		field := codegenField{
+			Name:        f.Name,
+			Description: f.Description,
+			Required:    f.Required,
+			Draft:       f.Status == domain.StatusDraft,
+		}
+		if f.Type == domain.FieldRelation {
+			field.Target = f.Target
+		}
+
+		switch lang {
+		case CodegenGo:
+			field.Ident = identifier(pascalCase(f.Name), "F")
+			field.Type = goFieldType(f.Type)
+		case CodegenTypeScript:
+			field.Ident = f.Name
+			field.Type = tsFieldType(f.Type)
+		}
+		for base, i := field.Ident, 2; taken[field.Ident]; i++ {
+			field.Ident = fmt.Sprintf("%s%d", base, i)
+		}
+		taken[field.Ident] = true
+
+		cm.Fields = append(cm.Fields, field)
+	}
+	return cm
+}
+
+func goFieldType(t domain.FieldType) string {
+	switch domain.FieldValueType(t) {
+	case domain.ValueNumber:
+		return "float64"
+	case domain.ValueBoolean:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+func tsFieldType(t domain.FieldType) string {
+	switch domain.FieldValueType(t) {
+	case domain.ValueNumber:
+		return "number"
+	case domain.ValueBoolean:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+var codegenFuncs = template.FuncMap{
+	// tsKey quotes property names that are not valid identifiers.
+	"tsKey": func(name string) string {
+		if tsIdentifier.MatchString(name) {
+			return name
+		}
+		return fmt.Sprintf("%q", name)
+	},
+	"quote": func(s string) string {
+		return fmt.Sprintf("%q", s)
+	},
+	"lowerFirst": func(s string) string {
+		if s == "" {
+			return s
+		}
+		return strings.ToLower(s[:1]) + s[1:]
+	},
+	// comment formats a field's documentation as a single line.
+	"comment": func(f codegenField) string {
+		var parts []string
+		if f.Description != "" {
+			parts = append(parts, strings.Join(strings.Fields(f.Description), " "))
+		}
+		if f.Target != "" {
+			parts = append(parts, fmt.Sprintf("ID of an entry of the %s model.", f.Target))
+		}
+		if f.Draft {
+			parts = append(parts, "Draft field, only returned in previews.")
+		}
+		return strings.Join(parts, " ")
+	},
+}
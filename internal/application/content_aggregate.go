@@ -0,0 +1,172 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// Aggregate computes q over the entries of a collection model, with the same
+// visibility rules and policy restrictions as Query. Groups and aggregations
+// apply to model fields. Repositories implementing
+// domain.AggregateRepository compute it in storage, others are aggregated in
+// memory.
+func (cs *ContentService) Aggregate(slug string, q domain.AggregateQuery, preview bool, policy domain.Policy) ([]domain.AggregateGroup, error) {
+	model, err := cs.collection(slug)
+	if err != nil {
+		return nil, err
+	}
+	if q, err = policy.RestrictAggregate(readAction(preview), slug, q); err != nil {
+		return nil, err
+	}
+
+	compiled := q
+	compiled.Published = !preview
+	if q.Filter != nil {
+		filter, err := cs.compileFilter(model, *q.Filter, preview, policy)
+		if err != nil {
+			return nil, err
+		}
+		compiled.Filter = &filter
+	}
+
+	for _, name := range q.GroupBy {
+		field, ok := visibleField(model, name, preview)
+		if !ok {
+			return nil, &domain.ValidationError{Field: "groupBy", Message: fmt.Sprintf("unknown field '%s'", name)}
+		}
+		if err := domain.ValidateGroupBy(field.Type); err != nil {
+			return nil, &domain.ValidationError{Field: "groupBy", Message: err.Error()}
+		}
+	}
+	for _, agg := range q.Aggregations {
+		field, ok := visibleField(model, agg.Field, preview)
+		if !ok {
+			return nil, &domain.ValidationError{Field: string(agg.Func), Message: fmt.Sprintf("unknown field '%s'", agg.Field)}
+		}
+		if err := domain.ValidateAggregation(agg.Func, field.Type); err != nil {
+			return nil, &domain.ValidationError{Field: string(agg.Func), Message: err.Error()}
+		}
+	}
+
+	if repo, ok := cs.content.(domain.AggregateRepository); ok {
+		return repo.AggregateEntries(slug, compiled)
+	}
+
+	page, err := cs.content.FindEntries(slug, domain.Query{Filter: compiled.Filter, Published: compiled.Published})
+	if err != nil {
+		return nil, err
+	}
+	return aggregateEntries(page.Entries, compiled), nil
+}
+
+type groupAccumulator struct {
+	group domain.AggregateGroup
+	sums  map[domain.Aggregation]float64
+	count map[domain.Aggregation]int
+}
+
+func aggregateEntries(entries []domain.Entry, q domain.AggregateQuery) []domain.AggregateGroup {
+	groups := make(map[string]*groupAccumulator)
+	var order []string
+
+	for _, e := range entries {
+		key := make(map[string]any, len(q.GroupBy))
+		parts := make([]string, len(q.GroupBy))
+		for i, name := range q.GroupBy {
+			key[name] = e.Data[name]
+			parts[i] = fmt.Sprintf("%T:%v", e.Data[name], e.Data[name])
+		}
+		id := strings.Join(parts, "\x00")
+
+		acc, ok := groups[id]
+		if !ok {
+			acc = &groupAccumulator{
+				group: domain.AggregateGroup{Key: key, Values: map[domain.Aggregation]any{}},
+				sums:  map[domain.Aggregation]float64{},
+				count: map[domain.Aggregation]int{},
+			}
+			groups[id] = acc
+			order = append(order, id)
+		}
+
+		acc.group.Count++
+		for _, agg := range q.Aggregations {
+			acc.add(agg, e.Data[agg.Field])
+		}
+	}
+
+	result := make([]domain.AggregateGroup, 0, len(order))
+	for _, id := range order {
+		acc := groups[id]
+		for _, agg := range q.Aggregations {
+			switch agg.Func {
+			case domain.AggSum:
+				acc.group.Values[agg] = acc.sums[agg]
+			case domain.AggAvg:
+				if n := acc.count[agg]; n > 0 {
+					acc.group.Values[agg] = acc.sums[agg] / float64(n)
+				} else {
+					acc.group.Values[agg] = nil
+				}
+			default:
+				if _, ok := acc.group.Values[agg]; !ok {
+					acc.group.Values[agg] = nil
+				}
+			}
+		}
+		result = append(result, acc.group)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		for _, name := range q.GroupBy {
+			a, b := result[i].Key[name], result[j].Key[name]
+			// Missing values come first, and false before true.
+			if c := domain.CompareValues(a, b); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	// Counting with no group and no entries still yields a single group.
+	if len(result) == 0 && len(q.GroupBy) == 0 {
+		values := make(map[domain.Aggregation]any, len(q.Aggregations))
+		for _, agg := range q.Aggregations {
+			values[agg] = nil
+			if agg.Func == domain.AggSum {
+				values[agg] = 0.0
+			}
+		}
+		result = append(result, domain.AggregateGroup{Key: map[string]any{}, Values: values})
+	}
+
+	return result
+}
+
+func (acc *groupAccumulator) add(agg domain.Aggregation, value any) {
+	if value == nil {
+		return
+	}
+
+	switch agg.Func {
+	case domain.AggSum, domain.AggAvg:
+		if n, ok := value.(float64); ok {
+			acc.sums[agg] += n
+			acc.count[agg]++
+		}
+	case domain.AggMin, domain.AggMax:
+		current, ok := acc.group.Values[agg]
+		if !ok || current == nil {
+			acc.group.Values[agg] = value
+			return
+		}
+		// Values compare as queries sort them, dates and datetimes
+		// chronologically.
+		if (agg.Func == domain.AggMin && domain.CompareValues(value, current) < 0) || (agg.Func == domain.AggMax && domain.CompareValues(current, value) < 0) {
+			acc.group.Values[agg] = value
+		}
+	}
+}
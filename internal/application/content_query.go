@@ -0,0 +1,191 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// Query lists the entries of a collection model matching q. Previews match
+// draft versions and see draft fields, public readers only match and see the
+// published version of published fields. The query, and its conditions on
+// related entries, are restricted by policy to the entries and fields the
+// caller may read, see domain.Policy.RestrictQuery.
+func (cs *ContentService) Query(slug string, q domain.Query, preview bool, policy domain.Policy) (domain.EntryPage, error) {
+	model, err := cs.collection(slug)
+	if err != nil {
+		return domain.EntryPage{}, err
+	}
+	if q, err = policy.RestrictQuery(readAction(preview), slug, q); err != nil {
+		return domain.EntryPage{}, err
+	}
+
+	compiled, err := cs.compileQuery(model, q, preview, policy)
+	if err != nil {
+		return domain.EntryPage{}, err
+	}
+
+	page, err := cs.content.FindEntries(slug, compiled)
+	if err != nil {
+		return domain.EntryPage{}, err
+	}
+
+	for i, e := range page.Entries {
+		e = view(model, e, preview)
+		if len(q.Fields) > 0 {
+			e.Data = selectFields(e.Data, q.Fields)
+		}
+		page.Entries[i] = e
+	}
+	return page, nil
+}
+
+// compileQuery validates q against the model and converts it to the form
+// repositories evaluate: filter values are coerced to the stored types and
+// conditions on relation sub-fields are replaced with the IDs of the matching
+// related entries.
+func (cs *ContentService) compileQuery(model domain.Model, q domain.Query, preview bool, policy domain.Policy) (domain.Query, error) {
+	compiled := q
+	compiled.Published = !preview
+
+	if q.Limit < 0 || q.Offset < 0 {
+		return domain.Query{}, &domain.ValidationError{Field: "limit", Message: "limit and offset cannot be negative"}
+	}
+
+	if q.Filter != nil {
+		filter, err := cs.compileFilter(model, *q.Filter, preview, policy)
+		if err != nil {
+			return domain.Query{}, err
+		}
+		compiled.Filter = &filter
+	}
+
+	for _, k := range q.Sort {
+		if _, err := queryField(model, k.Field, preview); err != nil {
+			return domain.Query{}, &domain.ValidationError{Field: "sort", Message: err.Error()}
+		}
+	}
+
+	for _, name := range q.Fields {
+		if _, ok := visibleField(model, name, preview); !ok {
+			return domain.Query{}, &domain.ValidationError{Field: "fields", Message: fmt.Sprintf("unknown field '%s'", name)}
+		}
+	}
+
+	return compiled, nil
+}
+
+func (cs *ContentService) compileFilter(model domain.Model, f domain.Filter, preview bool, policy domain.Policy) (domain.Filter, error) {
+	if f.IsLogical() {
+		compiled := domain.Filter{}
+		for _, child := range f.And {
+			c, err := cs.compileFilter(model, child, preview, policy)
+			if err != nil {
+				return domain.Filter{}, err
+			}
+			compiled.And = append(compiled.And, c)
+		}
+		for _, child := range f.Or {
+			c, err := cs.compileFilter(model, child, preview, policy)
+			if err != nil {
+				return domain.Filter{}, err
+			}
+			compiled.Or = append(compiled.Or, c)
+		}
+		return compiled, nil
+	}
+
+	name, rest, nested := strings.Cut(f.Field, ".")
+	if nested {
+		return cs.compileRelationFilter(model, name, rest, f, preview, policy)
+	}
+
+	field, err := queryField(model, f.Field, preview)
+	if err != nil {
+		return domain.Filter{}, &domain.ValidationError{Field: "filter", Message: err.Error()}
+	}
+	value, err := domain.CoerceFilterValue(field, f.Op, f.Value)
+	if err != nil {
+		return domain.Filter{}, &domain.ValidationError{Field: "filter", Message: fmt.Sprintf("%s: %v", f.Field, err)}
+	}
+
+	return domain.Filter{Field: f.Field, Op: f.Op, Value: value}, nil
+}
+
+// compileRelationFilter resolves a condition on a sub-field of the entries
+// targeted by a relation field into an "in" condition on the relation field.
+// The condition is restricted by policy like a query on the target model, so
+// it only matches the entries, on the fields, the caller may read there.
+func (cs *ContentService) compileRelationFilter(model domain.Model, name, rest string, f domain.Filter, preview bool, policy domain.Policy) (domain.Filter, error) {
+	field, ok := visibleField(model, name, preview)
+	if !ok || field.Type != domain.FieldRelation {
+		return domain.Filter{}, &domain.ValidationError{Field: "filter", Message: fmt.Sprintf("%s: '%s' is not a relation field", f.Field, name)}
+	}
+
+	target, err := cs.model(field.Target)
+	if err != nil {
+		return domain.Filter{}, err
+	}
+
+	q, err := policy.RestrictQuery(readAction(preview), target.Slug, domain.Query{
+		Filter: &domain.Filter{Field: rest, Op: f.Op, Value: f.Value},
+	})
+	if err != nil {
+		return domain.Filter{}, err
+	}
+	sub, err := cs.compileFilter(target, *q.Filter, preview, policy)
+	if err != nil {
+		return domain.Filter{}, err
+	}
+
+	related, err := cs.content.FindEntries(target.Slug, domain.Query{Filter: &sub, Published: !preview})
+	if err != nil {
+		return domain.Filter{}, err
+	}
+
+	ids := make([]any, len(related.Entries))
+	for i, e := range related.Entries {
+		ids[i] = e.ID
+	}
+	return domain.Filter{Field: name, Op: domain.OpIn, Value: ids}, nil
+}
+
+// queryField returns a visible model field or system field.
+func queryField(model domain.Model, name string, preview bool) (domain.Field, error) {
+	if field, ok := visibleField(model, name, preview); ok {
+		return field, nil
+	}
+	if t, ok := domain.SystemFieldType(name); ok {
+		return domain.Field{Name: name, Type: t}, nil
+	}
+	return domain.Field{}, fmt.Errorf("unknown field '%s'", name)
+}
+
+func visibleField(model domain.Model, name string, preview bool) (domain.Field, bool) {
+	for _, f := range model.Fields {
+		if f.Name == name && f.IsVisible(preview) {
+			return f, true
+		}
+	}
+	return domain.Field{}, false
+}
+
+func selectFields(data map[string]any, fields []string) map[string]any {
+	selected := make(map[string]any, len(fields))
+	for _, name := range fields {
+		if value, ok := data[name]; ok {
+			selected[name] = value
+		}
+	}
+	return selected
+}
+
+// readAction is the action of reading the published or, in previews, the
+// draft version of entries.
+func readAction(preview bool) domain.Action {
+	if preview {
+		return domain.ActionPreview
+	}
+	return domain.ActionRead
+}
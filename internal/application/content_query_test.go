@@ -0,0 +1,157 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/axarus/vectrag/internal/infrastructure/filestore"
+)
+
+// newTestContentService returns a content service storing models and
+// entries in a temporary directory, holding the given models and entries.
+func newTestContentService(t *testing.T, models []domain.Model, entries []domain.Entry) (*ContentService, domain.Repository, domain.ContentRepository) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := filestore.NewYamlRepository(dir+"/models", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := filestore.NewJSONContentRepository(dir+"/content", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range models {
+		if err := repo.CreateModel(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range entries {
+		if err := content.CreateEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewContentService(repo, content, nil, nil, nil), repo, content
+}
+
+func testModel(slug string, fields ...domain.Field) domain.Model {
+	for i := range fields {
+		fields[i].ID = slug + "-" + fields[i].Name
+		if fields[i].Type == "" {
+			fields[i].Type = domain.FieldString
+		}
+		if fields[i].Status == "" {
+			fields[i].Status = domain.StatusPublish
+		}
+	}
+	return domain.Model{
+		ID:            slug,
+		Name:          slug,
+		Slug:          slug,
+		Kind:          domain.KindCollection,
+		Fields:        fields,
+		Status:        domain.StatusPublish,
+		SchemaVersion: 1,
+	}
+}
+
+func testEntry(model, id, owner string, data map[string]any) domain.Entry {
+	return domain.Entry{ID: id, Model: model, Data: data, Status: domain.StatusDraft, CreatedBy: owner}
+}
+
+func TestQueryRestrictsRelationFilters(t *testing.T) {
+	cs, _, _ := newTestContentService(t,
+		[]domain.Model{
+			testModel("authors", domain.Field{Name: "name"}, domain.Field{Name: "email"}),
+			testModel("posts", domain.Field{Name: "title"}, domain.Field{Name: "author", Type: domain.FieldRelation, Target: "authors"}),
+		},
+		[]domain.Entry{
+			testEntry("authors", "ada", "alice", map[string]any{"name": "Ada", "email": "ada@example.com"}),
+			testEntry("authors", "bob", "bob", map[string]any{"name": "Bob", "email": "bob@example.com"}),
+			testEntry("posts", "p1", "", map[string]any{"title": "One", "author": "ada"}),
+			testEntry("posts", "p2", "", map[string]any{"title": "Two", "author": "bob"}),
+		},
+	)
+
+	preview := []domain.Action{domain.ActionPreview}
+	posts := domain.Permission{Actions: preview, Models: []string{"posts"}}
+	tests := []struct {
+		name    string
+		policy  domain.Policy
+		filter  domain.Filter
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "full access",
+			policy: domain.FullAccess("alice"),
+			filter: domain.Filter{Field: "author.email", Op: domain.OpEq, Value: "bob@example.com"},
+			want:   []string{"p2"},
+		},
+		{
+			name:    "target model not readable",
+			policy:  domain.Policy{Permissions: []domain.Permission{posts}},
+			filter:  domain.Filter{Field: "author.email", Op: domain.OpEq, Value: "bob@example.com"},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name: "target field not readable",
+			policy: domain.Policy{Permissions: []domain.Permission{posts,
+				{Actions: preview, Models: []string{"authors"}, Fields: []string{"name"}}}},
+			filter:  domain.Filter{Field: "author.email", Op: domain.OpEq, Value: "bob@example.com"},
+			wantErr: domain.ErrForbidden,
+		},
+		{
+			name: "target field readable",
+			policy: domain.Policy{Permissions: []domain.Permission{posts,
+				{Actions: preview, Models: []string{"authors"}, Fields: []string{"name"}}}},
+			filter: domain.Filter{Field: "author.name", Op: domain.OpEq, Value: "Bob"},
+			want:   []string{"p2"},
+		},
+		{
+			name: "target entries of others hidden",
+			policy: domain.Policy{Owner: "alice", Permissions: []domain.Permission{posts,
+				{Actions: preview, Models: []string{"authors"}, Own: true}}},
+			filter: domain.Filter{Field: "author.email", Op: domain.OpContains, Value: "example.com"},
+			want:   []string{"p1"},
+		},
+		{
+			name: "nested in a group",
+			policy: domain.Policy{Permissions: []domain.Permission{posts,
+				{Actions: preview, Models: []string{"authors"}, Fields: []string{"name"}}}},
+			filter: domain.Filter{Or: []domain.Filter{
+				{Field: "title", Op: domain.OpEq, Value: "One"},
+				{Field: "author.email", Op: domain.OpEq, Value: "bob@example.com"},
+			}},
+			wantErr: domain.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			page, err := cs.Query("posts", domain.Query{Filter: &filter, Sort: []domain.SortKey{{Field: "title"}}}, true, tt.policy)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Query() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			var got []string
+			for _, e := range page.Entries {
+				got = append(got, e.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Query() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Query() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
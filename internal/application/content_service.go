@@ -0,0 +1,535 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+type ContentService struct {
+	models  domain.Repository
+	content domain.ContentRepository
+	events  EventPublisher
+	hooks   *Hooks
+	tx      Transactor
+}
+
+// NewContentService creates a content service publishing an event after each
+// change to events, and running the entry hooks of hooks around writes. Both
+// may be nil. Scheduled transitions are published by the Scheduler. After
+// hooks run once the transaction of tx writing the entry is committed, or
+// right after the write when tx is nil.
+func NewContentService(models domain.Repository, content domain.ContentRepository, events EventPublisher, hooks *Hooks, tx Transactor) *ContentService {
+	return &ContentService{
+		models:  models,
+		content: content,
+		events:  events,
+		hooks:   hooks,
+		tx:      tx,
+	}
+}
+
+// List returns the draft version of every entry, as seen in previews.
+func (cs *ContentService) List(slug string) ([]domain.Entry, error) {
+	model, err := cs.collection(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := cs.activeEntries(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, e := range entries {
+		entries[i] = view(model, e, true)
+	}
+	return entries, nil
+}
+
+// ListPublished returns the published version of every published entry, as
+// seen by public readers.
+func (cs *ContentService) ListPublished(slug string) ([]domain.Entry, error) {
+	model, err := cs.collection(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := cs.activeEntries(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	published := make([]domain.Entry, 0, len(entries))
+	for _, e := range entries {
+		if version, ok := e.PublishedVersion(); ok {
+			published = append(published, view(model, version, false))
+		}
+	}
+	return published, nil
+}
+
+func (cs *ContentService) Get(slug, id string) (domain.Entry, error) {
+	model, err := cs.collection(slug)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	entry, err := cs.activeEntry(slug, id)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+	return view(model, entry, true), nil
+}
+
+func (cs *ContentService) GetPublished(slug, id string) (domain.Entry, error) {
+	model, err := cs.collection(slug)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	entry, err := cs.activeEntry(slug, id)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+	return publishedVersion(model, entry)
+}
+
+// Create adds an entry and returns it as written, after the hooks ran.
+func (cs *ContentService) Create(entry domain.Entry) (domain.Entry, error) {
+	model, err := cs.collection(entry.Model)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+	return cs.create(model, entry)
+}
+
+func (cs *ContentService) create(model domain.Model, entry domain.Entry) (domain.Entry, error) {
+	hc := &HookContext{Action: HookCreate, Model: model, Entry: &entry}
+	if err := cs.before(hc); err != nil {
+		return domain.Entry{}, err
+	}
+	if entry.IsDeleted() {
+		return domain.Entry{}, &domain.ValidationError{Field: "Status", Message: "an entry cannot be created in the trash"}
+	}
+	if err := domain.ValidateWritableData(model, entry.Data); err != nil {
+		return domain.Entry{}, err
+	}
+	data, err := domain.EncodeData(model, entry.Data)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+	entry.Data = data
+	if err := cs.validate(model, entry); err != nil {
+		return domain.Entry{}, err
+	}
+	if err := cs.content.CreateEntry(entry); err != nil {
+		return domain.Entry{}, err
+	}
+	if err := cs.publish(domain.EventEntryCreated, entry); err != nil {
+		return domain.Entry{}, err
+	}
+	cs.after(hc)
+	return view(model, entry, true), nil
+}
+
+// Update replaces the draft data of an entry and returns it as written.
+// Data of deleted fields cannot be written and is carried over from the
+// stored entry.
+func (cs *ContentService) Update(entry domain.Entry) (domain.Entry, error) {
+	model, err := cs.collection(entry.Model)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	stored, err := cs.activeEntry(entry.Model, entry.ID)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+	return cs.update(model, stored, entry)
+}
+
+func (cs *ContentService) update(model domain.Model, stored, entry domain.Entry) (domain.Entry, error) {
+	hc := &HookContext{Action: HookUpdate, Model: model, Entry: &entry, Previous: stored}
+	if err := cs.before(hc); err != nil {
+		return domain.Entry{}, err
+	}
+	if err := cs.write(model, stored, &entry); err != nil {
+		return domain.Entry{}, err
+	}
+	if err := cs.publish(domain.EventEntryUpdated, entry); err != nil {
+		return domain.Entry{}, err
+	}
+	cs.after(hc)
+	return view(model, entry, true), nil
+}
+
+// Delete moves an entry to the trash. Use TrashService to restore or purge it.
+func (cs *ContentService) Delete(slug, id string) error {
+	model, err := cs.collection(slug)
+	if err != nil {
+		return err
+	}
+
+	entry, err := cs.activeEntry(slug, id)
+	if err != nil {
+		return err
+	}
+
+	hc := &HookContext{Action: HookDelete, Model: model, Entry: &entry, Previous: entry}
+	if err := cs.before(hc); err != nil {
+		return err
+	}
+	entry.Trash(time.Now().UTC())
+	if err := cs.content.UpdateEntry(entry); err != nil {
+		return err
+	}
+	if err := cs.publish(domain.EventEntryDeleted, entry); err != nil {
+		return err
+	}
+	cs.after(hc)
+	return nil
+}
+
+// GetSingle returns the draft version of the only entry of a single model.
+func (cs *ContentService) GetSingle(slug string) (domain.Entry, error) {
+	model, err := cs.single(slug)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	entry, err := cs.activeEntry(slug, slug)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+	return view(model, entry, true), nil
+}
+
+func (cs *ContentService) GetSinglePublished(slug string) (domain.Entry, error) {
+	model, err := cs.single(slug)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	entry, err := cs.activeEntry(slug, slug)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+	return publishedVersion(model, entry)
+}
+
+// PutSingle creates or replaces the only entry of a single model, and
+// returns it as written. The entry is stored under the model slug, callers
+// never see its ID. An entry in the trash must be restored before it is
+// replaced.
+func (cs *ContentService) PutSingle(entry domain.Entry) (domain.Entry, error) {
+	model, err := cs.single(entry.Model)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	entry.ID = model.Slug
+	stored, err := cs.content.GetEntry(model.Slug, entry.ID)
+	if errors.Is(err, domain.ErrEntryNotFound) {
+		return cs.create(model, entry)
+	} else if err != nil {
+		return domain.Entry{}, err
+	}
+	if stored.IsDeleted() {
+		return domain.Entry{}, fmt.Errorf("%w: %s is in the trash", domain.ErrEntryNotFound, entry.ID)
+	}
+	return cs.update(model, stored, entry)
+}
+
+// write stores entry over stored, keeping the data of deleted fields and the
+// creator of the entry, and updates entry to what was stored: its data is
+// encoded to the stored values.
+func (cs *ContentService) write(model domain.Model, stored domain.Entry, entry *domain.Entry) error {
+	if err := domain.ValidateWritableData(model, entry.Data); err != nil {
+		return err
+	}
+	entry.CreatedBy = stored.CreatedBy
+
+	data, err := domain.EncodeData(model, entry.Data)
+	if err != nil {
+		return err
+	}
+	for _, field := range model.Fields {
+		if value, ok := stored.Data[field.Name]; ok && field.IsDeleted() {
+			data[field.Name] = value
+		}
+	}
+	entry.Data = data
+
+	if err := cs.validate(model, *entry); err != nil {
+		return err
+	}
+	return cs.content.UpdateEntry(*entry)
+}
+
+// Publish makes the current draft of an entry publicly visible. The id is
+// ignored for single models.
+func (cs *ContentService) Publish(slug, id string, at time.Time) (domain.Entry, error) {
+	model, entry, err := cs.entry(slug, id)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	hc := &HookContext{Action: HookPublish, Model: model, Entry: &entry, Previous: entry}
+	if err := cs.before(hc); err != nil {
+		return domain.Entry{}, err
+	}
+	entry.Publish(at)
+	if err := cs.validate(model, entry); err != nil {
+		return domain.Entry{}, err
+	}
+	if err := cs.content.UpdateEntry(entry); err != nil {
+		return domain.Entry{}, err
+	}
+	if err := cs.publish(domain.EventEntryPublished, entry); err != nil {
+		return domain.Entry{}, err
+	}
+	cs.after(hc)
+	return view(model, entry, true), nil
+}
+
+// Unpublish removes the published version of an entry. The id is ignored for
+// single models.
+func (cs *ContentService) Unpublish(slug, id string) (domain.Entry, error) {
+	model, entry, err := cs.entry(slug, id)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	hc := &HookContext{Action: HookUnpublish, Model: model, Entry: &entry, Previous: entry}
+	if err := cs.before(hc); err != nil {
+		return domain.Entry{}, err
+	}
+	entry.Unpublish()
+	if err := cs.content.UpdateEntry(entry); err != nil {
+		return domain.Entry{}, err
+	}
+	if err := cs.publish(domain.EventEntryUnpublished, entry); err != nil {
+		return domain.Entry{}, err
+	}
+	cs.after(hc)
+	return view(model, entry, true), nil
+}
+
+// SetSchedule records the pending transitions of an entry. Use a Scheduler to
+// have them applied. The id is ignored for single models.
+func (cs *ContentService) SetSchedule(slug, id string, publishAt, unpublishAt time.Time) (domain.Entry, error) {
+	model, entry, err := cs.entry(slug, id)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	entry.PublishAt = publishAt
+	entry.UnpublishAt = unpublishAt
+	if err := cs.content.UpdateEntry(entry); err != nil {
+		return domain.Entry{}, err
+	}
+	return view(model, entry, true), nil
+}
+
+// ApplyScheduled performs a due scheduled transition and clears it from the
+// entry. It reports false without changing anything when the entry no longer
+// has the job scheduled, e.g. because it was rescheduled in the meantime.
+func (cs *ContentService) ApplyScheduled(job domain.ScheduledJob, at time.Time) (bool, error) {
+	model, entry, err := cs.entry(job.Model, job.EntryID)
+	if err != nil {
+		return false, err
+	}
+
+	hc := &HookContext{Action: HookPublish, Model: model, Entry: &entry, Previous: entry, Scheduled: true}
+	if job.Action == domain.ScheduleUnpublish {
+		hc.Action = HookUnpublish
+	}
+
+	switch job.Action {
+	case domain.SchedulePublish:
+		if !entry.PublishAt.Equal(job.At) {
+			return false, nil
+		}
+		if err := cs.before(hc); err != nil {
+			return false, err
+		}
+		entry.Publish(at)
+		entry.PublishAt = time.Time{}
+		if err := cs.validate(model, entry); err != nil {
+			return false, err
+		}
+	case domain.ScheduleUnpublish:
+		if !entry.UnpublishAt.Equal(job.At) {
+			return false, nil
+		}
+		if err := cs.before(hc); err != nil {
+			return false, err
+		}
+		entry.Unpublish()
+		entry.UnpublishAt = time.Time{}
+	default:
+		return false, fmt.Errorf("unknown schedule action '%s'", job.Action)
+	}
+
+	if err := cs.content.UpdateEntry(entry); err != nil {
+		return false, err
+	}
+	cs.after(hc)
+	return true, nil
+}
+
+func (cs *ContentService) publish(t domain.EventType, entry domain.Entry) error {
+	return publish(cs.events, domain.Event{Type: t, Model: entry.Model, EntryID: entry.ID})
+}
+
+func (cs *ContentService) entry(slug, id string) (domain.Model, domain.Entry, error) {
+	model, err := cs.model(slug)
+	if err != nil {
+		return domain.Model{}, domain.Entry{}, err
+	}
+	if model.Kind == domain.KindSingle {
+		id = model.Slug
+	}
+
+	entry, err := cs.activeEntry(slug, id)
+	if err != nil {
+		return domain.Model{}, domain.Entry{}, err
+	}
+	return model, entry, nil
+}
+
+func (cs *ContentService) activeEntries(slug string) ([]domain.Entry, error) {
+	entries, err := cs.content.GetEntries(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]domain.Entry, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDeleted() {
+			active = append(active, e)
+		}
+	}
+	return active, nil
+}
+
+func (cs *ContentService) activeEntry(slug, id string) (domain.Entry, error) {
+	entry, err := cs.content.GetEntry(slug, id)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+	if entry.IsDeleted() {
+		return domain.Entry{}, fmt.Errorf("%w: %s is in the trash", domain.ErrEntryNotFound, id)
+	}
+	return entry, nil
+}
+
+func publishedVersion(model domain.Model, entry domain.Entry) (domain.Entry, error) {
+	version, ok := entry.PublishedVersion()
+	if !ok {
+		return domain.Entry{}, fmt.Errorf("%w: %s is not published", domain.ErrEntryNotFound, entry.ID)
+	}
+	return view(model, version, false), nil
+}
+
+// view restricts the data of an entry to the fields visible to the reader,
+// decoding the stored values.
+func view(model domain.Model, entry domain.Entry, preview bool) domain.Entry {
+	entry.Data = domain.DecodeData(model, model.VisibleData(entry.Data, preview))
+	return entry
+}
+
+func (cs *ContentService) collection(slug string) (domain.Model, error) {
+	model, err := cs.model(slug)
+	if err != nil {
+		return domain.Model{}, err
+	}
+	if model.Kind == domain.KindSingle {
+		return domain.Model{}, &domain.ValidationError{Message: fmt.Sprintf("model '%s' is a single model", slug)}
+	}
+	return model, nil
+}
+
+func (cs *ContentService) single(slug string) (domain.Model, error) {
+	model, err := cs.model(slug)
+	if err != nil {
+		return domain.Model{}, err
+	}
+	if model.Kind != domain.KindSingle {
+		return domain.Model{}, &domain.ValidationError{Message: fmt.Sprintf("model '%s' is not a single model", slug)}
+	}
+	return model, nil
+}
+
+func (cs *ContentService) model(slug string) (domain.Model, error) {
+	model, err := cs.models.GetModel(slug)
+	if err != nil || model.IsDeleted() {
+		return domain.Model{}, fmt.Errorf("%w: %s", domain.ErrModelNotFound, slug)
+	}
+	return model, nil
+}
+
+func (cs *ContentService) validate(model domain.Model, entry domain.Entry) error {
+	if err := domain.ValidateEntry(model, entry); err != nil {
+		return err
+	}
+
+	var unique []domain.Field
+	for _, field := range model.Fields {
+		if field.Unique {
+			unique = append(unique, field)
+		}
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+
+	entries, err := cs.content.GetEntries(model.Slug)
+	if err != nil {
+		return err
+	}
+	for _, field := range unique {
+		value, ok := entry.Data[field.Name]
+		if !ok || value == nil {
+			continue
+		}
+		for _, other := range entries {
+			if other.ID != entry.ID && !other.IsDeleted() && other.Data[field.Name] == value {
+				return &domain.ValidationError{
+					Field:   field.Name,
+					Message: "value must be unique",
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// before runs the before hooks of hc, which may change the entry but not
+// move it to another ID or model.
+func (cs *ContentService) before(hc *HookContext) error {
+	id, model := hc.Entry.ID, hc.Entry.Model
+	if err := cs.hooks.before(hc); err != nil {
+		return err
+	}
+	if hc.Entry.ID != id || hc.Entry.Model != model {
+		return fmt.Errorf("hooks: before %s of %s/%s changed the ID or the model of the entry", hc.Action, model, id)
+	}
+	return nil
+}
+
+// after runs the after hooks of hc once the write is committed, so they
+// neither hold the project nor run for writes that are rolled back.
+func (cs *ContentService) after(hc *HookContext) {
+	if cs.tx == nil {
+		cs.hooks.after(hc)
+		return
+	}
+	cs.tx.AfterCommit(func() {
+		cs.hooks.after(hc)
+	})
+}
@@ -0,0 +1,30 @@
+package application
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+func TestPutSingleInTrash(t *testing.T) {
+	settings := testModel("settings", domain.Field{Name: "title"})
+	settings.Kind = domain.KindSingle
+	entry := testEntry("settings", "settings", "", map[string]any{"title": "Draft"})
+	entry.Publish(time.Now())
+	entry.Trash(time.Now())
+	cs, _, content := newTestContentService(t, []domain.Model{settings}, []domain.Entry{entry})
+
+	_, err := cs.PutSingle(domain.Entry{Model: "settings", Data: map[string]any{"title": "New"}})
+	if !errors.Is(err, domain.ErrEntryNotFound) {
+		t.Fatalf("PutSingle() error = %v, want %v", err, domain.ErrEntryNotFound)
+	}
+	stored, err := content.GetEntry("settings", "settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored.IsDeleted() || stored.Published["title"] != "Draft" {
+		t.Errorf("stored entry = %+v, want it unchanged in the trash", stored)
+	}
+}
@@ -23,15 +23,25 @@ type APIRoutesProvider interface {
 	Register(mux *http.ServeMux) error
 }
 
+// BackgroundWorker runs alongside the server until ctx is cancelled.
+type BackgroundWorker interface {
+	Run(ctx context.Context)
+}
+
+type WorkersProvider interface {
+	Workers() ([]BackgroundWorker, error)
+}
+
 type DevelopService struct {
 	listener ListenerProvider
 	server   ServerStarter
 	admin    AdminHandlerProvider
 	api      APIRoutesProvider
+	workers  WorkersProvider
 }
 
-func NewDevelopService(listener ListenerProvider, server ServerStarter, admin AdminHandlerProvider, api APIRoutesProvider) *DevelopService {
-	return &DevelopService{listener: listener, server: server, admin: admin, api: api}
+func NewDevelopService(listener ListenerProvider, server ServerStarter, admin AdminHandlerProvider, api APIRoutesProvider, workers WorkersProvider) *DevelopService {
+	return &DevelopService{listener: listener, server: server, admin: admin, api: api, workers: workers}
 }
 
 func (s *DevelopService) Start(basePort int, host string) (url string, shutdown func(ctx context.Context) error, err error) {
@@ -55,7 +65,24 @@ func (s *DevelopService) Start(basePort int, host string) (url string, shutdown
 		return "", nil, registerErr
 	}
 
+	var workers []BackgroundWorker
+	if s.workers != nil {
+		workers, err = s.workers.Workers()
+		if err != nil {
+			_ = srv.Shutdown(context.Background())
+			return "", nil, err
+		}
+	}
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	for _, w := range workers {
+		go w.Run(workersCtx)
+	}
+
 	url = fmt.Sprintf("http://%s:%d", host, port)
-	shutdown = srv.Shutdown
+	shutdown = func(ctx context.Context) error {
+		stopWorkers()
+		return srv.Shutdown(ctx)
+	}
 	return url, shutdown, nil
 }
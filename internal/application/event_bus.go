@@ -0,0 +1,56 @@
+package application
+
+import (
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+type EventPublisher interface {
+	Publish(event domain.Event) error
+}
+
+// EventBus delivers events synchronously to every subscriber, in the order
+// they subscribed, stopping at the first subscriber failing.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers []func(domain.Event) error
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+func (b *EventBus) Subscribe(fn func(domain.Event) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, fn)
+}
+
+func (b *EventBus) Publish(event domain.Event) error {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publish sends e to events, stamped with the current time. Services built
+// without a publisher, such as by read-only commands, pass a nil events. The
+// event is recorded in the transaction of the write it reports, which fails
+// with it.
+func publish(events EventPublisher, e domain.Event) error {
+	if events == nil {
+		return nil
+	}
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now().UTC()
+	}
+	return events.Publish(e)
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/axarus/vectrag/internal/infrastructure/filestore"
+)
+
+func TestPublishFailureRollsBackWrite(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name    string
+		fail    bool
+		wantErr error
+		want    int
+	}{
+		{name: "recorded", want: 1},
+		{name: "not recorded", fail: true, wantErr: errFailed, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			lock, err := filestore.NewFileLock(filepath.Join(dir, "lock"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			journal, err := filestore.NewJournal(filepath.Join(dir, "journal.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tx := filestore.NewTxLock(lock, journal)
+			models, err := filestore.NewYamlRepository(filepath.Join(dir, "models"), journal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := filestore.NewJSONContentRepository(filepath.Join(dir, "content"), journal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			changes, err := filestore.NewJSONChangeRepository(filepath.Join(dir, "changes.json"), journal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tx.Tx(func() error { return models.CreateModel(testModel("posts", domain.Field{Name: "title"})) }); err != nil {
+				t.Fatal(err)
+			}
+
+			events := NewEventBus()
+			events.Subscribe(NewChangeFeed(changes, 0, nil).Publish)
+			events.Subscribe(func(domain.Event) error {
+				if tt.fail {
+					return errFailed
+				}
+				return nil
+			})
+			cs := NewContentService(models, content, events, nil, tx)
+
+			err = tx.Tx(func() error {
+				_, err := cs.Create(testEntry("posts", "p1", "", map[string]any{"title": "One"}))
+				return err
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var entries []domain.Entry
+			var logged []domain.Change
+			err = tx.Tx(func() error {
+				page, err := content.FindEntries("posts", domain.Query{})
+				if err != nil {
+					return err
+				}
+				entries = page.Entries
+				logged, _, err = changes.GetChanges(0)
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.want || len(logged) != tt.want {
+				t.Errorf("stored %d entries and %d changes, want %d of each", len(entries), len(logged), tt.want)
+			}
+		})
+	}
+}
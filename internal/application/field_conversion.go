@@ -0,0 +1,215 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/google/uuid"
+)
+
+// TypeChangeOptions allow changes of field types that are not safe.
+type TypeChangeOptions struct {
+	Force bool
+	// Fallbacks holds, by field name, the value replacing the values that
+	// cannot be converted. Forced changes require one.
+	Fallbacks map[string]any
+}
+
+// FieldConversion reports the conversion of the values of a field whose type
+// changes.
+type FieldConversion struct {
+	Field  string
+	From   domain.FieldType
+	To     domain.FieldType
+	Safety domain.ConversionSafety
+	// Converted counts the values converted, Failures lists the values that
+	// cannot be, which are replaced by the fallback of forced changes.
+	Converted int
+	Failures  []ConversionFailure
+}
+
+type ConversionFailure struct {
+	EntryID string
+	// Published tells whether the value is in the published version of the
+	// entry rather than its draft.
+	Published bool
+	Value     any
+	Error     string
+}
+
+// TypeChangePlan holds the conversions needed to update a model, and the
+// entries once converted.
+type TypeChangePlan struct {
+	Model       string
+	Conversions []FieldConversion
+	entries     []domain.Entry
+}
+
+// PlanTypeChanges converts the entries of existing to the field types of
+// updated, without saving them. Fields are matched by ID. Changes that are
+// not safe, or that fail to convert some values to values valid for the
+// updated field, return an error wrapping domain.ErrUnsafeTypeChange unless
+// forced with a fallback value. Converted values of unique fields must stay
+// unique, even when forced. The plan is returned along with that error so the
+// conversions can be reported.
+func (s *MigrationService) PlanTypeChanges(existing, updated domain.Model, opts TypeChangeOptions) (*TypeChangePlan, error) {
+	plan := &TypeChangePlan{Model: existing.Slug}
+
+	previous := make(map[string]domain.Field, len(existing.Fields))
+	for _, f := range existing.Fields {
+		previous[f.ID] = f
+	}
+	var changed []domain.Field
+	var fromTypes []domain.FieldType
+	for _, f := range updated.Fields {
+		prev, ok := previous[f.ID]
+		if !ok || prev.Type == f.Type {
+			continue
+		}
+		changed = append(changed, f)
+		fromTypes = append(fromTypes, prev.Type)
+	}
+	if len(changed) == 0 {
+		return plan, nil
+	}
+
+	entries, err := s.content.GetEntries(existing.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []string
+	for i, field := range changed {
+		from := fromTypes[i]
+		conv := FieldConversion{
+			Field:  field.Name,
+			From:   from,
+			To:     field.Type,
+			Safety: domain.ClassifyTypeChange(from, field.Type),
+		}
+
+		fallback, hasFallback := opts.Fallbacks[field.Name]
+		if hasFallback {
+			if err := domain.ValidateValue(field, fallback); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: fallback %v", field.Name, err))
+			}
+		}
+
+		for j := range entries {
+			for _, published := range []bool{false, true} {
+				data := entries[j].Data
+				if published {
+					data = entries[j].Published
+				}
+				value, ok := data[field.Name]
+				if !ok {
+					continue
+				}
+
+				converted, err := domain.ConvertValue(value, from, field.Type)
+				if err == nil {
+					err = domain.ValidateValue(field, converted)
+				}
+				if err != nil {
+					conv.Failures = append(conv.Failures, ConversionFailure{
+						EntryID:   entries[j].ID,
+						Published: published,
+						Value:     value,
+						Error:     err.Error(),
+					})
+					converted = fallback
+				} else {
+					conv.Converted++
+				}
+				data[field.Name] = converted
+			}
+		}
+		plan.Conversions = append(plan.Conversions, conv)
+
+		if field.Unique {
+			if dup, ok := duplicateValue(entries, field.Name); ok {
+				errs = append(errs, fmt.Sprintf("%s: converted values must be unique, %v is held by several entries", field.Name, dup))
+			}
+		}
+
+		if conv.Safety == domain.ConversionSafe && len(conv.Failures) == 0 {
+			continue
+		}
+		if !opts.Force || !hasFallback {
+			errs = append(errs, fmt.Sprintf("changing %s from %s to %s is %s (%d of %d values cannot be converted), force the change with a fallback value",
+				field.Name, from, field.Type, conv.Safety, len(conv.Failures), conv.Converted+len(conv.Failures)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return plan, fmt.Errorf("%w: %s", domain.ErrUnsafeTypeChange, strings.Join(errs, "; "))
+	}
+	plan.entries = entries
+	return plan, nil
+}
+
+// CheckKindChange rejects changing the kind of a model holding entries,
+// including trashed ones: collection entries and the entry of a single model
+// are not stored under the same IDs.
+func (s *MigrationService) CheckKindChange(existing, updated domain.Model) error {
+	if existing.Kind == updated.Kind {
+		return nil
+	}
+	entries, err := s.content.GetEntries(existing.Slug)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return &domain.ValidationError{
+			Field:   "Kind",
+			Message: fmt.Sprintf("cannot change from %s to %s while the model has entries", existing.Kind, updated.Kind),
+		}
+	}
+	return nil
+}
+
+// duplicateValue returns a value of field held by several entries that are
+// not trashed, such as the fallback replacing several values.
+func duplicateValue(entries []domain.Entry, field string) (any, bool) {
+	seen := make(map[any]bool, len(entries))
+	for _, entry := range entries {
+		value, ok := entry.Data[field]
+		if !ok || value == nil || entry.IsDeleted() {
+			continue
+		}
+		if seen[value] {
+			return value, true
+		}
+		seen[value] = true
+	}
+	return nil, false
+}
+
+// ApplyTypeChanges saves the entries converted by plan and records the
+// changes in the migration history.
+func (s *MigrationService) ApplyTypeChanges(plan *TypeChangePlan) error {
+	for _, entry := range plan.entries {
+		if err := s.content.UpdateEntry(entry); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, conv := range plan.Conversions {
+		err := s.migrations.AddMigration(domain.Migration{
+			ID:    uuid.New().String(),
+			Type:  domain.MigrationChangeFieldType,
+			Model: plan.Model,
+			Field: conv.Field,
+			From:  string(conv.From),
+			To:    string(conv.To),
+			At:    now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package application
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+func TestPlanTypeChanges(t *testing.T) {
+	published := testEntry("items", "b", "", map[string]any{"code": "2"})
+	published.Publish(published.CreatedAt)
+	published.Data["code"] = "two"
+	entries := []domain.Entry{
+		testEntry("items", "a", "", map[string]any{"code": "1"}),
+		published,
+	}
+
+	tests := []struct {
+		name      string
+		from, to  domain.FieldType
+		unique    bool
+		opts      TypeChangeOptions
+		wantErr   bool
+		wantSafe  domain.ConversionSafety
+		wantFails int
+		wantData  map[string]any
+	}{
+		{
+			name:     "safe",
+			from:     domain.FieldString,
+			to:       domain.FieldText,
+			wantSafe: domain.ConversionSafe,
+			wantData: map[string]any{"a": "1", "b": "two", "b published": "2"},
+		},
+		{
+			name:      "lossy without force",
+			from:      domain.FieldString,
+			to:        domain.FieldNumber,
+			wantErr:   true,
+			wantSafe:  domain.ConversionLossy,
+			wantFails: 1,
+		},
+		{
+			name:      "forced without fallback",
+			from:      domain.FieldString,
+			to:        domain.FieldNumber,
+			opts:      TypeChangeOptions{Force: true},
+			wantErr:   true,
+			wantSafe:  domain.ConversionLossy,
+			wantFails: 1,
+		},
+		{
+			name:      "forced with fallback",
+			from:      domain.FieldString,
+			to:        domain.FieldNumber,
+			opts:      TypeChangeOptions{Force: true, Fallbacks: map[string]any{"code": 0.0}},
+			wantSafe:  domain.ConversionLossy,
+			wantFails: 1,
+			wantData:  map[string]any{"a": 1.0, "b": 0.0, "b published": 2.0},
+		},
+		{
+			name:      "forced fallback must be valid",
+			from:      domain.FieldString,
+			to:        domain.FieldNumber,
+			opts:      TypeChangeOptions{Force: true, Fallbacks: map[string]any{"code": "zero"}},
+			wantErr:   true,
+			wantSafe:  domain.ConversionLossy,
+			wantFails: 1,
+		},
+		{
+			name:      "forced fallback must keep unique values",
+			from:      domain.FieldString,
+			to:        domain.FieldNumber,
+			unique:    true,
+			opts:      TypeChangeOptions{Force: true, Fallbacks: map[string]any{"code": 1.0}},
+			wantErr:   true,
+			wantSafe:  domain.ConversionLossy,
+			wantFails: 1,
+		},
+		{
+			name:      "impossible",
+			from:      domain.FieldString,
+			to:        domain.FieldRelation,
+			wantErr:   true,
+			wantSafe:  domain.ConversionImpossible,
+			wantFails: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := testModel("items", domain.Field{Name: "code", Type: tt.from, Unique: tt.unique})
+			updated := testModel("items", domain.Field{Name: "code", Type: tt.to, Unique: tt.unique, Target: "items"})
+			stored := make([]domain.Entry, len(entries))
+			for i, e := range entries {
+				stored[i] = e
+				stored[i].Data = maps.Clone(e.Data)
+				stored[i].Published = maps.Clone(e.Published)
+			}
+			_, models, content := newTestContentService(t, []domain.Model{existing}, stored)
+			s := NewMigrationService(models, content, nil, nil, nil, nil, 0, nil)
+
+			plan, err := s.PlanTypeChanges(existing, updated, tt.opts)
+			if tt.wantErr != errors.Is(err, domain.ErrUnsafeTypeChange) {
+				t.Fatalf("PlanTypeChanges() error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(plan.Conversions) != 1 {
+				t.Fatalf("Conversions = %+v, want one", plan.Conversions)
+			}
+			conv := plan.Conversions[0]
+			if conv.Safety != tt.wantSafe || len(conv.Failures) != tt.wantFails || conv.Converted+len(conv.Failures) != 3 {
+				t.Errorf("conversion = %+v, want %s with %d failures of 3 values", conv, tt.wantSafe, tt.wantFails)
+			}
+
+			if tt.wantErr {
+				if len(plan.entries) != 0 {
+					t.Errorf("plan of a rejected change holds %d entries", len(plan.entries))
+				}
+				return
+			}
+			got := map[string]any{}
+			for _, e := range plan.entries {
+				got[e.ID] = e.Data["code"]
+				if e.Published != nil {
+					got[e.ID+" published"] = e.Published["code"]
+				}
+			}
+			for k, want := range tt.wantData {
+				if got[k] != want {
+					t.Errorf("converted %s = %#v, want %#v", k, got[k], want)
+				}
+			}
+
+			// Planning does not save the converted entries.
+			saved, err := content.GetEntry("items", "a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.Data["code"] != "1" {
+				t.Errorf("saved value = %#v, want the value before the change", saved.Data["code"])
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package application
+
+import (
+	"log"
+	"sync"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// HookAction is the kind of write an entry hook runs around.
+type HookAction string
+
+const (
+	HookCreate    HookAction = "create"
+	HookUpdate    HookAction = "update"
+	HookDelete    HookAction = "delete"
+	HookPublish   HookAction = "publish"
+	HookUnpublish HookAction = "unpublish"
+)
+
+// AllModels registers a hook for the entries of every model.
+const AllModels = "*"
+
+type hookStage int
+
+const (
+	hookBefore hookStage = iota
+	hookAfter
+)
+
+// EntryHook runs custom code around the writes of entries, see Hooks.
+type EntryHook func(hc *HookContext) error
+
+// HookContext describes the write an EntryHook runs around.
+type HookContext struct {
+	Action HookAction
+	Model  domain.Model
+	// Entry is the entry written. Before hooks may change it, its Data in
+	// particular, and the change is validated and written; changing its ID
+	// or Model fails the write. It is the entry
+	// moved to the trash for deletions. Its Data holds the values as stored,
+	// encoded by their field type, except for the before hooks of creations
+	// and updates, which see the values written by the client.
+	Entry *domain.Entry
+	// Previous is the stored entry being replaced, the zero Entry when it
+	// is created.
+	Previous domain.Entry
+	// Scheduled reports whether a scheduled transition caused the write.
+	Scheduled bool
+}
+
+// Hooks holds the entry hooks of a project, by action and model. Before
+// hooks run in the order they were registered, before the entry is
+// validated, and abort the write when one fails: a *domain.ValidationError
+// is reported to API clients as a bad request. They run holding the project
+// lock, in the transaction of the write, so they must not take long nor
+// write through the services of the project. After hooks run once the write
+// is committed and the lock released, and not for writes that fail; their
+// errors are logged, since the write is already made.
+type Hooks struct {
+	mu    sync.RWMutex
+	hooks map[hookKey][]EntryHook
+}
+
+type hookKey struct {
+	stage  hookStage
+	action HookAction
+	model  string
+}
+
+func NewHooks() *Hooks {
+	return &Hooks{hooks: make(map[hookKey][]EntryHook)}
+}
+
+// BeforeCreate registers fn to run before the entries of model, or of every
+// model for AllModels, are created.
+func (h *Hooks) BeforeCreate(model string, fn EntryHook) {
+	h.register(hookBefore, HookCreate, model, fn)
+}
+
+// AfterCreate registers fn to run after the entries of model are created.
+func (h *Hooks) AfterCreate(model string, fn EntryHook) {
+	h.register(hookAfter, HookCreate, model, fn)
+}
+
+// BeforeUpdate registers fn to run before the drafts of the entries of model
+// are replaced.
+func (h *Hooks) BeforeUpdate(model string, fn EntryHook) {
+	h.register(hookBefore, HookUpdate, model, fn)
+}
+
+// AfterUpdate registers fn to run after the drafts of the entries of model
+// are replaced.
+func (h *Hooks) AfterUpdate(model string, fn EntryHook) {
+	h.register(hookAfter, HookUpdate, model, fn)
+}
+
+// BeforeDelete registers fn to run before the entries of model are moved to
+// the trash.
+func (h *Hooks) BeforeDelete(model string, fn EntryHook) {
+	h.register(hookBefore, HookDelete, model, fn)
+}
+
+// AfterDelete registers fn to run after the entries of model are moved to
+// the trash.
+func (h *Hooks) AfterDelete(model string, fn EntryHook) {
+	h.register(hookAfter, HookDelete, model, fn)
+}
+
+// BeforePublish registers fn to run before the entries of model are
+// published, including by the scheduler. Changes to the entry are part of
+// the published version.
+func (h *Hooks) BeforePublish(model string, fn EntryHook) {
+	h.register(hookBefore, HookPublish, model, fn)
+}
+
+// AfterPublish registers fn to run after the entries of model are published.
+func (h *Hooks) AfterPublish(model string, fn EntryHook) {
+	h.register(hookAfter, HookPublish, model, fn)
+}
+
+// BeforeUnpublish registers fn to run before the entries of model are
+// unpublished, including by the scheduler.
+func (h *Hooks) BeforeUnpublish(model string, fn EntryHook) {
+	h.register(hookBefore, HookUnpublish, model, fn)
+}
+
+// AfterUnpublish registers fn to run after the entries of model are
+// unpublished.
+func (h *Hooks) AfterUnpublish(model string, fn EntryHook) {
+	h.register(hookAfter, HookUnpublish, model, fn)
+}
+
+func (h *Hooks) register(stage hookStage, action HookAction, model string, fn EntryHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := hookKey{stage: stage, action: action, model: model}
+	h.hooks[key] = append(h.hooks[key], fn)
+}
+
+// before runs the before hooks of hc, the hooks of every model first, and
+// returns the error of the first failing one. A nil Hooks has no hooks.
+func (h *Hooks) before(hc *HookContext) error {
+	for _, fn := range h.lookup(hookBefore, hc) {
+		if err := fn(hc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// after runs the after hooks of hc, logging their errors.
+func (h *Hooks) after(hc *HookContext) {
+	for _, fn := range h.lookup(hookAfter, hc) {
+		if err := fn(hc); err != nil {
+			log.Printf("hooks: after %s of %s/%s: %v", hc.Action, hc.Model.Slug, hc.Entry.ID, err)
+		}
+	}
+}
+
+func (h *Hooks) lookup(stage hookStage, hc *HookContext) []EntryHook {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	all := h.hooks[hookKey{stage: stage, action: hc.Action, model: AllModels}]
+	own := h.hooks[hookKey{stage: stage, action: hc.Action, model: hc.Model.Slug}]
+	return append(all[:len(all):len(all)], own...)
+}
@@ -3,19 +3,94 @@ package application
 import (
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"text/template"
 )
 
 //go:embed resources/init/*
 var initTemplates embed.FS
 
+//go:embed resources/templates
+var projectTemplates embed.FS
+
 // InitConfig holds the configuration for project initialization
 type InitConfig struct {
 	ProjectName string
 	Port        string
-	Database    string
+	// Database is one of Databases, see NormalizeDatabase.
+	Database string
+	// Template is the starter kit scaffolded in the project, one of
+	// ProjectTemplates. Empty creates an empty project.
+	Template string
+}
+
+// Databases are the identifiers of the supported databases.
+var Databases = []string{"postgresql", "mysql", "sqlite"}
+
+var databaseAliases = map[string]string{
+	"postgres": "postgresql",
+	"pg":       "postgresql",
+	"sqlite3":  "sqlite",
+}
+
+// NormalizeDatabase returns the identifier of a database given its name in
+// any case, e.g. "PostgreSQL" or "postgres" for postgresql.
+func NormalizeDatabase(name string) (string, error) {
+	id := strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := databaseAliases[id]; ok {
+		id = alias
+	}
+	for _, db := range Databases {
+		if id == db {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported database %q, supported databases: %s", name, strings.Join(Databases, ", "))
+}
+
+// ProjectTemplates are the starter kits InitializeProject can scaffold:
+// models with seed content.
+var ProjectTemplates = []string{"blog", "ecommerce", "docs-rag"}
+
+func ValidateProjectName(name string) error {
+	if name == "" {
+		return fmt.Errorf("project name cannot be empty")
+	}
+	if strings.Contains(name, " ") {
+		return fmt.Errorf("project name cannot contain spaces")
+	}
+	return nil
+}
+
+func ValidatePort(port string) error {
+	if port == "" {
+		return fmt.Errorf("port cannot be empty")
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("port must be a number")
+	}
+	if n < 1 || n > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+	return nil
+}
+
+func validateTemplate(name string) error {
+	if name == "" {
+		return nil
+	}
+	for _, t := range ProjectTemplates {
+		if name == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown template %q, available templates: %s", name, strings.Join(ProjectTemplates, ", "))
 }
 
 // InitService handles project initialization
@@ -27,6 +102,21 @@ func NewInitService() *InitService {
 
 // InitializeProject creates a new VectraG project structure
 func (s *InitService) InitializeProject(path string, config InitConfig) error {
+	if err := ValidateProjectName(config.ProjectName); err != nil {
+		return err
+	}
+	if err := ValidatePort(config.Port); err != nil {
+		return err
+	}
+	database, err := NormalizeDatabase(config.Database)
+	if err != nil {
+		return err
+	}
+	config.Database = database
+	if err := validateTemplate(config.Template); err != nil {
+		return err
+	}
+
 	// Resolve absolute path
 	absPath, err := filepath.Abs(path)
 	if err != nil {
@@ -67,6 +157,12 @@ func (s *InitService) InitializeProject(path string, config InitConfig) error {
 		return fmt.Errorf("failed to generate config files: %w", err)
 	}
 
+	if config.Template != "" {
+		if err := s.scaffoldTemplate(workDir, config.Template); err != nil {
+			return fmt.Errorf("failed to scaffold template %s: %w", config.Template, err)
+		}
+	}
+
 	return nil
 }
 
@@ -137,3 +233,36 @@ func (s *InitService) generateFile(targetDir, fileName, templateName string, con
 
 	return nil
 }
+
+// scaffoldTemplate copies the models and seed content of a starter kit to
+// the directories set up by the generated vectrag.config.yaml.
+func (s *InitService) scaffoldTemplate(basePath, name string) error {
+	targets := map[string]string{
+		"models":  "models",
+		"content": defaultContentPath,
+	}
+	root := path.Join("resources/templates", name)
+
+	for dir, target := range targets {
+		files, err := fs.ReadDir(projectTemplates, path.Join(root, dir))
+		if err != nil {
+			return err
+		}
+		targetDir := filepath.Join(basePath, filepath.FromSlash(target))
+		if err := os.MkdirAll(targetDir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", target, err)
+		}
+
+		for _, f := range files {
+			data, err := projectTemplates.ReadFile(path.Join(root, dir, f.Name()))
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(targetDir, f.Name()), data, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", f.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
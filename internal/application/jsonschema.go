@@ -0,0 +1,344 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/google/uuid"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Keywords describing what standard JSON Schema keywords cannot express.
+const (
+	jsonSchemaKind    = "x-vectrag-kind"
+	jsonSchemaSlug    = "x-vectrag-slug"
+	jsonSchemaStatus  = "x-vectrag-status"
+	jsonSchemaType    = "x-vectrag-type"
+	jsonSchemaTarget  = "x-vectrag-target"
+	jsonSchemaUnique  = "x-vectrag-unique"
+	jsonSchemaOptions = "x-vectrag-options"
+)
+
+// FieldJSONSchema describes the values of a field as a JSON Schema. Fields
+// that are not required accept null.
+func FieldJSONSchema(f domain.Field) map[string]any {
+	schema := map[string]any{}
+	var t string
+	switch f.Type {
+	case domain.FieldNumber:
+		t = "number"
+	case domain.FieldBoolean:
+		t = "boolean"
+	case domain.FieldDate:
+		t = "string"
+		schema["format"] = "date"
+	case domain.FieldDateTime:
+		t = "string"
+		schema["format"] = "date-time"
+	case domain.FieldRelation:
+		t = "string"
+		schema[jsonSchemaTarget] = f.Target
+	case domain.FieldText:
+		t = "string"
+		schema[jsonSchemaType] = string(domain.FieldText)
+	case domain.FieldString:
+		t = "string"
+	default:
+		t = string(domain.FieldValueType(f.Type))
+		schema[jsonSchemaType] = string(f.Type)
+	}
+	if len(f.Options) > 0 {
+		schema[jsonSchemaOptions] = f.Options
+	}
+
+	if f.Required {
+		schema["type"] = t
+	} else {
+		schema["type"] = []string{t, "null"}
+	}
+	if f.Description != "" {
+		schema["description"] = f.Description
+	}
+	if f.Unique {
+		schema[jsonSchemaUnique] = true
+	}
+	schema[jsonSchemaStatus] = string(f.Status)
+
+	return schema
+}
+
+// DataJSONSchema describes the data of the entries of a model as a JSON
+// Schema. Deleted fields are left out.
+func DataJSONSchema(m domain.Model) map[string]any {
+	props := map[string]any{}
+	required := []string{}
+	for _, f := range m.ActiveFields() {
+		props[f.Name] = FieldJSONSchema(f)
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if m.Description != "" {
+		schema["description"] = m.Description
+	}
+	return schema
+}
+
+// ExportJSONSchema converts a model to a standalone JSON Schema (draft
+// 2020-12) of the data of its entries. ImportJSONSchema converts it back.
+func ExportJSONSchema(m domain.Model) map[string]any {
+	schema := DataJSONSchema(m)
+	schema["$schema"] = jsonSchemaDialect
+	schema["title"] = m.Name
+	schema[jsonSchemaSlug] = m.Slug
+	schema[jsonSchemaKind] = string(m.Kind)
+	schema[jsonSchemaStatus] = string(m.Status)
+	return schema
+}
+
+// ImportJSONSchema converts a JSON Schema describing an object to a new
+// model. The model is named after the schema title, or name when it has
+// none. Properties and keywords a model cannot represent are skipped or
+// approximated, and reported in the returned notes.
+func ImportJSONSchema(data []byte, name string) (domain.Model, []string, error) {
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return domain.Model{}, nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var notes []string
+	note := func(format string, args ...any) {
+		notes = append(notes, fmt.Sprintf(format, args...))
+	}
+
+	if dialect, ok := schema["$schema"].(string); ok && dialect != jsonSchemaDialect {
+		note("$schema: %s is read as draft 2020-12", dialect)
+	}
+	if t, _ := jsonSchemaTypes(schema["type"]); len(t) != 1 || t[0] != "object" {
+		return domain.Model{}, nil, fmt.Errorf("schema must describe an object")
+	}
+
+	if title, ok := schema["title"].(string); ok && strings.TrimSpace(title) != "" {
+		name = title
+	}
+	slug, _ := schema[jsonSchemaSlug].(string)
+	if slug == "" {
+		var err error
+		if slug, err = Slugify(name); err != nil {
+			return domain.Model{}, nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	model := domain.Model{
+		ID:            uuid.New().String(),
+		Name:          name,
+		Slug:          slug,
+		Kind:          domain.KindCollection,
+		Status:        domain.StatusDraft,
+		SchemaVersion: 1,
+	}
+	model.Description, _ = schema["description"].(string)
+	if kind, ok := schema[jsonSchemaKind].(string); ok {
+		model.Kind = domain.ModelKind(kind)
+	}
+	if status, ok := schema[jsonSchemaStatus].(string); ok {
+		if domain.Status(status) == domain.StatusDelete {
+			note("%s: a model cannot be imported into the trash, imported as a draft", jsonSchemaStatus)
+		} else {
+			model.Status = domain.Status(status)
+		}
+	}
+
+	for _, keyword := range sortedKeys(schema) {
+		switch keyword {
+		case "$schema", "$id", "$comment", "title", "description", "type", "properties", "required",
+			jsonSchemaSlug, jsonSchemaKind, jsonSchemaStatus:
+		case "additionalProperties":
+			if schema[keyword] != false {
+				note("additionalProperties: entries cannot hold properties that are not fields")
+			}
+		default:
+			note("%s: keyword is not supported and was ignored", keyword)
+		}
+	}
+
+	required := map[string]bool{}
+	if list, ok := schema["required"].([]any); ok {
+		for _, r := range list {
+			if s, ok := r.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	for _, prop := range sortedKeys(props) {
+		propSchema, ok := props[prop].(map[string]any)
+		if !ok {
+			note("%s: property schema is not an object, skipped", prop)
+			continue
+		}
+
+		field, propNotes, ok := jsonSchemaField(propSchema)
+		for _, n := range propNotes {
+			note("%s: %s", prop, n)
+		}
+		if !ok {
+			continue
+		}
+
+		field.ID = uuid.New().String()
+		field.Name = prop
+		field.Required = required[prop]
+		field.CreatedAt = now
+		field.UpdatedAt = now
+		model.Fields = append(model.Fields, field)
+	}
+
+	for r := range required {
+		if _, ok := props[r]; !ok {
+			note("required: %s is not a property, ignored", r)
+		}
+	}
+	sort.Strings(notes)
+
+	if err := domain.ValidateModel(model); err != nil {
+		return domain.Model{}, notes, err
+	}
+	return model, notes, nil
+}
+
+// jsonSchemaField converts the schema of a property to a field. It reports
+// false when the property cannot be represented at all.
+func jsonSchemaField(schema map[string]any) (domain.Field, []string, bool) {
+	var notes []string
+	field := domain.Field{Status: domain.StatusDraft}
+	field.Description, _ = schema["description"].(string)
+	if status, ok := schema[jsonSchemaStatus].(string); ok {
+		if domain.Status(status) == domain.StatusDelete {
+			return field, []string{"field is in the trash, skipped"}, false
+		}
+		field.Status = domain.Status(status)
+	}
+	field.Unique, _ = schema[jsonSchemaUnique].(bool)
+
+	types, ok := jsonSchemaTypes(schema["type"])
+	if !ok {
+		for _, combinator := range []string{"$ref", "allOf", "anyOf", "oneOf"} {
+			if _, ok := schema[combinator]; ok {
+				return field, []string{combinator + " is not supported, skipped"}, false
+			}
+		}
+		return field, []string{"no type, skipped"}, false
+	}
+	if len(types) > 1 {
+		return field, []string{fmt.Sprintf("union of types %s is not supported, skipped", strings.Join(types, ", "))}, false
+	}
+
+	format, _ := schema["format"].(string)
+	// Fields of custom types name their type, registered by the program
+	// importing the schema.
+	custom, _ := schema[jsonSchemaType].(string)
+	if domain.IsBuiltinType(domain.FieldType(custom)) {
+		custom = ""
+	}
+	if custom != "" && !domain.IsValidType(custom) {
+		notes = append(notes, fmt.Sprintf("field type %s is not registered, imported from its type", custom))
+		custom = ""
+	}
+
+	switch {
+	case custom != "":
+		field.Type = domain.FieldType(custom)
+		if string(domain.FieldValueType(field.Type)) != types[0] {
+			return field, []string{fmt.Sprintf("type %s does not match the values of %s fields, skipped", types[0], custom)}, false
+		}
+		field.Options, _ = schema[jsonSchemaOptions].(map[string]any)
+	case types[0] == "string":
+		switch {
+		case schema[jsonSchemaTarget] != nil:
+			field.Type = domain.FieldRelation
+			field.Target, _ = schema[jsonSchemaTarget].(string)
+		case format == "date":
+			field.Type = domain.FieldDate
+		case format == "date-time":
+			field.Type = domain.FieldDateTime
+		case schema[jsonSchemaType] == string(domain.FieldText):
+			field.Type = domain.FieldText
+		default:
+			field.Type = domain.FieldString
+			if format != "" {
+				notes = append(notes, fmt.Sprintf("format %s is not enforced, imported as a string", format))
+			}
+		}
+	case types[0] == "number":
+		field.Type = domain.FieldNumber
+	case types[0] == "integer":
+		field.Type = domain.FieldNumber
+		notes = append(notes, "integer is imported as a number, fractions are accepted")
+	case types[0] == "boolean":
+		field.Type = domain.FieldBoolean
+	default:
+		return field, []string{fmt.Sprintf("type %s is not supported, skipped", types[0])}, false
+	}
+
+	for _, keyword := range sortedKeys(schema) {
+		switch keyword {
+		case "type", "format", "description", "title", "$comment",
+			jsonSchemaStatus, jsonSchemaUnique, jsonSchemaTarget, jsonSchemaType, jsonSchemaOptions:
+		default:
+			notes = append(notes, fmt.Sprintf("%s is not supported and was ignored", keyword))
+		}
+	}
+	return field, notes, true
+}
+
+// jsonSchemaTypes returns the types allowed by a type keyword, leaving out
+// null: every field that is not required accepts it.
+func jsonSchemaTypes(v any) ([]string, bool) {
+	var types []string
+	switch t := v.(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+	default:
+		return nil, false
+	}
+
+	nonNull := types[:0]
+	for _, t := range types {
+		if t != "null" {
+			nonNull = append(nonNull, t)
+		}
+	}
+	return nonNull, len(nonNull) > 0
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
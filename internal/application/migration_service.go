@@ -0,0 +1,277 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MigrationService applies the schema changes that migrate stored data, and
+// records them in the migration history of the project.
+type MigrationService struct {
+	models     domain.Repository
+	content    domain.ContentRepository
+	jobs       domain.ScheduleRepository
+	aliases    domain.AliasRepository
+	migrations domain.MigrationRepository
+	tokens     domain.TokenRepository
+	// aliasTTL is how long the former slug of a renamed model redirects to
+	// it. Zero keeps aliases forever.
+	aliasTTL time.Duration
+	events   EventPublisher
+}
+
+func NewMigrationService(models domain.Repository, content domain.ContentRepository, jobs domain.ScheduleRepository,
+	aliases domain.AliasRepository, migrations domain.MigrationRepository, tokens domain.TokenRepository, aliasTTL time.Duration,
+	events EventPublisher) *MigrationService {
+	return &MigrationService{
+		models:     models,
+		content:    content,
+		jobs:       jobs,
+		aliases:    aliases,
+		migrations: migrations,
+		tokens:     tokens,
+		aliasTTL:   aliasTTL,
+		events:     events,
+	}
+}
+
+// RenameModel changes the name and the slug of a model. Its entries and
+// scheduled jobs move to the new slug, relation fields targeting it and the
+// scopes of API tokens are rewritten, and the former slug becomes an alias of
+// the model. It must run in a transaction, which discards the changes already
+// made when a step fails.
+func (s *MigrationService) RenameModel(slug, name, newSlug string) (domain.Model, error) {
+	model, err := s.models.GetModel(slug)
+	if err != nil || model.IsDeleted() {
+		return domain.Model{}, fmt.Errorf("%w: %s", domain.ErrModelNotFound, slug)
+	}
+	if newSlug == "" {
+		newSlug = slug
+	}
+	if name != "" {
+		model.Name = name
+	}
+
+	if newSlug == slug {
+		if err := domain.ValidateModel(model); err != nil {
+			return domain.Model{}, err
+		}
+		if err := s.models.UpdateModel(model); err != nil {
+			return domain.Model{}, err
+		}
+		if err := publish(s.events, domain.Event{Type: domain.EventModelUpdated, Model: slug}); err != nil {
+			return domain.Model{}, err
+		}
+		return model, nil
+	}
+
+	if _, err := s.models.GetModel(newSlug); err == nil {
+		return domain.Model{}, fmt.Errorf("%w: %s", domain.ErrModelAlreadyExists, newSlug)
+	}
+	model.Slug = newSlug
+	models, err := s.models.GetModels()
+	if err != nil {
+		return domain.Model{}, err
+	}
+	if err := ValidateTypeNames(model, models); err != nil {
+		return domain.Model{}, err
+	}
+	for i, f := range model.Fields {
+		if f.Type == domain.FieldRelation && f.Target == slug {
+			model.Fields[i].Target = newSlug
+		}
+	}
+	if err := domain.ValidateModel(model); err != nil {
+		return domain.Model{}, err
+	}
+
+	if err := s.content.RenameEntries(slug, newSlug); err != nil {
+		return domain.Model{}, err
+	}
+	if err := s.models.RenameModel(slug, model); err != nil {
+		return domain.Model{}, err
+	}
+	if model.Kind == domain.KindSingle {
+		if err := s.renameSingleEntry(slug, newSlug); err != nil {
+			return domain.Model{}, err
+		}
+	}
+	if err := s.renameJobs(slug, newSlug, model.Kind); err != nil {
+		return domain.Model{}, err
+	}
+	if err := s.renameRelationTargets(slug, newSlug); err != nil {
+		return domain.Model{}, err
+	}
+	if err := s.renameScopes(slug, newSlug); err != nil {
+		return domain.Model{}, err
+	}
+
+	now := time.Now().UTC()
+	if err := s.renameAliases(slug, newSlug, now); err != nil {
+		return domain.Model{}, err
+	}
+
+	err = s.migrations.AddMigration(domain.Migration{
+		ID:    uuid.New().String(),
+		Type:  domain.MigrationRenameModel,
+		Model: newSlug,
+		From:  slug,
+		To:    newSlug,
+		At:    now,
+	})
+	if err != nil {
+		return domain.Model{}, err
+	}
+	if err := publish(s.events, domain.Event{Type: domain.EventModelUpdated, Model: newSlug, PreviousModel: slug, OccurredAt: now}); err != nil {
+		return domain.Model{}, err
+	}
+	return model, nil
+}
+
+// renameScopes moves the scopes of API tokens on a renamed model to its new
+// slug.
+func (s *MigrationService) renameScopes(slug, newSlug string) error {
+	tokens, err := s.tokens.GetTokens()
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		renamed := false
+		for i, scope := range t.Scopes {
+			if scope.Model == slug {
+				t.Scopes[i].Model = newSlug
+				renamed = true
+			}
+		}
+		if !renamed {
+			continue
+		}
+		if err := s.tokens.UpdateToken(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameSingleEntry moves the entry of a single model, stored under the
+// model slug, to the new slug. Single models without an entry have nothing
+// to move.
+func (s *MigrationService) renameSingleEntry(slug, newSlug string) error {
+	entry, err := s.content.GetEntry(newSlug, slug)
+	if errors.Is(err, domain.ErrEntryNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.content.DeleteEntry(newSlug, slug); err != nil {
+		return err
+	}
+	entry.ID = newSlug
+	entry.Model = newSlug
+	return s.content.CreateEntry(entry)
+}
+
+func (s *MigrationService) renameJobs(slug, newSlug string, kind domain.ModelKind) error {
+	jobs, err := s.jobs.GetJobs()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Model != slug {
+			continue
+		}
+		if err := s.jobs.DeleteJob(job.Model, job.EntryID, job.Action); err != nil {
+			return err
+		}
+		job.Model = newSlug
+		if kind == domain.KindSingle {
+			job.EntryID = newSlug
+		}
+		if err := s.jobs.PutJob(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameRelationTargets rewrites the relation fields of other models,
+// including trashed models and fields, targeting the renamed model.
+func (s *MigrationService) renameRelationTargets(slug, newSlug string) error {
+	models, err := s.models.GetModels()
+	if err != nil {
+		return err
+	}
+	for _, m := range models {
+		changed := false
+		for i, f := range m.Fields {
+			if f.Type == domain.FieldRelation && f.Target == slug {
+				m.Fields[i].Target = newSlug
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := s.models.UpdateModel(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameAliases points the aliases of the model to its new slug and adds its
+// former slug. An alias matching the new slug is dropped: the model now
+// answers to it.
+func (s *MigrationService) renameAliases(slug, newSlug string, now time.Time) error {
+	aliases, err := s.aliases.GetAliases()
+	if err != nil {
+		return err
+	}
+	for _, a := range aliases {
+		if a.Target != slug || a.Slug == newSlug {
+			continue
+		}
+		a.Target = newSlug
+		if err := s.aliases.PutAlias(a); err != nil {
+			return err
+		}
+	}
+	if err := s.aliases.DeleteAlias(newSlug); err != nil {
+		return err
+	}
+
+	alias := domain.ModelAlias{Slug: slug, Target: newSlug}
+	if s.aliasTTL > 0 {
+		alias.ExpiresAt = now.Add(s.aliasTTL)
+	}
+	return s.aliases.PutAlias(alias)
+}
+
+// ResolveAlias returns the slug of the model that was renamed from slug,
+// unless a model now uses slug or the alias expired.
+func (s *MigrationService) ResolveAlias(slug string) (string, bool) {
+	if _, err := s.models.GetModel(slug); err == nil {
+		return "", false
+	}
+	aliases, err := s.aliases.GetAliases()
+	if err != nil {
+		return "", false
+	}
+	now := time.Now()
+	for _, a := range aliases {
+		if a.Slug == slug && !a.IsExpired(now) {
+			return a.Target, true
+		}
+	}
+	return "", false
+}
+
+// History returns the migrations applied to the project, oldest first.
+func (s *MigrationService) History() ([]domain.Migration, error) {
+	return s.migrations.GetMigrations()
+}
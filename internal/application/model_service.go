@@ -1,33 +1,167 @@
 package application
 
-import "github.com/axarus/vectrag/internal/domain"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
 
 type ModelService struct {
-	repo domain.Repository
+	repo   domain.Repository
+	events EventPublisher
 }
 
-func NewModelService(repo domain.Repository) *ModelService {
+// NewModelService creates a model service publishing an event after each
+// change to events, which may be nil.
+func NewModelService(repo domain.Repository, events EventPublisher) *ModelService {
 	return &ModelService{
-		repo: repo,
+		repo:   repo,
+		events: events,
 	}
 }
 
 func (ms *ModelService) Create(model domain.Model) error {
-	return ms.repo.CreateModel(model)
+	if _, err := ms.repo.GetModel(model.Slug); err == nil {
+		return fmt.Errorf("%w: %s", domain.ErrModelAlreadyExists, model.Slug)
+	} else if !errors.Is(err, domain.ErrModelNotFound) {
+		return err
+	}
+	if model.IsDeleted() {
+		return &domain.ValidationError{Field: "Status", Message: "a model cannot be created in the trash"}
+	}
+	for _, f := range model.Fields {
+		if f.IsDeleted() {
+			return &domain.ValidationError{Field: "Status", Message: fmt.Sprintf("field '%s' cannot be created in the trash", f.Name)}
+		}
+	}
+	if err := ms.validateRelations(model); err != nil {
+		return err
+	}
+	models, err := ms.repo.GetModels()
+	if err != nil {
+		return err
+	}
+	if err := ValidateTypeNames(model, models); err != nil {
+		return err
+	}
+	if err := ms.repo.CreateModel(model); err != nil {
+		return err
+	}
+	return publish(ms.events, domain.Event{Type: domain.EventModelCreated, Model: model.Slug})
 }
 
-func (ms *ModelService) Update(model domain.Model) error {
-	return ms.repo.UpdateModel(model)
+// Update saves a new version of a model. Existing fields missing from it are
+// moved to the trash instead of being dropped, and fields whose status
+// becomes delete get their deletion timestamp. It returns the saved model.
+func (ms *ModelService) Update(model domain.Model) (domain.Model, error) {
+	existing, err := ms.Get(model.Slug)
+	if err != nil {
+		return domain.Model{}, err
+	}
+	if model.IsDeleted() {
+		return domain.Model{}, &domain.ValidationError{Field: "Status", Message: "delete the model to move it to the trash"}
+	}
+
+	model.Fields = mergeTrashedFields(existing.Fields, model.Fields, time.Now().UTC())
+	if err := domain.ValidateModel(model); err != nil {
+		return domain.Model{}, err
+	}
+	if err := ms.validateRelations(model); err != nil {
+		return domain.Model{}, err
+	}
+	if err := ms.repo.UpdateModel(model); err != nil {
+		return domain.Model{}, err
+	}
+	if err := publish(ms.events, domain.Event{Type: domain.EventModelUpdated, Model: model.Slug}); err != nil {
+		return domain.Model{}, err
+	}
+	return model, nil
 }
 
+// Delete moves a model to the trash. Use TrashService to restore or purge it.
 func (ms *ModelService) Delete(slug string) error {
-	return ms.repo.DeleteModel(slug)
+	model, err := ms.Get(slug)
+	if err != nil {
+		return err
+	}
+
+	model.Status = domain.StatusDelete
+	model.DeletedAt = time.Now().UTC()
+	if err := ms.repo.UpdateModel(model); err != nil {
+		return err
+	}
+	return publish(ms.events, domain.Event{Type: domain.EventModelDeleted, Model: slug})
 }
 
 func (ms *ModelService) Get(slug string) (domain.Model, error) {
-	return ms.repo.GetModel(slug)
+	model, err := ms.repo.GetModel(slug)
+	if err != nil {
+		return domain.Model{}, err
+	}
+	if model.IsDeleted() {
+		return domain.Model{}, fmt.Errorf("%w: %s is in the trash", domain.ErrModelNotFound, slug)
+	}
+	return model, nil
 }
 
 func (ms *ModelService) List() ([]domain.Model, error) {
-	return ms.repo.GetModels()
+	models, err := ms.repo.GetModels()
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]domain.Model, 0, len(models))
+	for _, m := range models {
+		if !m.IsDeleted() {
+			active = append(active, m)
+		}
+	}
+	return active, nil
+}
+
+func (ms *ModelService) validateRelations(model domain.Model) error {
+	models, err := ms.repo.GetModels()
+	if err != nil {
+		return err
+	}
+	return domain.ValidateRelationTargets(model, models)
+}
+
+func mergeTrashedFields(existing, updated []domain.Field, now time.Time) []domain.Field {
+	previous := make(map[string]domain.Field, len(existing))
+	for _, f := range existing {
+		previous[f.ID] = f
+	}
+
+	merged := make([]domain.Field, 0, len(updated)+len(existing))
+	kept := make(map[string]bool, len(updated))
+	for _, f := range updated {
+		kept[f.ID] = true
+		if f.IsDeleted() {
+			if prev, ok := previous[f.ID]; ok && prev.IsDeleted() {
+				f.DeletedAt = prev.DeletedAt
+			} else {
+				f.DeletedAt = now
+			}
+		} else {
+			f.DeletedAt = time.Time{}
+		}
+		merged = append(merged, f)
+	}
+
+	for _, f := range existing {
+		if kept[f.ID] {
+			continue
+		}
+		if !f.IsDeleted() {
+			f.Status = domain.StatusDelete
+			f.DeletedAt = now
+			f.UpdatedAt = now
+		}
+		merged = append(merged, f)
+	}
+
+	return merged
 }
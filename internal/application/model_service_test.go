@@ -0,0 +1,42 @@
+package application
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/axarus/vectrag/internal/infrastructure/filestore"
+)
+
+func TestCreateModelAlreadyExists(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := filestore.NewFileLock(filepath.Join(dir, "lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal, err := filestore.NewJournal(filepath.Join(dir, "journal.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := filestore.NewTxLock(lock, journal)
+	repo, err := filestore.NewYamlRepository(filepath.Join(dir, "models"), journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms := NewModelService(repo, nil)
+
+	// The first model is only staged in the journal when the second is created.
+	err = tx.Tx(func() error {
+		if err := ms.Create(testModel("posts", domain.Field{Name: "title"})); err != nil {
+			return err
+		}
+		return ms.Create(testModel("posts", domain.Field{Name: "body"}))
+	})
+	if !errors.Is(err, domain.ErrModelAlreadyExists) {
+		t.Fatalf("Create() error = %v, want %v", err, domain.ErrModelAlreadyExists)
+	}
+	if _, err := repo.GetModel("posts"); !errors.Is(err, domain.ErrModelNotFound) {
+		t.Errorf("GetModel() error = %v, want the failed transaction rolled back", err)
+	}
+}
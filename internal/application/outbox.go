@@ -0,0 +1,244 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+const (
+	defaultOutboxInterval = time.Second
+	// outboxBatchSize is how many changes are given to a publisher at once.
+	outboxBatchSize = 100
+	// outboxFirstRetry is the delay before publishing again after a failure,
+	// doubling after each failure up to outboxMaxRetry.
+	outboxFirstRetry = time.Second
+	outboxMaxRetry   = time.Minute
+)
+
+// ChangePublisher sends the changes of a project to an external system, such
+// as a message broker, for an Outbox.
+type ChangePublisher interface {
+	// Name identifies the publisher in the change log, which keeps the
+	// changes it has not published.
+	Name() string
+	// PublishChanges sends changes, oldest first. Changes are sent again when
+	// it fails, and may be after a crash, so consumers must tolerate
+	// duplicates, which have the same Seq.
+	PublishChanges(ctx context.Context, changes []domain.Change) error
+}
+
+// Outbox relays the change log of a project to publishers. Since changes are
+// recorded in the transaction of the writes they describe, and each publisher
+// has a cursor in the log, every write is published at least once, in order,
+// including the writes of the vectrag commands and of a server that stopped
+// before relaying them.
+type Outbox struct {
+	changes    domain.ChangeRepository
+	publishers []ChangePublisher
+	tx         Transactor
+	interval   time.Duration
+	wake       chan struct{}
+
+	mu       sync.Mutex
+	failures map[string]int
+	retryAt  map[string]time.Time
+}
+
+// NewOutbox creates an outbox relaying changes to publishers. It accesses the
+// log in transactions of tx.
+func NewOutbox(changes domain.ChangeRepository, publishers []ChangePublisher, tx Transactor) *Outbox {
+	return &Outbox{
+		changes:    changes,
+		publishers: publishers,
+		tx:         tx,
+		interval:   defaultOutboxInterval,
+		wake:       make(chan struct{}, 1),
+		failures:   make(map[string]int),
+		retryAt:    make(map[string]time.Time),
+	}
+}
+
+// Publishers returns the publishers of the outbox.
+func (o *Outbox) Publishers() []ChangePublisher {
+	return o.publishers
+}
+
+// Publish wakes Run up to relay a new change. The change itself is read from
+// the log once the transaction recording it is committed.
+func (o *Outbox) Publish(domain.Event) error {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Register creates the cursors of the publishers starting at the oldest
+// change still logged, and deletes the cursors of the publishers removed
+// from the project, which would otherwise keep their changes forever.
+func (o *Outbox) Register() error {
+	return runTx(o.tx, func() error {
+		cursors, err := o.changes.GetCursors()
+		if err != nil {
+			return err
+		}
+		names := make([]string, len(o.publishers))
+		for i, p := range o.publishers {
+			names[i] = p.Name()
+			if _, ok := cursors[p.Name()]; !ok {
+				if err := o.changes.SetCursor(p.Name(), 0); err != nil {
+					return err
+				}
+			}
+		}
+		for name := range cursors {
+			if !slices.Contains(names, name) {
+				if err := o.changes.DeleteCursor(name); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Run relays the changes of the log until ctx is cancelled.
+func (o *Outbox) Run(ctx context.Context) {
+	if len(o.publishers) == 0 {
+		return
+	}
+	if err := o.Register(); err != nil {
+		log.Printf("outbox: %v", err)
+	}
+
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+
+	for {
+		o.Relay(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// Relay sends every publisher not backing off at now the changes after its
+// cursor.
+func (o *Outbox) Relay(ctx context.Context, now time.Time) {
+	for _, p := range o.publishers {
+		o.mu.Lock()
+		wait := now.Before(o.retryAt[p.Name()])
+		o.mu.Unlock()
+		if wait {
+			continue
+		}
+
+		if err := o.relay(ctx, p); err != nil {
+			o.mu.Lock()
+			o.failures[p.Name()]++
+			delay := outboxFirstRetry
+			for i := 1; i < o.failures[p.Name()] && delay < outboxMaxRetry; i++ {
+				delay *= 2
+			}
+			o.retryAt[p.Name()] = now.Add(min(delay, outboxMaxRetry))
+			o.mu.Unlock()
+			log.Printf("outbox: %s: %v", p.Name(), err)
+			continue
+		}
+		o.mu.Lock()
+		delete(o.failures, p.Name())
+		delete(o.retryAt, p.Name())
+		o.mu.Unlock()
+	}
+}
+
+func (o *Outbox) relay(ctx context.Context, p ChangePublisher) error {
+	for ctx.Err() == nil {
+		var cursors map[string]int64
+		var changes []domain.Change
+		var oldest int64
+		err := runTx(o.tx, func() error {
+			var err error
+			if cursors, err = o.changes.GetCursors(); err != nil {
+				return err
+			}
+			changes, oldest, err = o.changes.GetChanges(cursors[p.Name()])
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		if cursor := cursors[p.Name()]; cursor > 0 && cursor+1 < oldest {
+			log.Printf("outbox: %s: changes %d to %d were dropped from the log before being published", p.Name(), cursor+1, oldest-1)
+		}
+
+		batch := changes[:min(len(changes), outboxBatchSize)]
+		if err := p.PublishChanges(ctx, batch); err != nil {
+			return fmt.Errorf("failed to publish changes %d to %d: %w", batch[0].Seq, batch[len(batch)-1].Seq, err)
+		}
+
+		err = runTx(o.tx, func() error {
+			return o.changes.SetCursor(p.Name(), batch[len(batch)-1].Seq)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemoryChangePublisher keeps the changes it is sent in memory. It lets tests
+// and programs embedding a project observe its changes.
+type MemoryChangePublisher struct {
+	name string
+
+	mu      sync.Mutex
+	changes []domain.Change
+	err     error
+}
+
+func NewMemoryChangePublisher(name string) *MemoryChangePublisher {
+	return &MemoryChangePublisher{name: name}
+}
+
+func (p *MemoryChangePublisher) Name() string {
+	return p.name
+}
+
+func (p *MemoryChangePublisher) PublishChanges(_ context.Context, changes []domain.Change) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	p.changes = append(p.changes, changes...)
+	return nil
+}
+
+// Changes returns the changes published so far, oldest first.
+func (p *MemoryChangePublisher) Changes() []domain.Change {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.changes)
+}
+
+// Fail makes the next publications fail with err, until called with nil.
+func (p *MemoryChangePublisher) Fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
@@ -3,27 +3,139 @@ package application
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"gopkg.in/yaml.v3"
 )
 
 type ProjectConfig struct {
+	Project     ProjectInfo        `yaml:"project"`
 	Paths       ProjectPaths       `yaml:"paths"`
 	Development ProjectDevelopment `yaml:"development"`
+	Content     ProjectContent     `yaml:"content"`
+	Trash       ProjectTrash       `yaml:"trash"`
+	Models      ProjectModels      `yaml:"models"`
+	Auth        ProjectAuth        `yaml:"auth"`
+	Roles       ProjectRoles       `yaml:"roles"`
+	Webhooks    ProjectWebhooks    `yaml:"webhooks"`
+	Events      ProjectEvents      `yaml:"events"`
+	Outbox      ProjectOutbox      `yaml:"outbox"`
+}
+
+type ProjectInfo struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
 }
 
 type ProjectPaths struct {
-	Models string `yaml:"models"`
-	Config string `yaml:"config"`
-	Admin  string `yaml:"admin"`
+	Models  string `yaml:"models"`
+	Content string `yaml:"content"`
+	Config  string `yaml:"config"`
+	Admin   string `yaml:"admin"`
 }
 
+const defaultContentPath = ".vectrag/content"
+
 type ProjectDevelopment struct {
 	EnableCORS bool `yaml:"enableCORS"`
 }
 
+type ProjectContent struct {
+	// PreviewToken must be sent as a bearer token to read drafts through
+	// the content API. Draft previews are disabled when it is empty.
+	PreviewToken string `yaml:"previewToken"`
+}
+
+type ProjectTrash struct {
+	// RetentionDays is how long deleted models, fields and entries stay in
+	// the trash before being purged. Zero keeps them until purged manually.
+	RetentionDays int `yaml:"retentionDays"`
+}
+
+type ProjectModels struct {
+	// AliasDays is how long the former slug of a renamed model redirects to
+	// the model. Zero keeps redirecting forever.
+	AliasDays int `yaml:"aliasDays"`
+}
+
+type ProjectAuth struct {
+	// SessionDays is how long admin users stay logged in. Zero defaults to
+	// 7 days.
+	SessionDays int `yaml:"sessionDays"`
+}
+
+// ProjectRoles defines the roles given to admin users, by name. The admin
+// role is built in, and the public role applies to anonymous callers.
+type ProjectRoles map[string]ProjectRole
+
+type ProjectRole struct {
+	Description string              `yaml:"description"`
+	Permissions []ProjectPermission `yaml:"permissions"`
+}
+
+// ProjectPermission allows actions on models, see domain.Permission. Fields
+// limits reading and writing to the given fields, and Own to the entries
+// created by the user.
+type ProjectPermission struct {
+	Actions []string `yaml:"actions"`
+	Models  []string `yaml:"models"`
+	Fields  []string `yaml:"fields"`
+	Own     bool     `yaml:"own"`
+}
+
+type ProjectEvents struct {
+	// LogSize is how many changes are kept for the clients resuming the
+	// event stream. Zero defaults to 1000.
+	LogSize int `yaml:"logSize"`
+}
+
+// ProjectOutbox defines the publishers the change log of the project is
+// relayed to, by name.
+type ProjectOutbox map[string]ProjectPublisher
+
+// ProjectPublisher is a publisher of the outbox. The file type appends the
+// changes to the file at Path, relative to the project root, as JSON lines.
+type ProjectPublisher struct {
+	Type string `yaml:"type"`
+	Path string `yaml:"path"`
+}
+
+// ProjectWebhooks defines the webhooks sent the events of the project, by
+// name.
+type ProjectWebhooks map[string]ProjectWebhook
+
+// ProjectWebhook sends events to a URL, see domain.Webhook. Requests are
+// signed with Secret when it is set.
+type ProjectWebhook struct {
+	URL    string   `yaml:"url"`
+	Events []string `yaml:"events"`
+	Models []string `yaml:"models"`
+	Secret string   `yaml:"secret"`
+}
+
+// ModelReferences returns the settings naming the model slug, such as
+// roles.editor.permissions[0].models or webhooks.notify.models. They keep
+// naming it when the model is renamed, as the config is only written by hand.
+func (c ProjectConfig) ModelReferences(slug string) []string {
+	var refs []string
+	for _, name := range slices.Sorted(maps.Keys(c.Roles)) {
+		for i, p := range c.Roles[name].Permissions {
+			if slices.Contains(p.Models, slug) {
+				refs = append(refs, fmt.Sprintf("roles.%s.permissions[%d].models", name, i))
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Webhooks)) {
+		if slices.Contains(c.Webhooks[name].Models, slug) {
+			refs = append(refs, fmt.Sprintf("webhooks.%s.models", name))
+		}
+	}
+	return refs
+}
+
 func FindProjectRoot(startDir string) (string, error) {
 	if startDir == "" {
 		return "", fmt.Errorf("start directory is empty")
@@ -74,15 +186,60 @@ func LoadProjectConfig(projectRoot string) (ProjectConfig, error) {
 }
 
 func ResolveModelsDir(projectRoot string, cfg ProjectConfig) (string, error) {
-	modelsPath := cfg.Paths.Models
-	if filepath.IsAbs(modelsPath) {
-		return modelsPath, nil
+	abs, err := resolveProjectPath(projectRoot, cfg.Paths.Models)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve models dir: %w", err)
+	}
+	return abs, nil
+}
+
+// ResolveContentDir returns the directory where entries are stored, defaulting
+// to .vectrag/content for projects created before paths.content existed.
+func ResolveContentDir(projectRoot string, cfg ProjectConfig) (string, error) {
+	contentPath := cfg.Paths.Content
+	if contentPath == "" {
+		contentPath = defaultContentPath
 	}
 
-	abs := filepath.Join(projectRoot, modelsPath)
-	abs, err := filepath.Abs(abs)
+	abs, err := resolveProjectPath(projectRoot, contentPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to resolve models dir: %w", err)
+		return "", fmt.Errorf("failed to resolve content dir: %w", err)
 	}
 	return abs, nil
 }
+
+// ResolveStateDir returns the .vectrag directory holding the internal state of
+// a project.
+func ResolveStateDir(projectRoot string) string {
+	return filepath.Join(projectRoot, ".vectrag")
+}
+
+// ResolveLockFile returns the file locked by the processes writing to a
+// project, such as vectrag develop and the vectrag model commands.
+func ResolveLockFile(projectRoot string) string {
+	return filepath.Join(ResolveStateDir(projectRoot), "lock")
+}
+
+// ResolveJournalFile returns the file recording the writes of a transaction
+// while they are made, so that a process stopping halfway is completed by the
+// next one taking the project lock.
+func ResolveJournalFile(projectRoot string) string {
+	return filepath.Join(ResolveStateDir(projectRoot), "journal.json")
+}
+
+// ResolvePublisherPath returns the absolute path of the file of an outbox
+// publisher.
+func ResolvePublisherPath(projectRoot string, p ProjectPublisher) (string, error) {
+	if p.Path == "" {
+		return "", fmt.Errorf("publisher path is empty")
+	}
+	return resolveProjectPath(projectRoot, p.Path)
+}
+
+func resolveProjectPath(projectRoot, path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+
+	return filepath.Abs(filepath.Join(projectRoot, path))
+}
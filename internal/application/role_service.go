@@ -0,0 +1,94 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// tokenOwnerPrefix namespaces the API tokens creating entries, as usernames
+// cannot contain ':'.
+const tokenOwnerPrefix = "token:"
+
+// RoleService holds the roles of a project and resolves the policies of the
+// callers of its APIs: admin users from their role, API tokens from their
+// scopes and anonymous callers from the public role.
+type RoleService struct {
+	roles map[string]domain.Role
+}
+
+// NewRoleService builds the roles defined in the project config besides the
+// built-in admin and public roles. The public role may be redefined, the
+// admin role may not.
+func NewRoleService(cfg ProjectRoles) (*RoleService, error) {
+	roles := map[string]domain.Role{
+		domain.RoleAdmin: {
+			Name:        domain.RoleAdmin,
+			Description: "Allowed everything",
+			Permissions: domain.FullAccess("").Permissions,
+		},
+		domain.RolePublic: {
+			Name:        domain.RolePublic,
+			Description: "Anonymous callers, reading published content",
+			Permissions: []domain.Permission{{Actions: []domain.Action{domain.ActionRead}, Models: []string{domain.AnyModel}}},
+		},
+	}
+
+	for name, rc := range cfg {
+		if name == domain.RoleAdmin {
+			return nil, &domain.ValidationError{Field: "roles", Message: "the admin role is built in and cannot be redefined"}
+		}
+		role := domain.Role{Name: name, Description: rc.Description}
+		for _, pc := range rc.Permissions {
+			perm := domain.Permission{Models: pc.Models, Fields: pc.Fields, Own: pc.Own}
+			for _, a := range pc.Actions {
+				perm.Actions = append(perm.Actions, domain.Action(a))
+			}
+			role.Permissions = append(role.Permissions, perm)
+		}
+		if err := domain.ValidateRole(role); err != nil {
+			return nil, fmt.Errorf("invalid roles in vectrag.config.yaml: %w", err)
+		}
+		roles[name] = role
+	}
+	return &RoleService{roles: roles}, nil
+}
+
+func (s *RoleService) Role(name string) (domain.Role, error) {
+	role, ok := s.roles[name]
+	if !ok {
+		return domain.Role{}, fmt.Errorf("%w: %s", domain.ErrRoleNotFound, name)
+	}
+	return role, nil
+}
+
+// Roles returns the roles sorted by name.
+func (s *RoleService) Roles() []domain.Role {
+	roles := make([]domain.Role, 0, len(s.roles))
+	for _, r := range s.roles {
+		roles = append(roles, r)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles
+}
+
+// UserPolicy returns the policy of an admin user. Users whose role was removed
+// from the config are allowed nothing.
+func (s *RoleService) UserPolicy(u domain.AdminUser) domain.Policy {
+	return domain.Policy{Owner: u.Username, Permissions: s.roles[u.RoleName()].Permissions}
+}
+
+// TokenPolicy returns the policy of an API token, allowing its scopes.
+func (s *RoleService) TokenPolicy(t domain.APIToken) domain.Policy {
+	policy := domain.Policy{Owner: tokenOwnerPrefix + t.ID}
+	for _, scope := range t.Scopes {
+		policy.Permissions = append(policy.Permissions, scope.Permission())
+	}
+	return policy
+}
+
+// PublicPolicy returns the policy of anonymous callers.
+func (s *RoleService) PublicPolicy() domain.Policy {
+	return domain.Policy{Permissions: s.roles[domain.RolePublic].Permissions}
+}
@@ -0,0 +1,231 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+const (
+	defaultSchedulerInterval = time.Second
+	// schedulerFirstRetry is the delay before applying a transition again
+	// after it failed, doubling after each failure up to schedulerMaxRetry.
+	schedulerFirstRetry = time.Second
+	schedulerMaxRetry   = time.Hour
+)
+
+// Scheduler applies scheduled publish and unpublish transitions. Pending jobs
+// live in a ScheduleRepository, so transitions that became due while the
+// server was stopped are applied on the next run.
+type Scheduler struct {
+	content  *ContentService
+	jobs     domain.ScheduleRepository
+	events   EventPublisher
+	tx       Transactor
+	interval time.Duration
+
+	mu       sync.Mutex
+	failures map[string]int
+	retryAt  map[string]time.Time
+}
+
+// NewScheduler creates a scheduler. Transitions are applied in transactions
+// of tx, which must be the one serializing the other writers of content.
+func NewScheduler(content *ContentService, jobs domain.ScheduleRepository, events EventPublisher, tx Transactor) *Scheduler {
+	return &Scheduler{
+		content:  content,
+		jobs:     jobs,
+		events:   events,
+		tx:       tx,
+		interval: defaultSchedulerInterval,
+		failures: make(map[string]int),
+		retryAt:  make(map[string]time.Time),
+	}
+}
+
+// Schedule replaces the pending transitions of an entry. A zero time clears
+// the corresponding transition. The id is ignored for single models.
+func (s *Scheduler) Schedule(slug, id string, publishAt, unpublishAt time.Time) (domain.Entry, error) {
+	if err := domain.ValidateSchedule(publishAt, unpublishAt); err != nil {
+		return domain.Entry{}, err
+	}
+
+	entry, err := s.content.SetSchedule(slug, id, publishAt, unpublishAt)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	if err := s.putJob(entry, domain.SchedulePublish, publishAt); err != nil {
+		return domain.Entry{}, err
+	}
+	if err := s.putJob(entry, domain.ScheduleUnpublish, unpublishAt); err != nil {
+		return domain.Entry{}, err
+	}
+	return entry, nil
+}
+
+func (s *Scheduler) putJob(entry domain.Entry, action domain.ScheduleAction, at time.Time) error {
+	if at.IsZero() {
+		return s.jobs.DeleteJob(entry.Model, entry.ID, action)
+	}
+	return s.jobs.PutJob(domain.ScheduledJob{
+		Model:   entry.Model,
+		EntryID: entry.ID,
+		Action:  action,
+		At:      at,
+	})
+}
+
+// Run applies due transitions until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunDue(time.Now().UTC()); err != nil {
+			log.Printf("scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue applies every transition scheduled at or before now, oldest first.
+// Jobs are deleted once applied, and kept to be tried again later when their
+// transition fails.
+func (s *Scheduler) RunDue(now time.Time) error {
+	var jobs []domain.ScheduledJob
+	err := runTx(s.tx, func() error {
+		var err error
+		jobs, err = s.jobs.GetJobs()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].At.Before(jobs[j].At)
+	})
+	s.forgetFailures(jobs)
+
+	for _, job := range jobs {
+		if job.At.After(now) {
+			break
+		}
+		s.mu.Lock()
+		wait := now.Before(s.retryAt[jobKey(job)])
+		s.mu.Unlock()
+		if wait {
+			continue
+		}
+
+		if err := s.apply(job, now); err != nil {
+			delay := s.fail(job, now)
+			log.Printf("scheduler: failed to %s %s/%s, retrying in %s: %v", job.Action, job.Model, job.EntryID, delay, err)
+			continue
+		}
+		s.mu.Lock()
+		delete(s.failures, jobKey(job))
+		delete(s.retryAt, jobKey(job))
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+// fail backs off from retrying job, returning the delay until it is.
+func (s *Scheduler) fail(job domain.ScheduledJob, now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := jobKey(job)
+	s.failures[key]++
+	delay := schedulerFirstRetry
+	for i := 1; i < s.failures[key] && delay < schedulerMaxRetry; i++ {
+		delay *= 2
+	}
+	delay = min(delay, schedulerMaxRetry)
+	s.retryAt[key] = now.Add(delay)
+	return delay
+}
+
+// forgetFailures drops the failures of the jobs no longer pending, e.g.
+// because they were rescheduled.
+func (s *Scheduler) forgetFailures(pending []domain.ScheduledJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make(map[string]bool, len(pending))
+	for _, job := range pending {
+		keys[jobKey(job)] = true
+	}
+	for key := range s.failures {
+		if !keys[key] {
+			delete(s.failures, key)
+			delete(s.retryAt, key)
+		}
+	}
+}
+
+// jobKey identifies a job at its scheduled time.
+func jobKey(job domain.ScheduledJob) string {
+	return fmt.Sprintf("%s/%s/%s@%d", job.Model, job.EntryID, job.Action, job.At.UnixNano())
+}
+
+// apply performs a transition, publishes its event and deletes its job in a
+// transaction, as subscribers such as webhooks write to the project. Jobs
+// rescheduled since they were read are left for their new time, and jobs of
+// entries that no longer exist are deleted.
+func (s *Scheduler) apply(job domain.ScheduledJob, now time.Time) error {
+	return runTx(s.tx, func() error {
+		jobs, err := s.jobs.GetJobs()
+		if err != nil {
+			return err
+		}
+		current := slices.IndexFunc(jobs, func(j domain.ScheduledJob) bool {
+			return j.Model == job.Model && j.EntryID == job.EntryID && j.Action == job.Action
+		})
+		if current < 0 || !jobs[current].At.Equal(job.At) {
+			return nil
+		}
+
+		err = s.transition(job, now)
+		if errors.Is(err, domain.ErrEntryNotFound) || errors.Is(err, domain.ErrModelNotFound) {
+			log.Printf("scheduler: dropping the %s of %s/%s: %v", job.Action, job.Model, job.EntryID, err)
+		} else if err != nil {
+			return err
+		}
+		return s.jobs.DeleteJob(job.Model, job.EntryID, job.Action)
+	})
+}
+
+func (s *Scheduler) transition(job domain.ScheduledJob, now time.Time) error {
+	applied, err := s.content.ApplyScheduled(job, now)
+	if err != nil || !applied {
+		return err
+	}
+
+	eventType := domain.EventEntryPublished
+	if job.Action == domain.ScheduleUnpublish {
+		eventType = domain.EventEntryUnpublished
+	}
+	return publish(s.events, domain.Event{
+		Type:       eventType,
+		Model:      job.Model,
+		EntryID:    job.EntryID,
+		Scheduled:  true,
+		OccurredAt: now,
+	})
+}
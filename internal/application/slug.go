@@ -0,0 +1,32 @@
+package application
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify derives the slug of a model from its name, e.g. "blog-post" for
+// "Blog Post".
+func Slugify(name string) (string, error) {
+	s := strings.TrimSpace(name)
+	if s == "" {
+		return "", fmt.Errorf("name cannot be empty")
+	}
+
+	s = strings.ToLower(s)
+	s = slugRegex.ReplaceAllString(s, "-")
+	s = strings.Trim(s, "-")
+	if s == "" {
+		return "", fmt.Errorf("name results in empty slug")
+	}
+
+	m, _ := regexp.MatchString(`^[a-z0-9-]+$`, s)
+	if !m {
+		return "", fmt.Errorf("invalid slug")
+	}
+
+	return s, nil
+}
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/google/uuid"
+)
+
+// TokenPrefix starts every API token, telling them apart from the preview
+// token of the project.
+const TokenPrefix = "vg_"
+
+// lastUsedResolution limits how often the last use of a token is saved, so
+// that busy clients do not rewrite the token file on every request.
+const lastUsedResolution = time.Minute
+
+// TokenService manages the API tokens of a project.
+type TokenService struct {
+	tokens domain.TokenRepository
+}
+
+func NewTokenService(tokens domain.TokenRepository) *TokenService {
+	return &TokenService{tokens: tokens}
+}
+
+// IsAPIToken reports whether a bearer token is an API token.
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, TokenPrefix)
+}
+
+// ParseScopes parses scopes written action:model, or the names of
+// domain.ScopePresets.
+func ParseScopes(values []string) ([]domain.Scope, error) {
+	var scopes []domain.Scope
+	for _, v := range values {
+		if preset, ok := domain.ScopePresets[v]; ok {
+			scopes = append(scopes, preset...)
+			continue
+		}
+		scope, err := domain.ParseScope(v)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+// CreateToken creates a token and returns it along with its secret value,
+// which is not stored and cannot be shown again. A zero expiresAt never
+// expires.
+func (s *TokenService) CreateToken(name string, scopes []domain.Scope, expiresAt time.Time) (domain.APIToken, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return domain.APIToken{}, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	secret := TokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	token := domain.APIToken{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		Prefix:    secret[:len(TokenPrefix)+8],
+		Hash:      hashToken(secret),
+		Scopes:    scopes,
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: expiresAt.UTC(),
+	}
+	if err := domain.ValidateAPIToken(token); err != nil {
+		return domain.APIToken{}, "", err
+	}
+	if err := s.tokens.CreateToken(token); err != nil {
+		return domain.APIToken{}, "", err
+	}
+	return token, secret, nil
+}
+
+func (s *TokenService) ListTokens() ([]domain.APIToken, error) {
+	return s.tokens.GetTokens()
+}
+
+// RevokeToken deletes the token with the given ID or prefix.
+func (s *TokenService) RevokeToken(idOrPrefix string) (domain.APIToken, error) {
+	tokens, err := s.tokens.GetTokens()
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+	for _, t := range tokens {
+		if t.ID == idOrPrefix || t.Prefix == idOrPrefix {
+			return t, s.tokens.DeleteToken(t.ID)
+		}
+	}
+	return domain.APIToken{}, fmt.Errorf("%w: %s", domain.ErrTokenNotFound, idOrPrefix)
+}
+
+// Authenticate returns the token matching secret, recording its use. It
+// fails with domain.ErrTokenNotFound for unknown, revoked and expired tokens.
+func (s *TokenService) Authenticate(secret string) (domain.APIToken, error) {
+	tokens, err := s.tokens.GetTokens()
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+
+	hash := hashToken(secret)
+	now := time.Now().UTC()
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Hash), []byte(hash)) != 1 {
+			continue
+		}
+		if t.IsExpired(now) {
+			return domain.APIToken{}, fmt.Errorf("%w: %s expired", domain.ErrTokenNotFound, t.Prefix)
+		}
+		if now.Sub(t.LastUsedAt) >= lastUsedResolution {
+			t.LastUsedAt = now
+			if err := s.tokens.UpdateToken(t); err != nil {
+				return domain.APIToken{}, err
+			}
+		}
+		return t, nil
+	}
+	return domain.APIToken{}, domain.ErrTokenNotFound
+}
+
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,21 @@
+package application
+
+// Transactor runs the transactions writing a project, serializing its
+// writers.
+type Transactor interface {
+	// Tx runs fn as a transaction, whose writes are kept when fn returns nil
+	// and discarded when it fails.
+	Tx(fn func() error) error
+	// AfterCommit runs fn once the current transaction is committed and no
+	// longer holds the project, or drops it when the transaction fails.
+	AfterCommit(fn func())
+}
+
+// runTx runs fn as a transaction of tx, or directly when tx is nil, for the
+// services used by commands that already run in one.
+func runTx(tx Transactor, fn func() error) error {
+	if tx == nil {
+		return fn()
+	}
+	return tx.Tx(fn)
+}
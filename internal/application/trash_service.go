@@ -0,0 +1,388 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// TrashedField is a field in the trash together with the model it belongs to.
+type TrashedField struct {
+	Model string
+	Field domain.Field
+}
+
+// TrashService lists, restores and permanently removes deleted models, fields
+// and entries.
+type TrashService struct {
+	models  domain.Repository
+	content domain.ContentRepository
+	aliases domain.AliasRepository
+	jobs    domain.ScheduleRepository
+	events  EventPublisher
+}
+
+// NewTrashService creates a trash service publishing an event after each
+// restore to events, which may be nil. Purges are not published, as their
+// deletion was.
+func NewTrashService(models domain.Repository, content domain.ContentRepository, aliases domain.AliasRepository,
+	jobs domain.ScheduleRepository, events EventPublisher) *TrashService {
+	return &TrashService{
+		models:  models,
+		content: content,
+		aliases: aliases,
+		jobs:    jobs,
+		events:  events,
+	}
+}
+
+func (ts *TrashService) ListModels() ([]domain.Model, error) {
+	models, err := ts.models.GetModels()
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := make([]domain.Model, 0)
+	for _, m := range models {
+		if m.IsDeleted() {
+			trashed = append(trashed, m)
+		}
+	}
+	return trashed, nil
+}
+
+// ListFields returns the trashed fields of models that are not themselves in
+// the trash.
+func (ts *TrashService) ListFields() ([]TrashedField, error) {
+	models, err := ts.models.GetModels()
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := make([]TrashedField, 0)
+	for _, m := range models {
+		if m.IsDeleted() {
+			continue
+		}
+		for _, f := range m.Fields {
+			if f.IsDeleted() {
+				trashed = append(trashed, TrashedField{Model: m.Slug, Field: f})
+			}
+		}
+	}
+	return trashed, nil
+}
+
+func (ts *TrashService) ListEntries(slug string) ([]domain.Entry, error) {
+	if _, err := ts.models.GetModel(slug); err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrModelNotFound, slug)
+	}
+
+	entries, err := ts.content.GetEntries(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := make([]domain.Entry, 0)
+	for _, e := range entries {
+		if e.IsDeleted() {
+			trashed = append(trashed, e)
+		}
+	}
+	return trashed, nil
+}
+
+// RestoreModel takes a model out of the trash as a draft.
+func (ts *TrashService) RestoreModel(slug string) (domain.Model, error) {
+	model, err := ts.trashedModel(slug)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	model.Status = domain.StatusDraft
+	model.DeletedAt = time.Time{}
+	if err := ts.models.UpdateModel(model); err != nil {
+		return domain.Model{}, err
+	}
+	if err := publish(ts.events, domain.Event{Type: domain.EventModelRestored, Model: model.Slug}); err != nil {
+		return domain.Model{}, err
+	}
+	return model, nil
+}
+
+// RestoreField takes a field out of the trash as a draft.
+func (ts *TrashService) RestoreField(slug, fieldID string) (domain.Model, error) {
+	model, i, err := ts.trashedField(slug, fieldID)
+	if err != nil {
+		return domain.Model{}, err
+	}
+
+	model.Fields[i].Status = domain.StatusDraft
+	model.Fields[i].DeletedAt = time.Time{}
+	model.Fields[i].UpdatedAt = time.Now().UTC()
+	if err := ts.models.UpdateModel(model); err != nil {
+		return domain.Model{}, err
+	}
+	if err := publish(ts.events, domain.Event{Type: domain.EventModelUpdated, Model: model.Slug}); err != nil {
+		return domain.Model{}, err
+	}
+	return model, nil
+}
+
+func (ts *TrashService) RestoreEntry(slug, id string) (domain.Entry, error) {
+	entry, err := ts.trashedEntry(slug, id)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+
+	entry.Restore()
+	if err := ts.content.UpdateEntry(entry); err != nil {
+		return domain.Entry{}, err
+	}
+	if err := publish(ts.events, domain.Event{Type: domain.EventEntryRestored, Model: entry.Model, EntryID: entry.ID}); err != nil {
+		return domain.Entry{}, err
+	}
+	return entry, nil
+}
+
+// PurgeModel permanently removes a trashed model with its entries, its
+// scheduled jobs and the aliases redirecting to it. Models still having a
+// relation to it must drop it first, a new model taking over the slug would
+// otherwise take over the relation.
+func (ts *TrashService) PurgeModel(slug string) error {
+	if _, err := ts.trashedModel(slug); err != nil {
+		return err
+	}
+	if err := ts.checkUnrelated(slug); err != nil {
+		return err
+	}
+
+	if err := ts.content.DeleteEntries(slug); err != nil {
+		return err
+	}
+
+	jobs, err := ts.jobs.GetJobs()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Model != slug {
+			continue
+		}
+		if err := ts.jobs.DeleteJob(job.Model, job.EntryID, job.Action); err != nil {
+			return err
+		}
+	}
+
+	aliases, err := ts.aliases.GetAliases()
+	if err != nil {
+		return err
+	}
+	for _, a := range aliases {
+		if a.Target != slug {
+			continue
+		}
+		if err := ts.aliases.DeleteAlias(a.Slug); err != nil {
+			return err
+		}
+	}
+
+	return ts.models.DeleteModel(slug)
+}
+
+// checkUnrelated rejects purging a model that fields of other models, even
+// trashed ones, still relate to.
+func (ts *TrashService) checkUnrelated(slug string) error {
+	models, err := ts.models.GetModels()
+	if err != nil {
+		return err
+	}
+
+	var relations []string
+	for _, m := range models {
+		if m.Slug == slug {
+			continue
+		}
+		for _, f := range m.Fields {
+			if f.Type == domain.FieldRelation && f.Target == slug {
+				relations = append(relations, fmt.Sprintf("field '%s' of model '%s' has a relation to this model", f.Name, m.Slug))
+			}
+		}
+	}
+	if len(relations) > 0 {
+		return &domain.ValidationError{Field: "Model", Message: strings.Join(relations, "; ")}
+	}
+	return nil
+}
+
+// PurgeField permanently removes a trashed field from its model and drops
+// its data from every entry.
+func (ts *TrashService) PurgeField(slug, fieldID string) error {
+	model, i, err := ts.trashedField(slug, fieldID)
+	if err != nil {
+		return err
+	}
+
+	name := model.Fields[i].Name
+	entries, err := ts.content.GetEntries(slug)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		_, inData := e.Data[name]
+		_, inPublished := e.Published[name]
+		if !inData && !inPublished {
+			continue
+		}
+		delete(e.Data, name)
+		delete(e.Published, name)
+		if err := ts.content.UpdateEntry(e); err != nil {
+			return err
+		}
+	}
+
+	model.Fields = append(model.Fields[:i], model.Fields[i+1:]...)
+	return ts.models.UpdateModel(model)
+}
+
+func (ts *TrashService) PurgeEntry(slug, id string) error {
+	if _, err := ts.trashedEntry(slug, id); err != nil {
+		return err
+	}
+	return ts.content.DeleteEntry(slug, id)
+}
+
+// PurgeDeletedBefore permanently removes everything moved to the trash
+// before cutoff. Items without a deletion timestamp, e.g. edited into the
+// trash by hand, are kept for an explicit purge.
+func (ts *TrashService) PurgeDeletedBefore(cutoff time.Time) error {
+	models, err := ts.models.GetModels()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range models {
+		if m.IsDeleted() {
+			if trashedBefore(m.DeletedAt, cutoff) {
+				err := ts.PurgeModel(m.Slug)
+				var validationErr *domain.ValidationError
+				if errors.As(err, &validationErr) {
+					log.Printf("trash: keeping model %s: %v", m.Slug, err)
+				} else if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		for _, f := range m.Fields {
+			if f.IsDeleted() && trashedBefore(f.DeletedAt, cutoff) {
+				if err := ts.PurgeField(m.Slug, f.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		entries, err := ts.ListEntries(m.Slug)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if trashedBefore(e.DeletedAt, cutoff) {
+				if err := ts.content.DeleteEntry(m.Slug, e.ID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func trashedBefore(deletedAt, cutoff time.Time) bool {
+	return !deletedAt.IsZero() && deletedAt.Before(cutoff)
+}
+
+func (ts *TrashService) trashedModel(slug string) (domain.Model, error) {
+	model, err := ts.models.GetModel(slug)
+	if err != nil || !model.IsDeleted() {
+		return domain.Model{}, fmt.Errorf("%w: %s is not in the trash", domain.ErrModelNotFound, slug)
+	}
+	return model, nil
+}
+
+func (ts *TrashService) trashedField(slug, fieldID string) (domain.Model, int, error) {
+	model, err := ts.models.GetModel(slug)
+	if err != nil || model.IsDeleted() {
+		return domain.Model{}, 0, fmt.Errorf("%w: %s", domain.ErrModelNotFound, slug)
+	}
+
+	for i, f := range model.Fields {
+		if f.ID == fieldID && f.IsDeleted() {
+			return model, i, nil
+		}
+	}
+	return domain.Model{}, 0, fmt.Errorf("%w: field %s is not in the trash", domain.ErrFieldNotFound, fieldID)
+}
+
+func (ts *TrashService) trashedEntry(slug, id string) (domain.Entry, error) {
+	entry, err := ts.content.GetEntry(slug, id)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+	if !entry.IsDeleted() {
+		return domain.Entry{}, fmt.Errorf("%w: %s is not in the trash", domain.ErrEntryNotFound, id)
+	}
+	return entry, nil
+}
+
+// TrashPurger periodically purges items that stayed in the trash longer than
+// the retention period.
+type TrashPurger struct {
+	trash     *TrashService
+	retention time.Duration
+	tx        Transactor
+	interval  time.Duration
+}
+
+// NewTrashPurger creates a purger. Items are purged in transactions of tx,
+// which must be the one serializing the other writers of models and content.
+func NewTrashPurger(trash *TrashService, retention time.Duration, tx Transactor) *TrashPurger {
+	return &TrashPurger{
+		trash:     trash,
+		retention: retention,
+		tx:        tx,
+		interval:  time.Hour,
+	}
+}
+
+// Run purges expired items at startup and then every interval until ctx is
+// cancelled. It does nothing when the retention period is not positive.
+func (p *TrashPurger) Run(ctx context.Context) {
+	if p.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		err := runTx(p.tx, func() error {
+			return p.trash.PurgeDeletedBefore(time.Now().UTC().Add(-p.retention))
+		})
+		if err != nil {
+			log.Printf("trash: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package application
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/axarus/vectrag/internal/infrastructure/filestore"
+)
+
+func TestPurgeModel(t *testing.T) {
+	trashed := testModel("authors", domain.Field{Name: "name"})
+	trashed.Status = domain.StatusDelete
+	related := testModel("posts", domain.Field{Name: "author", Type: domain.FieldRelation, Target: "authors"})
+
+	tests := []struct {
+		name       string
+		models     []domain.Model
+		wantPurged bool
+	}{
+		{name: "unrelated", models: []domain.Model{trashed}, wantPurged: true},
+		{name: "related", models: []domain.Model{trashed, related}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, models, content := newTestContentService(t, tt.models, []domain.Entry{
+				testEntry("authors", "ada", "", map[string]any{"name": "Ada"}),
+			})
+			dir := t.TempDir()
+			aliases, err := filestore.NewJSONAliasRepository(filepath.Join(dir, "aliases.json"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			jobs, err := filestore.NewJSONScheduleRepository(filepath.Join(dir, "jobs.json"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := aliases.PutAlias(domain.ModelAlias{Slug: "writers", Target: "authors"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := jobs.PutJob(domain.ScheduledJob{Model: "authors", EntryID: "ada", Action: domain.SchedulePublish, At: time.Now()}); err != nil {
+				t.Fatal(err)
+			}
+
+			err = NewTrashService(models, content, aliases, jobs, nil).PurgeModel("authors")
+			var validationErr *domain.ValidationError
+			if tt.wantPurged && err != nil {
+				t.Fatalf("PurgeModel() error = %v", err)
+			}
+			if !tt.wantPurged && !errors.As(err, &validationErr) {
+				t.Fatalf("PurgeModel() error = %v, want a validation error", err)
+			}
+
+			_, err = models.GetModel("authors")
+			if purged := err != nil; purged != tt.wantPurged {
+				t.Errorf("model purged = %v, want %v", purged, tt.wantPurged)
+			}
+			entries, err := content.GetEntries("authors")
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotAliases, err := aliases.GetAliases()
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotJobs, err := jobs.GetJobs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			left := 1
+			if tt.wantPurged {
+				left = 0
+			}
+			if len(entries) != left || len(gotAliases) != left || len(gotJobs) != left {
+				t.Errorf("left %d entries, %d aliases and %d jobs, want %d of each", len(entries), len(gotAliases), len(gotJobs), left)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// ModelTypeName returns the name of the types generated for a model from its
+// slug, e.g. "BlogPost" for "blog-post". Generated APIs and code use the same
+// names.
+func ModelTypeName(slug string) string {
+	return identifier(pascalCase(slug), "_")
+}
+
+// typeNamespaces lists the types generated for each model in the namespaces
+// their names share: the GraphQL schema, whose built-in and root types are
+// reserved, and the schemas of the OpenAPI document. Suffixes complete the
+// type name of the model.
+var typeNamespaces = []struct {
+	reserved []string
+	suffixes []string
+}{
+	{
+		reserved: []string{"Query", "Mutation", "Time", "String", "Int", "Float", "Boolean", "ID"},
+		suffixes: []string{"", "Page", "Filter", "Input"},
+	},
+	{
+		suffixes: []string{"Data", "Entry", "DraftData", "DraftEntry"},
+	},
+}
+
+// ValidateTypeNames rejects a model whose generated types would have the
+// name of a reserved type or of a type generated for another of models,
+// e.g. PostPage for both post-page and the page type of post. Trashed models
+// are included so they can be restored.
+func ValidateTypeNames(model domain.Model, models []domain.Model) error {
+	name := ModelTypeName(model.Slug)
+	for _, ns := range typeNamespaces {
+		taken := make(map[string]string)
+		for _, reserved := range ns.reserved {
+			taken[reserved] = "a built-in type"
+		}
+		for _, m := range models {
+			if m.ID == model.ID {
+				continue
+			}
+			for _, suffix := range ns.suffixes {
+				taken[ModelTypeName(m.Slug)+suffix] = "generated for model " + m.Slug
+			}
+		}
+
+		for _, suffix := range ns.suffixes {
+			if owner, ok := taken[name+suffix]; ok {
+				return &domain.ValidationError{
+					Field:   "Slug",
+					Message: fmt.Sprintf("'%s' generates the type %s, which is already %s", model.Slug, name+suffix, owner),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pascalCase joins the words of s, split on any character that is not a
+// letter or a digit, capitalizing each of them.
+func pascalCase(s string) string {
+	var name strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return name.String()
+}
+
+// identifier makes s a valid identifier in most languages by replacing
+// unsupported characters with underscores and prefixing it when it does not
+// start with a letter.
+func identifier(s, prefix string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '_' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))) {
+			return r
+		}
+		return '_'
+	}, s)
+	if s == "" || !unicode.IsLetter(rune(s[0])) && s[0] != '_' {
+		s = prefix + s
+	}
+	return s
+}
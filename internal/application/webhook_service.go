@@ -0,0 +1,354 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultWebhookInterval = time.Second
+	// webhookFirstRetry is the delay before the second attempt of a
+	// delivery, doubling after each failure up to webhookMaxRetry.
+	webhookFirstRetry  = 10 * time.Second
+	webhookMaxRetry    = time.Hour
+	webhookMaxAttempts = 8
+	// webhookKeptDeliveries is how many finished deliveries are kept in the
+	// delivery log.
+	webhookKeptDeliveries = 500
+)
+
+// WebhookSender sends the requests of webhooks.
+type WebhookSender interface {
+	// Send POSTs body to url with the given headers and returns the status
+	// code of the response.
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
+
+// WebhookDispatcher sends events to the webhooks of a project. Events are
+// queued in a DeliveryRepository when published, so deliveries survive
+// restarts and events published by the vectrag commands are sent by the
+// running server. Failed deliveries are retried with exponential backoff.
+type WebhookDispatcher struct {
+	hooks      []domain.Webhook
+	deliveries domain.DeliveryRepository
+	sender     WebhookSender
+	tx         Transactor
+	interval   time.Duration
+	wake       chan struct{}
+}
+
+// NewWebhookDispatcher creates a dispatcher for hooks. Publish must be called
+// in a transaction of tx, which Run runs to access deliveries. Dispatchers
+// that only queue events, and are never run, need neither sender nor tx.
+func NewWebhookDispatcher(hooks []domain.Webhook, deliveries domain.DeliveryRepository, sender WebhookSender, tx Transactor) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		hooks:      hooks,
+		deliveries: deliveries,
+		sender:     sender,
+		tx:         tx,
+		interval:   defaultWebhookInterval,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// WebhooksFromConfig builds the webhooks defined in the project config,
+// sorted by name.
+func WebhooksFromConfig(cfg ProjectWebhooks) ([]domain.Webhook, error) {
+	hooks := make([]domain.Webhook, 0, len(cfg))
+	for name, wc := range cfg {
+		hook := domain.Webhook{Name: name, URL: wc.URL, Models: wc.Models, Secret: wc.Secret}
+		for _, e := range wc.Events {
+			hook.Events = append(hook.Events, domain.EventType(e))
+		}
+		if err := domain.ValidateWebhook(hook); err != nil {
+			return nil, fmt.Errorf("invalid webhooks in vectrag.config.yaml: %w", err)
+		}
+		hooks = append(hooks, hook)
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].Name < hooks[j].Name })
+	return hooks, nil
+}
+
+// WebhookSignature returns the hex encoded HMAC-SHA256 of the timestamp and
+// the body of a request, joined by a dot, sent as sha256=<signature> in the
+// X-Vectrag-Signature header.
+func WebhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Webhooks returns the webhooks of the project, sorted by name.
+func (d *WebhookDispatcher) Webhooks() []domain.Webhook {
+	return d.hooks
+}
+
+// Publish queues e for the webhooks it matches, in the transaction of the
+// write it reports.
+func (d *WebhookDispatcher) Publish(e domain.Event) error {
+	queued := false
+	for _, hook := range d.hooks {
+		if !hook.Matches(e) {
+			continue
+		}
+		now := time.Now().UTC()
+		err := d.deliveries.CreateDelivery(domain.WebhookDelivery{
+			ID:            uuid.New().String(),
+			Webhook:       hook.Name,
+			Event:         e,
+			Status:        domain.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			return fmt.Errorf("queueing %s for %s: %w", e.Type, hook.Name, err)
+		}
+		queued = true
+	}
+	if queued {
+		d.notify()
+	}
+	return nil
+}
+
+// Deliveries returns the deliveries matching filter, newest first.
+func (d *WebhookDispatcher) Deliveries(filter domain.DeliveryFilter, limit int) ([]domain.WebhookDelivery, error) {
+	return d.deliveries.GetDeliveries(filter, limit)
+}
+
+func (d *WebhookDispatcher) Delivery(id string) (domain.WebhookDelivery, error) {
+	return d.deliveries.GetDelivery(id)
+}
+
+// Retry sends a delivery again as soon as possible, with a new set of
+// attempts. It runs in a transaction of its own, and must not be called in
+// one.
+func (d *WebhookDispatcher) Retry(id string) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := runTx(d.tx, func() error {
+		var err error
+		delivery, err = d.deliveries.GetDelivery(id)
+		if err != nil {
+			return err
+		}
+		if _, ok := d.hook(delivery.Webhook); !ok {
+			return fmt.Errorf("%w: %s", domain.ErrWebhookNotFound, delivery.Webhook)
+		}
+
+		delivery.Status = domain.DeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = time.Now().UTC()
+		return d.deliveries.UpdateDelivery(delivery)
+	})
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	d.notify()
+	return delivery, nil
+}
+
+// Run sends due deliveries until ctx is cancelled.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DeliverDue(ctx, time.Now().UTC()); err != nil {
+			log.Printf("webhooks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DeliverDue attempts every pending delivery due at or before now. Webhooks
+// are sent their deliveries in parallel, each in the order of its events: a
+// delivery that fails, or waits for its retry, holds back the later
+// deliveries of its webhook.
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context, now time.Time) error {
+	var pending []domain.WebhookDelivery
+	err := runTx(d.tx, func() error {
+		var err error
+		pending, err = d.deliveries.GetDeliveries(domain.DeliveryFilter{Status: domain.DeliveryPending}, 0)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	due := make(map[string][]domain.WebhookDelivery)
+	waiting := make(map[string]bool)
+	for i := len(pending) - 1; i >= 0; i-- {
+		name := pending[i].Webhook
+		if waiting[name] {
+			continue
+		}
+		if pending[i].NextAttemptAt.After(now) {
+			waiting[name] = true
+			continue
+		}
+		due[name] = append(due[name], pending[i])
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for name, deliveries := range due {
+		hook, ok := d.hook(name)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, delivery := range deliveries {
+				if ctx.Err() != nil {
+					return
+				}
+				if !ok {
+					d.abandon(delivery)
+					continue
+				}
+				if !d.attempt(ctx, hook, delivery) {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return runTx(d.tx, func() error {
+		return d.deliveries.PruneDeliveries(webhookKeptDeliveries)
+	})
+}
+
+type webhookPayload struct {
+	ID            string           `json:"id"`
+	Event         domain.EventType `json:"event"`
+	Model         string           `json:"model"`
+	EntryID       string           `json:"entryId,omitempty"`
+	PreviousModel string           `json:"previousModel,omitempty"`
+	Scheduled     bool             `json:"scheduled,omitempty"`
+	OccurredAt    time.Time        `json:"occurredAt"`
+}
+
+// attempt sends a delivery once and records the outcome. It reports whether
+// the delivery is done with, sent or given up, so the next one may be sent.
+// Deliveries interrupted by the shutdown of the server stay due.
+func (d *WebhookDispatcher) attempt(ctx context.Context, hook domain.Webhook, delivery domain.WebhookDelivery) bool {
+	body, err := json.Marshal(webhookPayload{
+		ID:            delivery.ID,
+		Event:         delivery.Event.Type,
+		Model:         delivery.Event.Model,
+		EntryID:       delivery.Event.EntryID,
+		PreviousModel: delivery.Event.PreviousModel,
+		Scheduled:     delivery.Event.Scheduled,
+		OccurredAt:    delivery.Event.OccurredAt,
+	})
+	if err != nil {
+		log.Printf("webhooks: failed to encode delivery %s: %v", delivery.ID, err)
+		return false
+	}
+
+	now := time.Now().UTC()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	headers := map[string]string{
+		"Content-Type":        "application/json",
+		"X-Vectrag-Event":     string(delivery.Event.Type),
+		"X-Vectrag-Delivery":  delivery.ID,
+		"X-Vectrag-Timestamp": timestamp,
+	}
+	if hook.Secret != "" {
+		headers["X-Vectrag-Signature"] = "sha256=" + WebhookSignature(hook.Secret, timestamp, body)
+	}
+
+	status, err := d.sender.Send(ctx, hook.URL, headers, body)
+	if ctx.Err() != nil {
+		return false
+	}
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = now
+	delivery.ResponseStatus = status
+	delivery.LastError = ""
+	if err != nil {
+		delivery.LastError = err.Error()
+	} else if status < 200 || status > 299 {
+		delivery.LastError = fmt.Sprintf("unexpected response status %d", status)
+	}
+
+	switch {
+	case delivery.LastError == "":
+		delivery.Status = domain.DeliverySucceeded
+		delivery.NextAttemptAt = time.Time{}
+	case delivery.Attempts >= webhookMaxAttempts:
+		delivery.Status = domain.DeliveryFailed
+		delivery.NextAttemptAt = time.Time{}
+		log.Printf("webhooks: giving up %s for %s after %d attempts: %s", delivery.Event.Type, hook.Name, delivery.Attempts, delivery.LastError)
+	default:
+		delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+	}
+	d.update(delivery)
+	return delivery.Status != domain.DeliveryPending
+}
+
+// abandon fails the deliveries of webhooks removed from the config.
+func (d *WebhookDispatcher) abandon(delivery domain.WebhookDelivery) {
+	delivery.Status = domain.DeliveryFailed
+	delivery.NextAttemptAt = time.Time{}
+	delivery.LastError = "webhook is no longer configured"
+	d.update(delivery)
+}
+
+func (d *WebhookDispatcher) update(delivery domain.WebhookDelivery) {
+	err := runTx(d.tx, func() error {
+		return d.deliveries.UpdateDelivery(delivery)
+	})
+	if err != nil {
+		log.Printf("webhooks: failed to save delivery %s: %v", delivery.ID, err)
+	}
+}
+
+func (d *WebhookDispatcher) hook(name string) (domain.Webhook, bool) {
+	for _, hook := range d.hooks {
+		if hook.Name == name {
+			return hook, true
+		}
+	}
+	return domain.Webhook{}, false
+}
+
+// notify wakes Run up, without blocking when it is already awake.
+func (d *WebhookDispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// webhookBackoff returns the delay before the next attempt of a delivery
+// after the given number of failed attempts.
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookFirstRetry
+	for i := 1; i < attempts && delay < webhookMaxRetry; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxRetry)
+}
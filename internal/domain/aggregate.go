@@ -0,0 +1,67 @@
+package domain
+
+import "fmt"
+
+type AggregateFunc string
+
+const (
+	AggSum AggregateFunc = "sum"
+	AggAvg AggregateFunc = "avg"
+	AggMin AggregateFunc = "min"
+	AggMax AggregateFunc = "max"
+)
+
+type Aggregation struct {
+	Func  AggregateFunc
+	Field string
+}
+
+// AggregateQuery computes aggregations over the entries matching Filter,
+// per distinct combination of the GroupBy fields. Entries are always counted.
+type AggregateQuery struct {
+	Filter       *Filter
+	GroupBy      []string
+	Aggregations []Aggregation
+	// Published aggregates the published version of published entries
+	// instead of the draft version of all entries.
+	Published bool
+}
+
+type AggregateGroup struct {
+	// Key holds the value of each GroupBy field for the group.
+	Key    map[string]any
+	Count  int
+	Values map[Aggregation]any
+}
+
+// AggregateRepository is implemented by content repositories able to compute
+// aggregations in their storage. Others are aggregated in memory.
+type AggregateRepository interface {
+	AggregateEntries(model string, q AggregateQuery) ([]AggregateGroup, error)
+}
+
+// ValidateAggregation checks that fn applies to fields of type t. Custom field
+// types are aggregated by the type of their values.
+func ValidateAggregation(fn AggregateFunc, t FieldType) error {
+	switch fn {
+	case AggSum, AggAvg:
+		if FieldValueType(t) != ValueNumber {
+			return fmt.Errorf("%s only applies to fields with number values", fn)
+		}
+	case AggMin, AggMax:
+		if t == FieldText || t == FieldRelation || FieldValueType(t) == ValueBoolean {
+			return fmt.Errorf("%s does not apply to %s fields", fn, t)
+		}
+	default:
+		return fmt.Errorf("unknown aggregation '%s'", fn)
+	}
+	return nil
+}
+
+// ValidateGroupBy checks that entries can be grouped by fields of type t.
+func ValidateGroupBy(t FieldType) error {
+	if t == FieldText {
+		return fmt.Errorf("cannot group by text fields")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "testing"
+
+// ratingType is a custom field type with number values.
+type ratingType struct{}
+
+func (ratingType) Type() FieldType                        { return "rating" }
+func (ratingType) ValueType() ValueType                   { return ValueNumber }
+func (ratingType) ValidateOptions(Field) error            { return nil }
+func (ratingType) Encode(_ Field, value any) (any, error) { return value, nil }
+func (ratingType) Decode(_ Field, stored any) any         { return stored }
+func (ratingType) ValidateValue(Field, any) error         { return nil }
+func (ratingType) Column() ColumnType                     { return ColumnFloat }
+func (ratingType) UI() FieldUI                            { return FieldUI{Widget: "number"} }
+
+func TestValidateAggregation(t *testing.T) {
+	if err := RegisterFieldType(ratingType{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		fn      AggregateFunc
+		typ     FieldType
+		wantErr bool
+	}{
+		{AggSum, FieldNumber, false},
+		{AggAvg, "rating", false},
+		{AggSum, FieldString, true},
+		{AggAvg, FieldBoolean, true},
+		{AggMin, FieldNumber, false},
+		{AggMax, "rating", false},
+		{AggMin, FieldString, false},
+		{AggMax, FieldDateTime, false},
+		{AggMin, FieldText, true},
+		{AggMax, FieldRelation, true},
+		{AggMin, FieldBoolean, true},
+		{"median", FieldNumber, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.fn)+" "+string(tt.typ), func(t *testing.T) {
+			if err := ValidateAggregation(tt.fn, tt.typ); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAggregation() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
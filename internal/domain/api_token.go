@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Action is what a request does to a model, checked against the scopes of
+// API tokens.
+type Action string
+
+const (
+	// ActionRead reads published entries.
+	ActionRead Action = "read"
+	// ActionPreview reads drafts.
+	ActionPreview Action = "preview"
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	// ActionPublish publishes, unpublishes and schedules entries.
+	ActionPublish Action = "publish"
+	// ActionSchema manages models, their trash and migrations.
+	ActionSchema Action = "schema"
+	// ActionAdmin manages API tokens and reads the webhook delivery log.
+	// Tokens may only grant the scopes they hold.
+	ActionAdmin Action = "admin"
+)
+
+var actions = []Action{ActionRead, ActionPreview, ActionCreate, ActionUpdate, ActionDelete, ActionPublish, ActionSchema, ActionAdmin}
+
+// AnyModel matches every model in scopes, and stands for requests that are
+// not about a single model, such as listing models.
+const AnyModel = "*"
+
+// Scope allows an action on a model, written action:model. Either may be *.
+type Scope struct {
+	Action Action
+	Model  string
+}
+
+// ScopePresets are the named sets of scopes offered when creating tokens.
+var ScopePresets = map[string][]Scope{
+	"read-only":   {{Action: ActionRead, Model: AnyModel}},
+	"full-access": {{Action: "*", Model: AnyModel}},
+}
+
+func ParseScope(s string) (Scope, error) {
+	action, model, ok := strings.Cut(s, ":")
+	if !ok || action == "" || model == "" {
+		return Scope{}, &ValidationError{Field: "scopes", Message: fmt.Sprintf("%q must be written action:model", s)}
+	}
+	if action != "*" && !slices.Contains(actions, Action(action)) {
+		return Scope{}, &ValidationError{Field: "scopes", Message: fmt.Sprintf("unknown action %q in %q", action, s)}
+	}
+	return Scope{Action: Action(action), Model: model}, nil
+}
+
+func (s Scope) String() string {
+	return string(s.Action) + ":" + s.Model
+}
+
+// Allows reports whether the scope covers action on model. Only scopes for
+// any model cover requests about any model.
+func (s Scope) Allows(action Action, model string) bool {
+	return (s.Action == "*" || s.Action == action) && (s.Model == AnyModel || s.Model == model)
+}
+
+// APIToken gives machine clients access to the APIs, limited to its scopes.
+// Only a hash of the token is stored; it is shown once when created.
+type APIToken struct {
+	ID   string
+	Name string
+	// Prefix is the start of the token, telling tokens apart in listings.
+	Prefix string
+	Hash   string
+	Scopes []Scope
+	// ExpiresAt is zero for tokens that never expire, LastUsedAt for
+	// tokens never used.
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+}
+
+func (t APIToken) IsExpired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+func (t APIToken) Allows(action Action, model string) bool {
+	for _, s := range t.Scopes {
+		if s.Allows(action, model) {
+			return true
+		}
+	}
+	return false
+}
+
+type TokenRepository interface {
+	CreateToken(t APIToken) error
+	UpdateToken(t APIToken) error
+	DeleteToken(id string) error
+	GetTokens() ([]APIToken, error)
+}
+
+func ValidateAPIToken(t APIToken) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return &ValidationError{Field: "name", Message: "is required"}
+	}
+	if len(t.Scopes) == 0 {
+		return &ValidationError{Field: "scopes", Message: "at least one scope is required"}
+	}
+	if !t.ExpiresAt.IsZero() && !t.ExpiresAt.After(t.CreatedAt) {
+		return &ValidationError{Field: "expiresAt", Message: "must be in the future"}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+// Change is an event recorded in the change log of a project. Seq numbers
+// changes in the order they happened, starting at 1, and is never reused.
+type Change struct {
+	Seq   int64
+	Event Event
+}
+
+type ChangeRepository interface {
+	// AppendChange records e after the last change, keeping at most keep
+	// changes besides the ones a cursor has not moved past, and returns it
+	// numbered.
+	AppendChange(e Event, keep int) (Change, error)
+	// GetChanges returns the changes numbered after seq, oldest first, along
+	// with the number of the oldest change still recorded, or of the next
+	// change when none is.
+	GetChanges(after int64) (changes []Change, oldest int64, err error)
+	// LastSeq returns the number of the last change, zero when there is none.
+	LastSeq() (int64, error)
+
+	// GetCursors returns the number of the last change each reader of the
+	// log consumed, by reader name.
+	GetCursors() (map[string]int64, error)
+	// SetCursor records that the reader name consumed the changes up to seq.
+	SetCursor(name string, seq int64) error
+	// DeleteCursor forgets a reader, letting the changes it has not consumed
+	// be dropped.
+	DeleteCursor(name string) error
+}
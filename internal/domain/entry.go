@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is a single piece of content stored for a model. Data holds the draft
+// version and Published the version public readers see, both keyed by field
+// name. Published is nil until the entry is published for the first time.
+// PublishAt and UnpublishAt hold pending scheduled transitions, if any.
+// CreatedBy identifies the admin user or API token that created the entry,
+// empty for entries created anonymously or before it was recorded.
+type Entry struct {
+	ID          string
+	Model       string
+	Data        map[string]any
+	Published   map[string]any
+	Status      Status
+	PublishedAt time.Time
+	PublishAt   time.Time
+	UnpublishAt time.Time
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   time.Time
+}
+
+func (e Entry) IsDeleted() bool {
+	return e.Status == StatusDelete
+}
+
+// Trash moves the entry to the trash. Its draft and published versions are
+// kept so it can be restored.
+func (e *Entry) Trash(at time.Time) {
+	e.Status = StatusDelete
+	e.DeletedAt = at
+}
+
+// Restore takes the entry out of the trash.
+func (e *Entry) Restore() {
+	e.Status = StatusDraft
+	if e.Published != nil {
+		e.Status = StatusPublish
+	}
+	e.DeletedAt = time.Time{}
+}
+
+// Publish replaces the published version with a copy of the draft.
+func (e *Entry) Publish(at time.Time) {
+	e.Published = make(map[string]any, len(e.Data))
+	for k, v := range e.Data {
+		e.Published[k] = v
+	}
+	e.Status = StatusPublish
+	e.PublishedAt = at
+}
+
+// Unpublish drops the published version, the draft is kept.
+func (e *Entry) Unpublish() {
+	e.Published = nil
+	e.Status = StatusDraft
+	e.PublishedAt = time.Time{}
+}
+
+func (e Entry) IsPublished() bool {
+	return e.Published != nil && !e.IsDeleted()
+}
+
+// PublishedVersion returns the entry as public readers see it, with Data set
+// to the published version. It reports false if the entry is not published.
+func (e Entry) PublishedVersion() (Entry, bool) {
+	if !e.IsPublished() {
+		return Entry{}, false
+	}
+	e.Data = e.Published
+	return e, true
+}
+
+func ValidateEntry(m Model, e Entry) error {
+	var errors []string
+
+	if err := validateID(e.ID); err != nil {
+		errors = append(errors, fmt.Sprintf("ID: %v", err))
+	}
+
+	if e.Model != m.Slug {
+		errors = append(errors, fmt.Sprintf("Model: expected '%s', got '%s'", m.Slug, e.Model))
+	}
+
+	if err := ValidateStatus(e.Status); err != nil {
+		errors = append(errors, fmt.Sprintf("Status: %v", err))
+	}
+
+	known := make(map[string]bool, len(m.Fields))
+	for _, field := range m.Fields {
+		known[field.Name] = true
+		// Data of trashed fields is retained but no longer validated.
+		if field.IsDeleted() {
+			continue
+		}
+
+		value, ok := e.Data[field.Name]
+		if !ok || value == nil {
+			if field.Required {
+				errors = append(errors, fmt.Sprintf("%s: is required", field.Name))
+			}
+			continue
+		}
+
+		if err := validateValue(field, value); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", field.Name, err))
+		}
+	}
+
+	var unknown []string
+	for name := range e.Data {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		errors = append(errors, fmt.Sprintf("%s: unknown field", name))
+	}
+
+	if len(errors) > 0 {
+		return &ValidationError{
+			Field:   "Entry",
+			Message: strings.Join(errors, "; "),
+		}
+	}
+
+	return nil
+}
+
+// ValidateWritableData rejects data written to deleted fields. Their stored
+// data is retained, but they no longer accept new values.
+func ValidateWritableData(m Model, data map[string]any) error {
+	var errors []string
+	for _, field := range m.Fields {
+		if _, ok := data[field.Name]; ok && field.IsDeleted() {
+			errors = append(errors, fmt.Sprintf("%s: field is deleted and cannot be written", field.Name))
+		}
+	}
+
+	if len(errors) > 0 {
+		return &ValidationError{
+			Field:   "Entry",
+			Message: strings.Join(errors, "; "),
+		}
+	}
+
+	return nil
+}
+
+func validateValue(f Field, value any) error {
+	p, ok := LookupFieldType(f.Type)
+	if !ok {
+		return fmt.Errorf("field type '%s' is not registered", f.Type)
+	}
+	return p.ValidateValue(f, value)
+}
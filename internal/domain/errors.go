@@ -7,6 +7,20 @@ var (
 	ErrModelAlreadyExists = fmt.Errorf("model already exists")
 	ErrInvalidModel       = fmt.Errorf("invalid model")
 	ErrInvalidField       = fmt.Errorf("invalid field")
+	ErrFieldNotFound      = fmt.Errorf("field not found")
+	ErrEntryNotFound      = fmt.Errorf("entry not found")
+	ErrEntryAlreadyExists = fmt.Errorf("entry already exists")
+	ErrUnsafeTypeChange   = fmt.Errorf("unsafe field type change")
+	ErrUserNotFound       = fmt.Errorf("user not found")
+	ErrUserAlreadyExists  = fmt.Errorf("user already exists")
+	ErrSessionNotFound    = fmt.Errorf("session not found")
+	ErrInvalidCredentials = fmt.Errorf("invalid username or password")
+	ErrTokenNotFound      = fmt.Errorf("API token not found")
+	ErrRoleNotFound       = fmt.Errorf("role not found")
+	ErrForbidden          = fmt.Errorf("permission denied")
+	ErrWebhookNotFound    = fmt.Errorf("webhook not found")
+	ErrDeliveryNotFound   = fmt.Errorf("webhook delivery not found")
+	ErrFieldTypeExists    = fmt.Errorf("field type already registered")
 )
 
 type ValidationError struct {
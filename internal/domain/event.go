@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type EventType string
+
+const (
+	EventModelCreated     EventType = "model.created"
+	EventModelUpdated     EventType = "model.updated"
+	EventModelDeleted     EventType = "model.deleted"
+	EventModelRestored    EventType = "model.restored"
+	EventEntryCreated     EventType = "entry.created"
+	EventEntryUpdated     EventType = "entry.updated"
+	EventEntryDeleted     EventType = "entry.deleted"
+	EventEntryRestored    EventType = "entry.restored"
+	EventEntryPublished   EventType = "entry.published"
+	EventEntryUnpublished EventType = "entry.unpublished"
+)
+
+// EventTypes lists every event type, in the order they are documented.
+var EventTypes = []EventType{
+	EventModelCreated, EventModelUpdated, EventModelDeleted, EventModelRestored,
+	EventEntryCreated, EventEntryUpdated, EventEntryDeleted, EventEntryRestored,
+	EventEntryPublished, EventEntryUnpublished,
+}
+
+// Event describes a change that already happened. EntryID is empty for
+// model events, and is the model slug for the entries of single models.
+// PreviousModel holds the former slug of renamed models.
+type Event struct {
+	Type          EventType
+	Model         string
+	EntryID       string
+	PreviousModel string
+	Scheduled     bool
+	OccurredAt    time.Time
+}
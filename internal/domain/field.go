@@ -11,12 +11,33 @@ type Field struct {
 	ID          string
 	Name        string
 	Type        FieldType
+	Target      string
+	Options     map[string]any
 	Description string
 	Unique      bool
 	Required    bool
 	Status      Status
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	DeletedAt   time.Time
+}
+
+func (f Field) IsDeleted() bool {
+	return f.Status == StatusDelete
+}
+
+// IsVisible reports whether readers see the field's data. Public readers only
+// see published fields, previews also see draft fields. Deleted fields are
+// never visible.
+func (f Field) IsVisible(preview bool) bool {
+	switch f.Status {
+	case StatusPublish:
+		return true
+	case StatusDraft:
+		return preview
+	default:
+		return false
+	}
 }
 
 func ValidateField(f Field) error {
@@ -35,8 +56,17 @@ func ValidateField(f Field) error {
 		errors = append(errors, "Name: cannot be empty")
 	}
 
-	if !IsValidType(string(f.Type)) {
+	if p, ok := LookupFieldType(f.Type); !ok {
 		errors = append(errors, fmt.Sprintf("Type: '%s' is not a valid field type", f.Type))
+	} else if err := p.ValidateOptions(f); err != nil {
+		errors = append(errors, fmt.Sprintf("Options: %v", err))
+	}
+
+	if f.Type == FieldRelation && strings.TrimSpace(f.Target) == "" {
+		errors = append(errors, "Target: relation fields must define a target model")
+	}
+	if f.Type != FieldRelation && f.Target != "" {
+		errors = append(errors, "Target: only relation fields can define a target model")
 	}
 
 	if err := ValidateStatus(f.Status); err != nil {
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConversionSafety classifies the change of the type of a field by what
+// happens to the values already stored.
+type ConversionSafety string
+
+const (
+	// ConversionSafe changes convert every value without loss.
+	ConversionSafe ConversionSafety = "safe"
+	// ConversionLossy changes convert values that lose information, or that
+	// may fail to convert.
+	ConversionLossy ConversionSafety = "lossy"
+	// ConversionImpossible changes cannot convert any value.
+	ConversionImpossible ConversionSafety = "impossible"
+)
+
+// ClassifyTypeChange tells how values of type from convert to type to.
+func ClassifyTypeChange(from, to FieldType) ConversionSafety {
+	if from == to {
+		return ConversionSafe
+	}
+
+	switch to {
+	case FieldString, FieldText:
+		// Every value has a textual form, relations their ID.
+		return ConversionSafe
+	case FieldNumber:
+		switch from {
+		case FieldBoolean:
+			return ConversionSafe
+		case FieldString, FieldText:
+			return ConversionLossy
+		}
+	case FieldBoolean:
+		switch from {
+		case FieldString, FieldText, FieldNumber:
+			return ConversionLossy
+		}
+	case FieldDate:
+		switch from {
+		case FieldString, FieldText, FieldDateTime:
+			return ConversionLossy
+		}
+	case FieldDateTime:
+		switch from {
+		case FieldDate:
+			return ConversionSafe
+		case FieldString, FieldText:
+			return ConversionLossy
+		}
+	}
+	return ConversionImpossible
+}
+
+// ConvertValue converts a value stored for a field of type from to type to.
+// Null stays null.
+func ConvertValue(value any, from, to FieldType) (any, error) {
+	if value == nil || from == to {
+		return value, nil
+	}
+	if ClassifyTypeChange(from, to) == ConversionImpossible {
+		return nil, fmt.Errorf("%s values cannot be converted to %s", from, to)
+	}
+
+	switch to {
+	case FieldString, FieldText:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		}
+	case FieldNumber:
+		switch v := value.(type) {
+		case bool:
+			if v {
+				return float64(1), nil
+			}
+			return float64(0), nil
+		case string:
+			n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a number", v)
+			}
+			return n, nil
+		}
+	case FieldBoolean:
+		switch v := value.(type) {
+		case float64:
+			switch v {
+			case 0:
+				return false, nil
+			case 1:
+				return true, nil
+			}
+			return nil, fmt.Errorf("%v is neither 0 nor 1", v)
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a boolean", v)
+			}
+			return b, nil
+		}
+	case FieldDate:
+		if s, ok := value.(string); ok {
+			s = strings.TrimSpace(s)
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return t.Format(time.DateOnly), nil
+			}
+			if _, err := time.Parse(time.DateOnly, s); err == nil {
+				return s, nil
+			}
+			return nil, fmt.Errorf("%q is not a date", s)
+		}
+	case FieldDateTime:
+		if s, ok := value.(string); ok {
+			s = strings.TrimSpace(s)
+			if t, err := time.Parse(time.DateOnly, s); err == nil {
+				return t.Format(time.RFC3339), nil
+			}
+			if _, err := time.Parse(time.RFC3339, s); err == nil {
+				return s, nil
+			}
+			return nil, fmt.Errorf("%q is not a datetime", s)
+		}
+	}
+	return nil, fmt.Errorf("%v is not a valid %s value", value, from)
+}
+
+// ValidateValue checks that value can be stored in field f. Null is only
+// accepted by fields that are not required.
+func ValidateValue(f Field, value any) error {
+	if value == nil {
+		if f.Required {
+			return fmt.Errorf("is required")
+		}
+		return nil
+	}
+	return validateValue(f, value)
+}
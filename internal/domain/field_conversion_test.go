@@ -0,0 +1,66 @@
+package domain
+
+import "testing"
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		from, to FieldType
+		want     any
+		wantErr  bool
+	}{
+		{"null stays null", nil, FieldString, FieldNumber, nil, false},
+		{"number to string", 1.5, FieldNumber, FieldString, "1.5", false},
+		{"boolean to text", true, FieldBoolean, FieldText, "true", false},
+		{"relation to string", "abc", FieldRelation, FieldString, "abc", false},
+		{"string to number", " 42 ", FieldString, FieldNumber, 42.0, false},
+		{"string to number fails", "forty", FieldString, FieldNumber, nil, true},
+		{"boolean to number", true, FieldBoolean, FieldNumber, 1.0, false},
+		{"number to boolean", 0.0, FieldNumber, FieldBoolean, false, false},
+		{"number to boolean fails", 2.0, FieldNumber, FieldBoolean, nil, true},
+		{"string to boolean", "TRUE", FieldString, FieldBoolean, true, false},
+		{"datetime to date", "2026-03-04T10:00:00Z", FieldDateTime, FieldDate, "2026-03-04", false},
+		{"string to date fails", "March", FieldString, FieldDate, nil, true},
+		{"date to datetime", "2026-03-04", FieldDate, FieldDateTime, "2026-03-04T00:00:00Z", false},
+		{"impossible", 1.0, FieldNumber, FieldRelation, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertValue(tt.value, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertValue() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ConvertValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyTypeChange(t *testing.T) {
+	tests := []struct {
+		from, to FieldType
+		want     ConversionSafety
+	}{
+		{FieldNumber, FieldNumber, ConversionSafe},
+		{FieldNumber, FieldString, ConversionSafe},
+		{FieldRelation, FieldText, ConversionSafe},
+		{FieldBoolean, FieldNumber, ConversionSafe},
+		{FieldDate, FieldDateTime, ConversionSafe},
+		{FieldString, FieldNumber, ConversionLossy},
+		{FieldNumber, FieldBoolean, ConversionLossy},
+		{FieldDateTime, FieldDate, ConversionLossy},
+		{FieldNumber, FieldDate, ConversionImpossible},
+		{FieldString, FieldRelation, ConversionImpossible},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := ClassifyTypeChange(tt.from, tt.to); got != tt.want {
+				t.Errorf("ClassifyTypeChange() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,14 @@
 package domain
 
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
 type FieldType string
 
 const (
@@ -12,26 +21,299 @@ const (
 	FieldRelation FieldType = "relation"
 )
 
-var fieldTypeRegistry = map[FieldType]struct{}{
-	FieldString:   {},
-	FieldText:     {},
-	FieldNumber:   {},
-	FieldBoolean:  {},
-	FieldDate:     {},
-	FieldDateTime: {},
-	FieldRelation: {},
+// ValueType is the JSON type of the values API clients read and write for a
+// field type. Filters, GraphQL, JSON Schemas and generated code handle the
+// values of custom field types as values of their ValueType.
+type ValueType string
+
+const (
+	ValueString  ValueType = "string"
+	ValueNumber  ValueType = "number"
+	ValueBoolean ValueType = "boolean"
+)
+
+// ColumnType is the kind of SQL column the values of a field type are stored
+// in. Each SQL dialect maps it to one of its column types.
+type ColumnType string
+
+const (
+	ColumnText      ColumnType = "text"
+	ColumnFloat     ColumnType = "float"
+	ColumnInteger   ColumnType = "integer"
+	ColumnBoolean   ColumnType = "boolean"
+	ColumnDate      ColumnType = "date"
+	ColumnTimestamp ColumnType = "timestamp"
+)
+
+// FieldUI holds the hints of the admin panel for editing the values of a
+// field type.
+type FieldUI struct {
+	// Widget names the input editing the values, e.g. text, textarea,
+	// number, checkbox, date, datetime, relation, color or select.
+	Widget      string
+	Placeholder string
+}
+
+// FieldTypePlugin defines a field type. The built-in types are plugins, and
+// programs embedding VectraG add their own with RegisterFieldType.
+//
+// Values written by API clients are encoded, then validated and stored in
+// their encoded form, and decoded when read. Filter values are encoded the
+// same way before they are compared with the stored values, so encoding must
+// keep values in order for sorting and range filters to be meaningful.
+type FieldTypePlugin interface {
+	// Type is the name fields of the type are declared with.
+	Type() FieldType
+	// ValueType is the JSON type of the values clients read and write.
+	ValueType() ValueType
+	// ValidateOptions checks the Options of a field of the type, e.g. the
+	// currency of a money field, when its model is saved.
+	ValidateOptions(f Field) error
+	// Encode converts a value written to field f to the value stored, a
+	// string, float64 or bool as stored values are read back from JSON. Its
+	// error rejects the value, and is reported to the client.
+	Encode(f Field, value any) (any, error)
+	// Decode converts a value stored for field f back to the value read by
+	// clients. Values it does not recognize are returned unchanged.
+	Decode(f Field, stored any) any
+	// ValidateValue checks a stored, non-null value of field f.
+	ValidateValue(f Field, stored any) error
+	// Column is the kind of SQL column the stored values fit in.
+	Column() ColumnType
+	// UI returns the hints of the admin panel for editing the values.
+	UI() FieldUI
+}
+
+var fieldTypeName = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+var (
+	fieldTypesMu      sync.RWMutex
+	fieldTypeRegistry = map[FieldType]FieldTypePlugin{}
+)
+
+func init() {
+	for _, p := range builtinFieldTypes {
+		fieldTypeRegistry[p.Type()] = p
+	}
+}
+
+// RegisterFieldType adds a custom field type. Types must be registered before
+// the models using them are loaded, and cannot be replaced once registered.
+func RegisterFieldType(p FieldTypePlugin) error {
+	t := p.Type()
+	if !fieldTypeName.MatchString(string(t)) {
+		return &ValidationError{Field: "Type", Message: fmt.Sprintf("'%s' must start with a lowercase letter and contain only lowercase letters, digits, hyphens and underscores", t)}
+	}
+	switch p.ValueType() {
+	case ValueString, ValueNumber, ValueBoolean:
+	default:
+		return &ValidationError{Field: "ValueType", Message: fmt.Sprintf("'%s' is not a value type", p.ValueType())}
+	}
+
+	fieldTypesMu.Lock()
+	defer fieldTypesMu.Unlock()
+	if _, ok := fieldTypeRegistry[t]; ok {
+		return fmt.Errorf("%w: %s", ErrFieldTypeExists, t)
+	}
+	fieldTypeRegistry[t] = p
+	return nil
+}
+
+// LookupFieldType returns the plugin of a field type.
+func LookupFieldType(t FieldType) (FieldTypePlugin, bool) {
+	fieldTypesMu.RLock()
+	defer fieldTypesMu.RUnlock()
+	p, ok := fieldTypeRegistry[t]
+	return p, ok
+}
+
+// IsBuiltinType reports whether t is one of the field types of VectraG,
+// rather than a registered one.
+func IsBuiltinType(t FieldType) bool {
+	_, ok := builtinFieldTypes[t]
+	return ok
+}
+
+// FieldValueType returns the JSON type of the values of fields of type t,
+// string for types that are not registered.
+func FieldValueType(t FieldType) ValueType {
+	if p, ok := LookupFieldType(t); ok {
+		return p.ValueType()
+	}
+	return ValueString
 }
 
 func IsValidType(t string) bool {
-	_, ok := fieldTypeRegistry[FieldType(t)]
+	_, ok := LookupFieldType(FieldType(t))
 	return ok
 }
 
+// ListFieldTypes returns the names of the field types, sorted.
 func ListFieldTypes() []string {
+	fieldTypesMu.RLock()
+	defer fieldTypesMu.RUnlock()
+
 	types := make([]string, 0, len(fieldTypeRegistry))
 	for ft := range fieldTypeRegistry {
 		types = append(types, string(ft))
 	}
+	sort.Strings(types)
 
 	return types
 }
+
+// EncodeData encodes the values of data written to the active fields of m,
+// see FieldTypePlugin. Values of unknown fields are left for ValidateEntry to
+// reject.
+func EncodeData(m Model, data map[string]any) (map[string]any, error) {
+	var errors []string
+	encoded := make(map[string]any, len(data))
+	for k, v := range data {
+		encoded[k] = v
+	}
+	for _, field := range m.Fields {
+		value, ok := data[field.Name]
+		if !ok || value == nil || field.IsDeleted() {
+			continue
+		}
+		p, ok := LookupFieldType(field.Type)
+		if !ok {
+			errors = append(errors, fmt.Sprintf("%s: field type '%s' is not registered", field.Name, field.Type))
+			continue
+		}
+		v, err := p.Encode(field, value)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", field.Name, err))
+			continue
+		}
+		encoded[field.Name] = v
+	}
+
+	if len(errors) > 0 {
+		return nil, &ValidationError{
+			Field:   "Entry",
+			Message: strings.Join(errors, "; "),
+		}
+	}
+
+	return encoded, nil
+}
+
+// DecodeData decodes the stored values of data to the values read by
+// clients. Values of fields whose type is not registered are left as stored.
+func DecodeData(m Model, data map[string]any) map[string]any {
+	decoded := make(map[string]any, len(data))
+	for k, v := range data {
+		decoded[k] = v
+	}
+	for _, field := range m.Fields {
+		value, ok := data[field.Name]
+		if !ok || value == nil {
+			continue
+		}
+		if p, ok := LookupFieldType(field.Type); ok {
+			decoded[field.Name] = p.Decode(field, value)
+		}
+	}
+	return decoded
+}
+
+// builtinFieldType is the plugin of a built-in field type. Their values are
+// stored as written.
+type builtinFieldType struct {
+	name     FieldType
+	value    ValueType
+	column   ColumnType
+	widget   string
+	validate func(f Field, value any) error
+}
+
+func (b builtinFieldType) Type() FieldType      { return b.name }
+func (b builtinFieldType) ValueType() ValueType { return b.value }
+func (b builtinFieldType) Column() ColumnType   { return b.column }
+
+func (b builtinFieldType) ValidateOptions(f Field) error {
+	if len(f.Options) > 0 {
+		return fmt.Errorf("%s fields take no options", b.name)
+	}
+	return nil
+}
+
+func (b builtinFieldType) Encode(_ Field, value any) (any, error) {
+	return value, nil
+}
+
+func (b builtinFieldType) Decode(_ Field, stored any) any {
+	return stored
+}
+
+func (b builtinFieldType) ValidateValue(f Field, value any) error {
+	return b.validate(f, value)
+}
+
+func (b builtinFieldType) UI() FieldUI {
+	return FieldUI{Widget: b.widget}
+}
+
+var builtinFieldTypes = map[FieldType]builtinFieldType{
+	FieldString:   {name: FieldString, value: ValueString, column: ColumnText, widget: "text", validate: validateString},
+	FieldText:     {name: FieldText, value: ValueString, column: ColumnText, widget: "textarea", validate: validateString},
+	FieldNumber:   {name: FieldNumber, value: ValueNumber, column: ColumnFloat, widget: "number", validate: validateNumber},
+	FieldBoolean:  {name: FieldBoolean, value: ValueBoolean, column: ColumnBoolean, widget: "checkbox", validate: validateBoolean},
+	FieldDate:     {name: FieldDate, value: ValueString, column: ColumnDate, widget: "date", validate: validateDate},
+	FieldDateTime: {name: FieldDateTime, value: ValueString, column: ColumnTimestamp, widget: "datetime", validate: validateDateTime},
+	FieldRelation: {name: FieldRelation, value: ValueString, column: ColumnText, widget: "relation", validate: validateRelation},
+}
+
+func validateString(_ Field, value any) error {
+	if _, ok := value.(string); !ok {
+		return fmt.Errorf("must be a string")
+	}
+	return nil
+}
+
+func validateNumber(_ Field, value any) error {
+	switch value.(type) {
+	case float64, float32, int, int32, int64:
+		return nil
+	default:
+		return fmt.Errorf("must be a number")
+	}
+}
+
+func validateBoolean(_ Field, value any) error {
+	if _, ok := value.(bool); !ok {
+		return fmt.Errorf("must be a boolean")
+	}
+	return nil
+}
+
+func validateDate(_ Field, value any) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a date string")
+	}
+	if _, err := time.Parse(time.DateOnly, s); err != nil {
+		return fmt.Errorf("must be a date in YYYY-MM-DD format")
+	}
+	return nil
+}
+
+func validateDateTime(_ Field, value any) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a datetime string")
+	}
+	if _, err := time.Parse(time.RFC3339, s); err != nil {
+		return fmt.Errorf("must be a datetime in RFC 3339 format")
+	}
+	return nil
+}
+
+func validateRelation(f Field, value any) error {
+	s, ok := value.(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		return fmt.Errorf("must be the ID of a '%s' entry", f.Target)
+	}
+	return nil
+}
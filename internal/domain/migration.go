@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+type MigrationType string
+
+const (
+	MigrationRenameModel     MigrationType = "rename_model"
+	MigrationChangeFieldType MigrationType = "change_field_type"
+)
+
+// Migration is a schema change that migrated stored data, recorded in the
+// migration history of the project.
+type Migration struct {
+	ID    string
+	Type  MigrationType
+	Model string
+	// Field is the field the change applies to, empty for model changes.
+	Field string
+	// From and To describe the state before and after the change, e.g. the
+	// old and new slugs of a renamed model or types of a field.
+	From string
+	To   string
+	At   time.Time
+}
+
+type MigrationRepository interface {
+	AddMigration(m Migration) error
+	GetMigrations() ([]Migration, error)
+}
+
+// ModelAlias is the former slug of a renamed model. Requests using it are
+// redirected to the model until the alias expires.
+type ModelAlias struct {
+	Slug   string
+	Target string
+	// ExpiresAt is zero for aliases that never expire.
+	ExpiresAt time.Time
+}
+
+func (a ModelAlias) IsExpired(now time.Time) bool {
+	return !a.ExpiresAt.IsZero() && !now.Before(a.ExpiresAt)
+}
+
+type AliasRepository interface {
+	PutAlias(a ModelAlias) error
+	DeleteAlias(slug string) error
+	GetAliases() ([]ModelAlias, error)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 )
 
 type Model struct {
@@ -11,10 +12,42 @@ type Model struct {
 	Name        string
 	Slug        string
 	Description string
+	Kind        ModelKind
 	Fields      []Field
 	// Relations   []Relation
 	Status        Status
 	SchemaVersion int // TODO: This could be a string, what could be the best way to handle this?
+	DeletedAt     time.Time
+}
+
+func (m Model) IsDeleted() bool {
+	return m.Status == StatusDelete
+}
+
+// VisibleData returns the subset of data belonging to fields visible to the
+// reader. See Field.IsVisible.
+func (m Model) VisibleData(data map[string]any, preview bool) map[string]any {
+	visible := make(map[string]any, len(data))
+	for _, f := range m.Fields {
+		if !f.IsVisible(preview) {
+			continue
+		}
+		if value, ok := data[f.Name]; ok {
+			visible[f.Name] = value
+		}
+	}
+	return visible
+}
+
+// ActiveFields returns the fields that are not in the trash.
+func (m Model) ActiveFields() []Field {
+	fields := make([]Field, 0, len(m.Fields))
+	for _, f := range m.Fields {
+		if !f.IsDeleted() {
+			fields = append(fields, f)
+		}
+	}
+	return fields
 }
 
 func ValidateModel(m Model) error {
@@ -32,6 +65,10 @@ func ValidateModel(m Model) error {
 		errors = append(errors, fmt.Sprintf("Slug: %v", err))
 	}
 
+	if err := ValidateKind(m.Kind); err != nil {
+		errors = append(errors, fmt.Sprintf("Kind: %v", err))
+	}
+
 	if err := ValidateStatus(m.Status); err != nil {
 		errors = append(errors, fmt.Sprintf("Status: %v", err))
 	}
@@ -40,7 +77,7 @@ func ValidateModel(m Model) error {
 		errors = append(errors, "SchemaVersion: cannot be negative")
 	}
 
-	if len(m.Fields) == 0 {
+	if len(m.ActiveFields()) == 0 {
 		errors = append(errors, "Fields: model must have at least one field")
 	}
 
@@ -56,10 +93,71 @@ func ValidateModel(m Model) error {
 		}
 		fieldIDs[field.ID] = true
 
+		// Trashed fields keep their name reserved until they are purged,
+		// entries still hold their data under it.
 		if fieldNames[field.Name] {
 			errors = append(errors, fmt.Sprintf("Fields[%d]: duplicate field name '%s'", i, field.Name))
 		}
 		fieldNames[field.Name] = true
+
+		// Filters, sorts and cursors name system fields like model fields.
+		if _, system := SystemFieldType(field.Name); system {
+			errors = append(errors, fmt.Sprintf("Fields[%d]: '%s' is reserved for the system field", i, field.Name))
+		}
+
+		if m.Kind == KindSingle && field.Type == FieldRelation && field.Target == m.Slug {
+			errors = append(errors, fmt.Sprintf("Fields[%d]: single models cannot relate to themselves", i))
+		}
+	}
+
+	if len(errors) > 0 {
+		return &ValidationError{
+			Field:   "Model",
+			Message: strings.Join(errors, "; "),
+		}
+	}
+
+	return nil
+}
+
+// ValidateRelationTargets checks the relation fields of m against the other
+// models of the project. Targets must exist and cannot be single models, and a
+// single model cannot be the target of any other model.
+func ValidateRelationTargets(m Model, models []Model) error {
+	var errors []string
+
+	bySlug := make(map[string]Model, len(models))
+	for _, other := range models {
+		bySlug[other.Slug] = other
+	}
+	bySlug[m.Slug] = m
+
+	for i, field := range m.Fields {
+		if field.Type != FieldRelation {
+			continue
+		}
+		target, ok := bySlug[field.Target]
+		if !ok {
+			errors = append(errors, fmt.Sprintf("Fields[%d]: relation target '%s' does not exist", i, field.Target))
+			continue
+		}
+		if target.Kind == KindSingle {
+			errors = append(errors, fmt.Sprintf("Fields[%d]: relation target '%s' is a single model", i, field.Target))
+		}
+	}
+
+	if m.Kind == KindSingle {
+		for _, other := range models {
+			if other.Slug == m.Slug {
+				continue
+			}
+			for _, field := range other.Fields {
+				if field.Type == FieldRelation && field.Target == m.Slug {
+					errors = append(errors, fmt.Sprintf("Kind: model '%s' has a relation to this model", other.Slug))
+					break
+				}
+			}
+		}
 	}
 
 	if len(errors) > 0 {
@@ -0,0 +1,17 @@
+package domain
+
+import "fmt"
+
+type ModelKind string
+
+const (
+	KindCollection ModelKind = "collection"
+	KindSingle     ModelKind = "single"
+)
+
+func ValidateKind(kind ModelKind) error {
+	if kind != KindCollection && kind != KindSingle {
+		return fmt.Errorf("must be either 'collection' or 'single'")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "testing"
+
+func TestValidateModelFieldNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		wantErr bool
+	}{
+		{"model field", "title", false},
+		{"id", SystemFieldID, true},
+		{"createdAt", SystemFieldCreatedAt, true},
+		{"updatedAt", SystemFieldUpdatedAt, true},
+		{"publishedAt", SystemFieldPublishedAt, true},
+		{"createdBy", SystemFieldCreatedBy, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Model{
+				ID:     "posts",
+				Name:   "Posts",
+				Slug:   "posts",
+				Kind:   KindCollection,
+				Status: StatusPublish,
+				Fields: []Field{{ID: "f1", Name: tt.field, Type: FieldString, Status: StatusPublish}},
+			}
+			if err := ValidateModel(m); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateModel() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// FieldSet is a set of field names. The nil set holds every field.
+type FieldSet map[string]bool
+
+func (s FieldSet) Has(name string) bool {
+	return s == nil || s[name]
+}
+
+// Policy decides what a caller may do from the permissions of its role or
+// API token. It is shared by every API reading or writing content, so they
+// enforce the same rules. Owner identifies the caller in the CreatedBy of the
+// entries it creates, and is matched by the permissions limited to its own
+// entries.
+type Policy struct {
+	Owner       string
+	Permissions []Permission
+}
+
+// FullAccess returns the policy of a caller allowed everything.
+func FullAccess(owner string) Policy {
+	return Policy{Owner: owner, Permissions: []Permission{{Actions: []Action{"*"}, Models: []string{AnyModel}}}}
+}
+
+// Allows reports whether action on model is allowed, at least on some
+// entries or fields.
+func (p Policy) Allows(action Action, model string) bool {
+	return len(p.matching(action, model)) > 0
+}
+
+// AllowsSomeModel reports whether action is allowed on at least one model.
+func (p Policy) AllowsSomeModel(action Action) bool {
+	for _, perm := range p.Permissions {
+		if slices.Contains(perm.Actions, "*") || slices.Contains(perm.Actions, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// Grants reports whether the policy allows everything the scope does, on
+// every entry and field, so that callers cannot create API tokens with more
+// access than their own.
+func (p Policy) Grants(s Scope) bool {
+	for _, perm := range p.matching(s.Action, s.Model) {
+		if !perm.Own && len(perm.Fields) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// EntryFields returns the fields of e action is allowed on, nil when it is
+// allowed on all of them. It reports false when action is not allowed on e.
+func (p Policy) EntryFields(action Action, e Entry) (FieldSet, bool) {
+	var perms []Permission
+	for _, perm := range p.matching(action, e.Model) {
+		if !perm.Own || p.owns(e) {
+			perms = append(perms, perm)
+		}
+	}
+	if len(perms) == 0 {
+		return nil, false
+	}
+	return fieldsOf(perms), true
+}
+
+func (p Policy) AllowsEntry(action Action, e Entry) bool {
+	_, ok := p.EntryFields(action, e)
+	return ok
+}
+
+// FilterEntry returns e with only the fields action is allowed on. It reports
+// false when action is not allowed on e.
+func (p Policy) FilterEntry(action Action, e Entry) (Entry, bool) {
+	fields, ok := p.EntryFields(action, e)
+	if !ok {
+		return Entry{}, false
+	}
+	if fields != nil {
+		e.Data = filterData(e.Data, fields)
+		if e.Published != nil {
+			e.Published = filterData(e.Published, fields)
+		}
+	}
+	return e, true
+}
+
+// AuthorizeWrite checks that action is allowed on stored with the given data,
+// and returns the data to write. Fields the caller may not write keep their
+// stored values, and may only be sent unchanged. Pass the new entry, holding
+// its CreatedBy, as stored when creating entries.
+func (p Policy) AuthorizeWrite(action Action, stored Entry, data map[string]any) (map[string]any, error) {
+	fields, ok := p.EntryFields(action, stored)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not allowed on this %s entry", ErrForbidden, action, stored.Model)
+	}
+	if fields == nil {
+		return data, nil
+	}
+
+	merged := make(map[string]any, len(data))
+	for name, value := range data {
+		if !fields.Has(name) && !reflect.DeepEqual(stored.Data[name], value) {
+			return nil, fmt.Errorf("%w: field '%s' of %s cannot be written", ErrForbidden, name, stored.Model)
+		}
+		merged[name] = value
+	}
+	for name, value := range stored.Data {
+		if !fields.Has(name) {
+			merged[name] = value
+		}
+	}
+	return merged, nil
+}
+
+// RestrictQuery checks that a query doing action on the entries of model only
+// filters, sorts and selects fields the caller may use, and limits it to the
+// entries of the caller when only its own entries are allowed.
+func (p Policy) RestrictQuery(action Action, model string, q Query) (Query, error) {
+	own, fields, err := p.queryScope(action, model)
+	if err != nil {
+		return Query{}, err
+	}
+
+	if q.Filter != nil {
+		if err := checkFilterFields(*q.Filter, fields, model); err != nil {
+			return Query{}, err
+		}
+	}
+	for _, k := range q.Sort {
+		if err := checkField(k.Field, fields, model); err != nil {
+			return Query{}, err
+		}
+	}
+	for _, name := range q.Fields {
+		if err := checkField(name, fields, model); err != nil {
+			return Query{}, err
+		}
+	}
+
+	if own {
+		q.Filter = p.ownFilter(q.Filter)
+	}
+	return q, nil
+}
+
+// RestrictAggregate is RestrictQuery for aggregations.
+func (p Policy) RestrictAggregate(action Action, model string, q AggregateQuery) (AggregateQuery, error) {
+	own, fields, err := p.queryScope(action, model)
+	if err != nil {
+		return AggregateQuery{}, err
+	}
+
+	if q.Filter != nil {
+		if err := checkFilterFields(*q.Filter, fields, model); err != nil {
+			return AggregateQuery{}, err
+		}
+	}
+	for _, name := range q.GroupBy {
+		if err := checkField(name, fields, model); err != nil {
+			return AggregateQuery{}, err
+		}
+	}
+	for _, agg := range q.Aggregations {
+		if err := checkField(agg.Field, fields, model); err != nil {
+			return AggregateQuery{}, err
+		}
+	}
+
+	if own {
+		q.Filter = p.ownFilter(q.Filter)
+	}
+	return q, nil
+}
+
+// queryScope returns the restrictions of queries doing action on model. When
+// some permission allows every entry, queries match all entries on the
+// fields allowed on every entry. Otherwise they only match the entries of the
+// caller, on the fields its permissions on own entries allow.
+func (p Policy) queryScope(action Action, model string) (own bool, fields FieldSet, err error) {
+	var all, owned []Permission
+	for _, perm := range p.matching(action, model) {
+		if perm.Own {
+			owned = append(owned, perm)
+		} else {
+			all = append(all, perm)
+		}
+	}
+
+	switch {
+	case len(all) > 0:
+		return false, fieldsOf(all), nil
+	case len(owned) > 0 && p.Owner != "":
+		return true, fieldsOf(owned), nil
+	}
+	return false, nil, fmt.Errorf("%w: %s is not allowed on %s", ErrForbidden, action, model)
+}
+
+func (p Policy) matching(action Action, model string) []Permission {
+	var perms []Permission
+	for _, perm := range p.Permissions {
+		if perm.Matches(action, model) {
+			perms = append(perms, perm)
+		}
+	}
+	return perms
+}
+
+func (p Policy) owns(e Entry) bool {
+	return p.Owner != "" && e.CreatedBy == p.Owner
+}
+
+func (p Policy) ownFilter(filter *Filter) *Filter {
+	own := Filter{Field: SystemFieldCreatedBy, Op: OpEq, Value: p.Owner}
+	if filter == nil {
+		return &own
+	}
+	return &Filter{And: []Filter{*filter, own}}
+}
+
+// fieldsOf returns the fields allowed by perms, nil when one of them allows
+// every field.
+func fieldsOf(perms []Permission) FieldSet {
+	fields := FieldSet{}
+	for _, perm := range perms {
+		if len(perm.Fields) == 0 {
+			return nil
+		}
+		for _, name := range perm.Fields {
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+func filterData(data map[string]any, fields FieldSet) map[string]any {
+	filtered := make(map[string]any, len(data))
+	for name, value := range data {
+		if fields.Has(name) {
+			filtered[name] = value
+		}
+	}
+	return filtered
+}
+
+func checkFilterFields(f Filter, fields FieldSet, model string) error {
+	for _, child := range f.And {
+		if err := checkFilterFields(child, fields, model); err != nil {
+			return err
+		}
+	}
+	for _, child := range f.Or {
+		if err := checkFilterFields(child, fields, model); err != nil {
+			return err
+		}
+	}
+	if f.IsLogical() {
+		return nil
+	}
+	return checkField(f.Field, fields, model)
+}
+
+// checkField rejects the fields outside of fields. Conditions on related
+// entries are checked against their relation field, and system fields are
+// always allowed.
+func checkField(name string, fields FieldSet, model string) error {
+	name, _, _ = strings.Cut(name, ".")
+	if _, system := SystemFieldType(name); system || fields.Has(name) {
+		return nil
+	}
+	return fmt.Errorf("%w: field '%s' of %s cannot be used", ErrForbidden, name, model)
+}
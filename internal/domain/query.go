@@ -0,0 +1,314 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Operator string
+
+const (
+	OpEq         Operator = "eq"
+	OpNe         Operator = "ne"
+	OpLt         Operator = "lt"
+	OpGt         Operator = "gt"
+	OpIn         Operator = "in"
+	OpContains   Operator = "contains"
+	OpStartsWith Operator = "startsWith"
+	OpNull       Operator = "null"
+)
+
+// System fields every entry has besides its model fields. They can be used to
+// filter and sort but not selected, and model fields cannot take their names.
+const (
+	SystemFieldID          = "id"
+	SystemFieldCreatedAt   = "createdAt"
+	SystemFieldUpdatedAt   = "updatedAt"
+	SystemFieldPublishedAt = "publishedAt"
+	SystemFieldCreatedBy   = "createdBy"
+)
+
+// Filter is a node of a filter tree. A node with And or Or children combines
+// them, otherwise it is a condition comparing Field with Value. Field is a
+// field name, or a dotted path such as "author.name" reaching into the
+// entries targeted by a relation field.
+type Filter struct {
+	And   []Filter
+	Or    []Filter
+	Field string
+	Op    Operator
+	Value any
+}
+
+func (f Filter) IsLogical() bool {
+	return f.And != nil || f.Or != nil
+}
+
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// Query selects, orders and paginates the entries of a model. It is backend
+// neutral, each ContentRepository translates it to its own storage.
+type Query struct {
+	Filter *Filter
+	Sort   []SortKey
+	// Fields restricts the returned data to the given fields, all when empty.
+	Fields []string
+	Limit  int
+	Offset int
+	// After is an opaque cursor returned as EntryPage.NextCursor. Results
+	// start right after the entry it points to.
+	After string
+	// Published matches against the published version of published entries
+	// instead of the draft version of all entries.
+	Published bool
+}
+
+type EntryPage struct {
+	Entries    []Entry
+	Total      int
+	NextCursor string
+}
+
+// SortKeys returns the sort order of q with the entry ID appended as a final
+// tie breaker, so that every entry has a distinct position.
+func (q Query) SortKeys() []SortKey {
+	keys := make([]SortKey, 0, len(q.Sort)+1)
+	for _, k := range q.Sort {
+		if k.Field == SystemFieldID {
+			return append(keys, k)
+		}
+		keys = append(keys, k)
+	}
+	return append(keys, SortKey{Field: SystemFieldID})
+}
+
+// EncodeCursor builds a cursor from the sort values of an entry, in the
+// order of Query.SortKeys.
+func EncodeCursor(values []any) string {
+	data, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(cursor string) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, &ValidationError{Field: "after", Message: "invalid cursor"}
+	}
+
+	var values []any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, &ValidationError{Field: "after", Message: "invalid cursor"}
+	}
+	return values, nil
+}
+
+// SystemFieldType returns the type of a system field.
+func SystemFieldType(name string) (FieldType, bool) {
+	switch name {
+	case SystemFieldID, SystemFieldCreatedBy:
+		return FieldString, true
+	case SystemFieldCreatedAt, SystemFieldUpdatedAt, SystemFieldPublishedAt:
+		return FieldDateTime, true
+	}
+	return "", false
+}
+
+// CoerceFilterValue validates that op applies to field f and converts value,
+// typically a string taken from a URL, to the value stored for it. Values of
+// custom field types are coerced to their ValueType, then encoded.
+func CoerceFilterValue(f Field, op Operator, value any) (any, error) {
+	t := f.Type
+	switch op {
+	case OpNull:
+		return coerceBool(value)
+	case OpIn:
+		values, ok := value.([]any)
+		if !ok {
+			values = []any{value}
+		}
+		coerced := make([]any, len(values))
+		for i, v := range values {
+			c, err := coerceScalar(f, v)
+			if err != nil {
+				return nil, err
+			}
+			coerced[i] = c
+		}
+		return coerced, nil
+	case OpEq, OpNe:
+		return coerceScalar(f, value)
+	case OpLt, OpGt:
+		if t == FieldBoolean || t == FieldRelation || FieldValueType(t) == ValueBoolean {
+			return nil, fmt.Errorf("operator '%s' does not apply to %s fields", op, t)
+		}
+		return coerceScalar(f, value)
+	case OpContains, OpStartsWith:
+		// Substrings are matched as written, they are not values to encode.
+		if t == FieldString || t == FieldText {
+			return coerceValue(t, value)
+		}
+		if !IsBuiltinType(t) && FieldValueType(t) == ValueString {
+			return coerceValue(FieldString, value)
+		}
+		return nil, fmt.Errorf("operator '%s' only applies to string and text fields", op)
+	default:
+		return nil, fmt.Errorf("unknown operator '%s'", op)
+	}
+}
+
+// valueFieldTypes are the built-in types coercing the values of each
+// ValueType.
+var valueFieldTypes = map[ValueType]FieldType{
+	ValueString:  FieldString,
+	ValueNumber:  FieldNumber,
+	ValueBoolean: FieldBoolean,
+}
+
+func coerceScalar(f Field, value any) (any, error) {
+	if IsBuiltinType(f.Type) {
+		return coerceValue(f.Type, value)
+	}
+	p, ok := LookupFieldType(f.Type)
+	if !ok {
+		return nil, fmt.Errorf("field type '%s' is not registered", f.Type)
+	}
+	v, err := coerceValue(valueFieldTypes[p.ValueType()], value)
+	if err != nil {
+		return nil, err
+	}
+	return p.Encode(f, v)
+}
+
+func coerceValue(t FieldType, value any) (any, error) {
+	s, isString := value.(string)
+	switch t {
+	case FieldNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		}
+		if !isString {
+			return nil, fmt.Errorf("'%v' is not a number", value)
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", s)
+		}
+		return f, nil
+	case FieldBoolean:
+		return coerceBool(value)
+	case FieldDate:
+		if !isString {
+			return nil, fmt.Errorf("'%v' is not a date", value)
+		}
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return nil, fmt.Errorf("'%s' is not a date in YYYY-MM-DD format", s)
+		}
+		return s, nil
+	case FieldDateTime:
+		if !isString {
+			return nil, fmt.Errorf("'%v' is not a datetime", value)
+		}
+		parsed, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a datetime in RFC 3339 format", s)
+		}
+		return parsed.UTC().Format(time.RFC3339Nano), nil
+	default:
+		if !isString {
+			return nil, fmt.Errorf("'%v' is not a string", value)
+		}
+		return s, nil
+	}
+}
+
+func coerceBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch v {
+		case "true", "1":
+			return true, nil
+		case "false", "0":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("'%v' is not a boolean", value)
+}
+
+// CompareValues orders stored values as queries sort them: nil first, then
+// booleans, numbers and strings. Strings holding RFC 3339 timestamps compare
+// chronologically, dates compare as written.
+func CompareValues(a, b any) int {
+	if ra, rb := valueRank(a), valueRank(b); ra != rb {
+		return ra - rb
+	}
+
+	switch av := a.(type) {
+	case bool:
+		bv := b.(bool)
+		switch {
+		case av == bv:
+			return 0
+		case !av:
+			return -1
+		default:
+			return 1
+		}
+	case string:
+		bv := b.(string)
+		if at, err := time.Parse(time.RFC3339Nano, av); err == nil {
+			if bt, err := time.Parse(time.RFC3339Nano, bv); err == nil {
+				return at.Compare(bt)
+			}
+		}
+		return strings.Compare(av, bv)
+	}
+
+	if af, ok := numberValue(a); ok {
+		bf, _ := numberValue(b)
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func valueRank(v any) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64, int:
+		return 2
+	case string:
+		return 3
+	}
+	return 4
+}
+
+func numberValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
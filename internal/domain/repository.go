@@ -6,4 +6,22 @@ type Repository interface {
 	DeleteModel(slug string) error
 	GetModel(slug string) (Model, error)
 	GetModels() ([]Model, error)
+	// RenameModel stores model, whose slug changed, in place of the model
+	// stored under oldSlug.
+	RenameModel(oldSlug string, model Model) error
+}
+
+type ContentRepository interface {
+	CreateEntry(entry Entry) error
+	UpdateEntry(entry Entry) error
+	DeleteEntry(model, id string) error
+	GetEntry(model, id string) (Entry, error)
+	GetEntries(model string) ([]Entry, error)
+	DeleteEntries(model string) error
+	// RenameEntries moves the entries of a model to a new slug.
+	RenameEntries(model, newModel string) error
+	// FindEntries returns the live entries of model matching q. Relation
+	// sub-field conditions must already be resolved to conditions on the
+	// relation field itself.
+	FindEntries(model string, q Query) (EntryPage, error)
 }
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+const (
+	// RoleAdmin may do anything. Admin users created before roles existed
+	// have it.
+	RoleAdmin = "admin"
+	// RolePublic holds the permissions of anonymous callers of projects with
+	// admin users, reading published content unless configured otherwise.
+	RolePublic = "public"
+)
+
+// Role is a named set of permissions given to admin users.
+type Role struct {
+	Name        string
+	Description string
+	Permissions []Permission
+}
+
+// Permission allows actions on models, written like scopes with * matching
+// every action or model. Fields limits reading and writing entries to the
+// given fields, all fields when empty. Own limits the permission to the
+// entries created by the caller. Fields and Own only apply to content
+// actions.
+type Permission struct {
+	Actions []Action
+	Models  []string
+	Fields  []string
+	Own     bool
+}
+
+// Matches reports whether the permission covers action on model, regardless
+// of its field and ownership conditions.
+func (p Permission) Matches(action Action, model string) bool {
+	return (slices.Contains(p.Actions, "*") || slices.Contains(p.Actions, action)) &&
+		(slices.Contains(p.Models, AnyModel) || slices.Contains(p.Models, model))
+}
+
+// Permission returns the permission of an API token holding the scope.
+func (s Scope) Permission() Permission {
+	return Permission{Actions: []Action{s.Action}, Models: []string{s.Model}}
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+
+func ValidateRole(r Role) error {
+	if !roleNamePattern.MatchString(r.Name) {
+		return &ValidationError{Field: "name", Message: fmt.Sprintf("role %q must be lowercase letters, digits, '_' or '-', starting with a letter", r.Name)}
+	}
+	for i, p := range r.Permissions {
+		if len(p.Actions) == 0 || len(p.Models) == 0 {
+			return &ValidationError{Field: "permissions", Message: fmt.Sprintf("permission %d of role %s needs actions and models", i+1, r.Name)}
+		}
+		for _, a := range p.Actions {
+			if a != "*" && !slices.Contains(actions, a) {
+				return &ValidationError{Field: "permissions", Message: fmt.Sprintf("unknown action %q in role %s", a, r.Name)}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type ScheduleAction string
+
+const (
+	SchedulePublish   ScheduleAction = "publish"
+	ScheduleUnpublish ScheduleAction = "unpublish"
+)
+
+// ScheduledJob is a pending publish or unpublish transition of an entry.
+type ScheduledJob struct {
+	Model   string
+	EntryID string
+	Action  ScheduleAction
+	At      time.Time
+}
+
+func ValidateSchedule(publishAt, unpublishAt time.Time) error {
+	if !publishAt.IsZero() && !unpublishAt.IsZero() && !unpublishAt.After(publishAt) {
+		return &ValidationError{
+			Field:   "unpublishAt",
+			Message: fmt.Sprintf("must be after publishAt (%s)", publishAt.Format(time.RFC3339)),
+		}
+	}
+	return nil
+}
+
+type ScheduleRepository interface {
+	PutJob(job ScheduledJob) error
+	DeleteJob(model, entryID string, action ScheduleAction) error
+	GetJobs() ([]ScheduledJob, error)
+}
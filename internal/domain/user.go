@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// AdminUser is a user allowed to manage the project through the admin panel
+// and the APIs, as far as its role permits.
+type AdminUser struct {
+	ID       string
+	Username string
+	// PasswordHash is the bcrypt hash of the password, never the password.
+	PasswordHash string
+	// Role is the name of the role of the user, RoleAdmin when empty.
+	Role      string
+	CreatedAt time.Time
+}
+
+// RoleName returns the role of the user, defaulting to RoleAdmin for users
+// created before roles existed.
+func (u AdminUser) RoleName() string {
+	if u.Role == "" {
+		return RoleAdmin
+	}
+	return u.Role
+}
+
+type UserRepository interface {
+	CreateUser(u AdminUser) error
+	UpdateUser(u AdminUser) error
+	GetUser(username string) (AdminUser, error)
+	GetUsers() ([]AdminUser, error)
+}
+
+// Session is the login of an admin user, identified by the cookie of its
+// browser until it expires or the user logs out.
+type Session struct {
+	ID        string
+	Username  string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func (s Session) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+type SessionRepository interface {
+	PutSession(s Session) error
+	GetSession(id string) (Session, error)
+	DeleteSession(id string) error
+	// DeleteExpiredSessions drops the sessions expired at now.
+	DeleteExpiredSessions(now time.Time) error
+}
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._@-]{0,63}$`)
+
+func ValidateUsername(username string) error {
+	if username == "" {
+		return &ValidationError{Field: "username", Message: "is required"}
+	}
+	if !usernamePattern.MatchString(username) {
+		return &ValidationError{Field: "username", Message: "must be at most 64 letters, digits, '.', '_', '@' or '-', starting with a letter or digit"}
+	}
+	return nil
+}
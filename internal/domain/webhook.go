@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Webhook sends the events of a project to a URL. Events may hold * for every
+// event, or model.* and entry.* for every event of a kind. Models limits the
+// webhook to the events of the given models, all models when empty. Requests
+// are signed with Secret when it is set.
+type Webhook struct {
+	Name   string
+	URL    string
+	Events []EventType
+	Models []string
+	Secret string
+}
+
+// Matches reports whether the webhook is sent e.
+func (w Webhook) Matches(e Event) bool {
+	if len(w.Models) > 0 && !slices.Contains(w.Models, AnyModel) &&
+		!slices.Contains(w.Models, e.Model) && (e.PreviousModel == "" || !slices.Contains(w.Models, e.PreviousModel)) {
+		return false
+	}
+	kind, _, _ := strings.Cut(string(e.Type), ".")
+	for _, t := range w.Events {
+		if t == "*" || t == e.Type || t == EventType(kind+".*") {
+			return true
+		}
+	}
+	return false
+}
+
+var webhookNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+
+func ValidateWebhook(w Webhook) error {
+	if !webhookNamePattern.MatchString(w.Name) {
+		return &ValidationError{Field: "name", Message: fmt.Sprintf("webhook %q must be lowercase letters, digits, '_' or '-', starting with a letter", w.Name)}
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &ValidationError{Field: "url", Message: fmt.Sprintf("webhook %s needs an http or https URL", w.Name)}
+	}
+	if len(w.Events) == 0 {
+		return &ValidationError{Field: "events", Message: fmt.Sprintf("webhook %s needs at least one event", w.Name)}
+	}
+	for _, t := range w.Events {
+		if t != "*" && t != "model.*" && t != "entry.*" && !slices.Contains(EventTypes, t) {
+			return &ValidationError{Field: "events", Message: fmt.Sprintf("unknown event %q in webhook %s", t, w.Name)}
+		}
+	}
+	return nil
+}
+
+type DeliveryStatus string
+
+const (
+	// DeliveryPending deliveries wait for their next attempt.
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryFailed deliveries ran out of attempts. They are only sent
+	// again when retried by hand.
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is an event queued for a webhook, with the outcome of its
+// last attempt. ResponseStatus is zero when no response was received.
+type WebhookDelivery struct {
+	ID             string
+	Webhook        string
+	Event          Event
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  time.Time
+	ResponseStatus int
+	LastError      string
+	CreatedAt      time.Time
+}
+
+// DeliveryFilter selects deliveries. Empty fields match every delivery.
+type DeliveryFilter struct {
+	Webhook string
+	Status  DeliveryStatus
+}
+
+type DeliveryRepository interface {
+	CreateDelivery(d WebhookDelivery) error
+	UpdateDelivery(d WebhookDelivery) error
+	GetDelivery(id string) (WebhookDelivery, error)
+	// GetDeliveries returns the deliveries matching filter, newest first.
+	// A limit of zero or less returns all of them.
+	GetDeliveries(filter DeliveryFilter, limit int) ([]WebhookDelivery, error)
+	// PruneDeliveries removes the oldest finished deliveries beyond keep.
+	// Pending deliveries are never removed.
+	PruneDeliveries(keep int) error
+}
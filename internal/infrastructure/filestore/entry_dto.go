@@ -0,0 +1,81 @@
+package filestore
+
+import (
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+type entryDTO struct {
+	ID          string         `json:"id"`
+	Data        map[string]any `json:"data"`
+	Published   map[string]any `json:"published,omitempty"`
+	Status      string         `json:"status,omitempty"`
+	PublishedAt *time.Time     `json:"publishedAt,omitempty"`
+	PublishAt   *time.Time     `json:"publishAt,omitempty"`
+	UnpublishAt *time.Time     `json:"unpublishAt,omitempty"`
+	CreatedBy   string         `json:"createdBy,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	DeletedAt   *time.Time     `json:"deletedAt,omitempty"`
+}
+
+func entryDTOFromDomain(e domain.Entry) entryDTO {
+	dto := entryDTO{
+		ID:        e.ID,
+		Data:      e.Data,
+		Published: e.Published,
+		Status:    string(e.Status),
+		CreatedBy: e.CreatedBy,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+	dto.PublishedAt = timePtr(e.PublishedAt)
+	dto.PublishAt = timePtr(e.PublishAt)
+	dto.UnpublishAt = timePtr(e.UnpublishAt)
+	dto.DeletedAt = timePtr(e.DeletedAt)
+	return dto
+}
+
+func (dto entryDTO) toDomain(model string) domain.Entry {
+	data := dto.Data
+	if data == nil {
+		data = map[string]any{}
+	}
+
+	status := domain.Status(dto.Status)
+	if status == "" {
+		status = domain.StatusDraft
+	}
+
+	entry := domain.Entry{
+		ID:        dto.ID,
+		Model:     model,
+		Data:      data,
+		Published: dto.Published,
+		Status:    status,
+		CreatedBy: dto.CreatedBy,
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+	}
+	entry.PublishedAt = timeValue(dto.PublishedAt)
+	entry.PublishAt = timeValue(dto.PublishAt)
+	entry.UnpublishAt = timeValue(dto.UnpublishAt)
+	entry.DeletedAt = timeValue(dto.DeletedAt)
+	return entry
+}
+
+// timePtr maps the zero time to nil so optional timestamps are omitted.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
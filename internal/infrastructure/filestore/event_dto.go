@@ -0,0 +1,38 @@
+package filestore
+
+import (
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+type eventDTO struct {
+	Type          string    `json:"type"`
+	Model         string    `json:"model"`
+	EntryID       string    `json:"entryId,omitempty"`
+	PreviousModel string    `json:"previousModel,omitempty"`
+	Scheduled     bool      `json:"scheduled,omitempty"`
+	OccurredAt    time.Time `json:"occurredAt"`
+}
+
+func toEventDTO(e domain.Event) eventDTO {
+	return eventDTO{
+		Type:          string(e.Type),
+		Model:         e.Model,
+		EntryID:       e.EntryID,
+		PreviousModel: e.PreviousModel,
+		Scheduled:     e.Scheduled,
+		OccurredAt:    e.OccurredAt,
+	}
+}
+
+func (d eventDTO) toDomain() domain.Event {
+	return domain.Event{
+		Type:          domain.EventType(d.Type),
+		Model:         d.Model,
+		EntryID:       d.EntryID,
+		PreviousModel: d.PreviousModel,
+		Scheduled:     d.Scheduled,
+		OccurredAt:    d.OccurredAt,
+	}
+}
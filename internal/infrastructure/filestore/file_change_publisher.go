@@ -0,0 +1,56 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// FileChangePublisher appends the changes it is sent to a file, one JSON
+// object per line, for the programs tailing it to forward them.
+type FileChangePublisher struct {
+	name     string
+	filePath string
+}
+
+func NewFileChangePublisher(name, filePath string) (*FileChangePublisher, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &FileChangePublisher{name: name, filePath: filePath}, nil
+}
+
+func (p *FileChangePublisher) Name() string {
+	return p.name
+}
+
+func (p *FileChangePublisher) PublishChanges(_ context.Context, changes []domain.Change) error {
+	var buf bytes.Buffer
+	for _, c := range changes {
+		data, err := json.Marshal(changeDTO{Seq: c.Seq, Event: toEventDTO(c.Event)})
+		if err != nil {
+			return fmt.Errorf("failed to marshal change: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(p.filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,37 @@
+package filestore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileLock is a sync.Locker shared by every process opening the same lock
+// file, such as vectrag develop and the vectrag model commands. It also
+// serializes the goroutines of a process.
+type FileLock struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileLock(path string) (*FileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	return &FileLock{file: file}, nil
+}
+
+func (l *FileLock) Lock() {
+	l.mu.Lock()
+	lockFile(l.file)
+}
+
+func (l *FileLock) Unlock() {
+	unlockFile(l.file)
+	l.mu.Unlock()
+}
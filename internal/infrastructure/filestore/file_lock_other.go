@@ -0,0 +1,11 @@
+//go:build !unix
+
+package filestore
+
+import "os"
+
+// Without flock, FileLock only serializes the goroutines of a process.
+
+func lockFile(f *os.File) {}
+
+func unlockFile(f *os.File) {}
@@ -0,0 +1,355 @@
+package filestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Journal makes the writes of the repositories sharing it atomic. Writes
+// made during a transaction are staged in memory and written on commit,
+// after being recorded in a journal file that is replayed when the process
+// stops before writing them all. Outside of transactions, and for
+// repositories given a nil Journal, writes go straight to disk.
+//
+// Staged writes are visible to every reader of the journal. Repositories
+// sharing a journal must only be used inside the transactions of a TxLock,
+// so that the only reader of staged writes is the transaction making them.
+type Journal struct {
+	path string
+
+	mu     sync.Mutex
+	active bool
+	staged map[string]stagedFile
+}
+
+type stagedFile struct {
+	Path    string      `json:"path"`
+	Data    []byte      `json:"data,omitempty"`
+	Perm    fs.FileMode `json:"perm,omitempty"`
+	Removed bool        `json:"removed,omitempty"`
+}
+
+func NewJournal(path string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &Journal{path: path, staged: make(map[string]stagedFile)}, nil
+}
+
+// Begin starts a transaction, first completing the transaction of a process
+// that stopped while committing. Transactions must be serialized by the
+// caller, see TxLock.
+func (j *Journal) Begin() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := j.recover(); err != nil {
+		return err
+	}
+	j.active = true
+	return nil
+}
+
+// Rollback throws away the files staged since Begin.
+func (j *Journal) Rollback() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.active = false
+	clear(j.staged)
+}
+
+// Commit writes the files staged since Begin.
+func (j *Journal) Commit() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.active = false
+	if len(j.staged) == 0 {
+		return nil
+	}
+
+	files := make([]stagedFile, 0, len(j.staged))
+	for _, f := range j.staged {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(a, b int) bool { return files[a].Path < files[b].Path })
+	clear(j.staged)
+
+	data, err := json.Marshal(files)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+	if err := writeFileAtomic(j.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return j.apply(files)
+}
+
+func (j *Journal) recover() error {
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var files []stagedFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		// The journal is renamed in place once complete, a partial one
+		// was never committed.
+		log.Printf("discarding incomplete journal %s: %v", j.path, err)
+		return os.Remove(j.path)
+	}
+	log.Printf("completing the transaction interrupted in %s", j.path)
+	return j.apply(files)
+}
+
+// apply writes files and removes the journal recording them.
+func (j *Journal) apply(files []stagedFile) error {
+	for _, f := range files {
+		if f.Removed {
+			if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove file: %w", err)
+			}
+			continue
+		}
+		if err := writeFileAtomic(f.Path, f.Data, f.Perm); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+	if err := os.Remove(j.path); err != nil {
+		return fmt.Errorf("failed to remove journal: %w", err)
+	}
+	return nil
+}
+
+func (j *Journal) ReadFile(path string) ([]byte, error) {
+	if j != nil {
+		j.mu.Lock()
+		f, ok := j.staged[path]
+		j.mu.Unlock()
+		if ok {
+			if f.Removed {
+				return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+			}
+			return f.Data, nil
+		}
+	}
+	return os.ReadFile(path)
+}
+
+func (j *Journal) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	if j.stage(stagedFile{Path: path, Data: data, Perm: perm}) {
+		return nil
+	}
+	return writeFileAtomic(path, data, perm)
+}
+
+func (j *Journal) Remove(path string) error {
+	if j != nil && j.isActive() {
+		if !j.Exists(path) {
+			return &fs.PathError{Op: "remove", Path: path, Err: fs.ErrNotExist}
+		}
+		j.stage(stagedFile{Path: path, Removed: true})
+		return nil
+	}
+	return os.Remove(path)
+}
+
+// Rename moves a file. Unlike os.Rename, it fails when newPath exists.
+func (j *Journal) Rename(oldPath, newPath string) error {
+	if j.Exists(newPath) {
+		return &fs.PathError{Op: "rename", Path: newPath, Err: fs.ErrExist}
+	}
+	if j == nil || !j.isActive() {
+		return os.Rename(oldPath, newPath)
+	}
+
+	data, err := j.ReadFile(oldPath)
+	if err != nil {
+		return err
+	}
+	perm := fs.FileMode(0644)
+	if info, err := os.Stat(oldPath); err == nil {
+		perm = info.Mode().Perm()
+	}
+	j.stage(stagedFile{Path: newPath, Data: data, Perm: perm})
+	j.stage(stagedFile{Path: oldPath, Removed: true})
+	return nil
+}
+
+func (j *Journal) Exists(path string) bool {
+	if j != nil {
+		j.mu.Lock()
+		f, ok := j.staged[path]
+		j.mu.Unlock()
+		if ok {
+			return !f.Removed
+		}
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// ReadDir returns the names of the files in dir, sorted.
+func (j *Journal) ReadDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for _, e := range entries {
+		if !e.IsDir() {
+			names[e.Name()] = true
+		}
+	}
+	if j != nil {
+		j.mu.Lock()
+		for path, f := range j.staged {
+			if filepath.Dir(path) == filepath.Clean(dir) {
+				names[filepath.Base(path)] = !f.Removed
+			}
+		}
+		j.mu.Unlock()
+	}
+
+	result := make([]string, 0, len(names))
+	for name, exists := range names {
+		if exists {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// stage records f when a transaction is active, and reports whether it did.
+func (j *Journal) stage(f stagedFile) bool {
+	if j == nil {
+		return false
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if !j.active {
+		return false
+	}
+	j.staged[f.Path] = f
+	return true
+}
+
+func (j *Journal) isActive() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.active
+}
+
+// writeFileAtomic writes a file through a temporary file renamed in place, so
+// that readers never see it partially written.
+func writeFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// TxLock runs transactions of a Journal holding a FileLock, so that
+// everything written by a transaction, such as an entry and the change
+// recording it, is written together or not at all.
+type TxLock struct {
+	lock    *FileLock
+	journal *Journal
+
+	mu          sync.Mutex
+	afterCommit []func()
+}
+
+func NewTxLock(lock *FileLock, journal *Journal) *TxLock {
+	return &TxLock{lock: lock, journal: journal}
+}
+
+// Tx runs fn holding the lock, as a transaction: the files fn writes are
+// written once it returns nil, and thrown away when it fails or panics.
+// Transactions cannot be nested.
+func (l *TxLock) Tx(fn func() error) error {
+	after, err := l.run(fn)
+	if err != nil {
+		return err
+	}
+	for _, fn := range after {
+		fn()
+	}
+	return nil
+}
+
+// run runs the transaction of Tx, returning the functions to run once the
+// lock is released.
+func (l *TxLock) run(fn func() error) (after []func(), err error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := l.journal.Begin(); err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		after = l.takeAfterCommit()
+		if !committed {
+			l.journal.Rollback()
+			after = nil
+		}
+	}()
+	if err := fn(); err != nil {
+		return nil, err
+	}
+	if err := l.journal.Commit(); err != nil {
+		// A journal written before failing is completed by the next
+		// transaction.
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+	return nil, nil
+}
+
+// AfterCommit runs fn once the current transaction is committed and the lock
+// released, for work that must not hold it, such as calling other systems.
+// fn is dropped when the transaction fails.
+func (l *TxLock) AfterCommit(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.afterCommit = append(l.afterCommit, fn)
+}
+
+func (l *TxLock) takeAfterCommit() []func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	after := l.afterCommit
+	l.afterCommit = nil
+	return after
+}
@@ -0,0 +1,200 @@
+package filestore
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestTxLock(t *testing.T) (*TxLock, *Journal, string) {
+	t.Helper()
+	dir := t.TempDir()
+	lock, err := NewFileLock(filepath.Join(dir, "state", "lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal, err := NewJournal(filepath.Join(dir, "state", "journal.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewTxLock(lock, journal), journal, dir
+}
+
+func TestTxLock(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name      string
+		fn        func(j *Journal, dir string) error
+		panics    bool
+		wantErr   error
+		wantFiles map[string]string
+		wantAfter bool
+	}{
+		{
+			name: "commit",
+			fn: func(j *Journal, dir string) error {
+				if err := j.WriteFile(filepath.Join(dir, "a"), []byte("new"), 0644); err != nil {
+					return err
+				}
+				return j.Remove(filepath.Join(dir, "b"))
+			},
+			wantFiles: map[string]string{"a": "new"},
+			wantAfter: true,
+		},
+		{
+			name: "rollback on error",
+			fn: func(j *Journal, dir string) error {
+				if err := j.WriteFile(filepath.Join(dir, "a"), []byte("new"), 0644); err != nil {
+					return err
+				}
+				if err := j.Remove(filepath.Join(dir, "b")); err != nil {
+					return err
+				}
+				return errFailed
+			},
+			wantErr:   errFailed,
+			wantFiles: map[string]string{"a": "old", "b": "old"},
+		},
+		{
+			name: "rollback on panic",
+			fn: func(j *Journal, dir string) error {
+				if err := j.WriteFile(filepath.Join(dir, "a"), []byte("new"), 0644); err != nil {
+					return err
+				}
+				panic(errFailed)
+			},
+			panics:    true,
+			wantFiles: map[string]string{"a": "old", "b": "old"},
+		},
+		{
+			name: "staged writes are read back",
+			fn: func(j *Journal, dir string) error {
+				path := filepath.Join(dir, "a")
+				if err := j.WriteFile(path, []byte("new"), 0644); err != nil {
+					return err
+				}
+				data, err := j.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				if string(data) != "new" {
+					return errors.New("staged write not read back")
+				}
+				if err := j.Rename(path, filepath.Join(dir, "c")); err != nil {
+					return err
+				}
+				if j.Exists(path) {
+					return errors.New("renamed file still exists")
+				}
+				return nil
+			},
+			wantFiles: map[string]string{"b": "old", "c": "new"},
+			wantAfter: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, journal, dir := newTestTxLock(t)
+			for _, name := range []string{"a", "b"} {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("old"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			after := false
+			var err error
+			func() {
+				defer func() {
+					if r := recover(); (r != nil) != tt.panics {
+						t.Fatalf("Tx() panic = %v, want panic %v", r, tt.panics)
+					}
+				}()
+				err = tx.Tx(func() error {
+					tx.AfterCommit(func() { after = true })
+					return tt.fn(journal, dir)
+				})
+			}()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Tx() error = %v, want %v", err, tt.wantErr)
+			}
+			if after != tt.wantAfter {
+				t.Errorf("AfterCommit ran = %v, want %v", after, tt.wantAfter)
+			}
+			for _, name := range []string{"a", "b", "c"} {
+				data, err := os.ReadFile(filepath.Join(dir, name))
+				want, ok := tt.wantFiles[name]
+				if !ok {
+					if !os.IsNotExist(err) {
+						t.Errorf("%s exists, want it removed", name)
+					}
+					continue
+				}
+				if err != nil || string(data) != want {
+					t.Errorf("%s = %q, %v, want %q", name, data, err, want)
+				}
+			}
+
+			// The next transaction starts clean, without the writes thrown
+			// away.
+			if err := tx.Tx(func() error { return nil }); err != nil {
+				t.Fatalf("next Tx() error = %v", err)
+			}
+			if _, err := os.Stat(journal.path); !os.IsNotExist(err) {
+				t.Errorf("journal left behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestJournalRecover(t *testing.T) {
+	tests := []struct {
+		name    string
+		journal string
+		want    string
+	}{
+		{
+			name: "completed journal is applied",
+			want: "new",
+		},
+		{
+			name:    "partial journal is discarded",
+			journal: `[{"path":`,
+			want:    "old",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, journal, dir := newTestTxLock(t)
+			path := filepath.Join(dir, "a")
+			if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			data := []byte(tt.journal)
+			if tt.journal == "" {
+				var err error
+				data, err = json.Marshal([]stagedFile{{Path: path, Data: []byte("new"), Perm: 0644}})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.WriteFile(journal.path, data, 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tx.Tx(func() error { return nil }); err != nil {
+				t.Fatalf("Tx() error = %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("file = %q, %v, want %q", got, err, tt.want)
+			}
+			if _, err := os.Stat(journal.path); !os.IsNotExist(err) {
+				t.Errorf("journal left behind: %v", err)
+			}
+		})
+	}
+}
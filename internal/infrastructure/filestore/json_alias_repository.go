@@ -0,0 +1,117 @@
+package filestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// JSONAliasRepository persists the aliases of renamed models in a single
+// JSON file.
+type JSONAliasRepository struct {
+	mu       sync.Mutex
+	filePath string
+	journal  *Journal
+}
+
+type aliasDTO struct {
+	Slug      string     `json:"slug"`
+	Target    string     `json:"target"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+func NewJSONAliasRepository(filePath string, journal *Journal) (*JSONAliasRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &JSONAliasRepository{filePath: filePath, journal: journal}, nil
+}
+
+func (r *JSONAliasRepository) PutAlias(a domain.ModelAlias) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	aliases, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	dto := aliasDTO{Slug: a.Slug, Target: a.Target, ExpiresAt: timePtr(a.ExpiresAt)}
+	for i, existing := range aliases {
+		if existing.Slug == a.Slug {
+			aliases[i] = dto
+			return r.save(aliases)
+		}
+	}
+	return r.save(append(aliases, dto))
+}
+
+func (r *JSONAliasRepository) DeleteAlias(slug string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	aliases, err := r.load()
+	if err != nil {
+		return err
+	}
+	for i, a := range aliases {
+		if a.Slug == slug {
+			return r.save(append(aliases[:i], aliases[i+1:]...))
+		}
+	}
+	return nil
+}
+
+func (r *JSONAliasRepository) GetAliases() ([]domain.ModelAlias, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	aliases, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ModelAlias, len(aliases))
+	for i, a := range aliases {
+		result[i] = domain.ModelAlias{Slug: a.Slug, Target: a.Target, ExpiresAt: timeValue(a.ExpiresAt)}
+	}
+	return result, nil
+}
+
+func (r *JSONAliasRepository) load() ([]aliasDTO, error) {
+	data, err := r.journal.ReadFile(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var aliases []aliasDTO
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return aliases, nil
+}
+
+func (r *JSONAliasRepository) save(aliases []aliasDTO) error {
+	if aliases == nil {
+		aliases = []aliasDTO{}
+	}
+
+	data, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal aliases: %w", err)
+	}
+
+	if err := r.journal.WriteFile(r.filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
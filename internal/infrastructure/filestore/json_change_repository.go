@@ -0,0 +1,180 @@
+package filestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// JSONChangeRepository persists the most recent changes of a project in a
+// single JSON file, oldest first, along with the number of the last change
+// so numbers keep increasing once older changes are dropped, and the cursors
+// of the readers of the log.
+type JSONChangeRepository struct {
+	mu       sync.Mutex
+	filePath string
+	journal  *Journal
+}
+
+type changeLogDTO struct {
+	LastSeq int64            `json:"lastSeq"`
+	Cursors map[string]int64 `json:"cursors,omitempty"`
+	Changes []changeDTO      `json:"changes"`
+}
+
+type changeDTO struct {
+	Seq   int64    `json:"seq"`
+	Event eventDTO `json:"event"`
+}
+
+func NewJSONChangeRepository(filePath string, journal *Journal) (*JSONChangeRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &JSONChangeRepository{filePath: filePath, journal: journal}, nil
+}
+
+func (r *JSONChangeRepository) AppendChange(e domain.Event, keep int) (domain.Change, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log, err := r.load()
+	if err != nil {
+		return domain.Change{}, err
+	}
+
+	log.LastSeq++
+	log.Changes = append(log.Changes, changeDTO{Seq: log.LastSeq, Event: toEventDTO(e)})
+	if keep > 0 && len(log.Changes) > keep {
+		start := len(log.Changes) - keep
+		if len(log.Cursors) > 0 {
+			oldest := log.LastSeq
+			for _, seq := range log.Cursors {
+				oldest = min(oldest, seq)
+			}
+			for start > 0 && log.Changes[start-1].Seq > oldest {
+				start--
+			}
+		}
+		log.Changes = log.Changes[start:]
+	}
+	if err := r.save(log); err != nil {
+		return domain.Change{}, err
+	}
+	return domain.Change{Seq: log.LastSeq, Event: e}, nil
+}
+
+func (r *JSONChangeRepository) GetChanges(after int64) ([]domain.Change, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log, err := r.load()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	oldest := log.LastSeq + 1
+	if len(log.Changes) > 0 {
+		oldest = log.Changes[0].Seq
+	}
+	changes := make([]domain.Change, 0)
+	for _, c := range log.Changes {
+		if c.Seq > after {
+			changes = append(changes, domain.Change{Seq: c.Seq, Event: c.Event.toDomain()})
+		}
+	}
+	return changes, oldest, nil
+}
+
+func (r *JSONChangeRepository) LastSeq() (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log, err := r.load()
+	if err != nil {
+		return 0, err
+	}
+	return log.LastSeq, nil
+}
+
+func (r *JSONChangeRepository) GetCursors() (map[string]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	cursors := make(map[string]int64, len(log.Cursors))
+	for name, seq := range log.Cursors {
+		cursors[name] = seq
+	}
+	return cursors, nil
+}
+
+func (r *JSONChangeRepository) SetCursor(name string, seq int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log, err := r.load()
+	if err != nil {
+		return err
+	}
+	if log.Cursors == nil {
+		log.Cursors = make(map[string]int64)
+	}
+	log.Cursors[name] = seq
+	return r.save(log)
+}
+
+func (r *JSONChangeRepository) DeleteCursor(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log, err := r.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := log.Cursors[name]; !ok {
+		return nil
+	}
+	delete(log.Cursors, name)
+	return r.save(log)
+}
+
+func (r *JSONChangeRepository) load() (changeLogDTO, error) {
+	data, err := r.journal.ReadFile(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return changeLogDTO{}, nil
+		}
+		return changeLogDTO{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var log changeLogDTO
+	if err := json.Unmarshal(data, &log); err != nil {
+		return changeLogDTO{}, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return log, nil
+}
+
+func (r *JSONChangeRepository) save(log changeLogDTO) error {
+	if log.Changes == nil {
+		log.Changes = []changeDTO{}
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal changes: %w", err)
+	}
+
+	if err := r.journal.WriteFile(r.filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package filestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// JSONContentRepository stores the entries of each model in a single JSON
+// file named after the model slug.
+type JSONContentRepository struct {
+	mu       sync.Mutex
+	basePath string
+	journal  *Journal
+}
+
+func NewJSONContentRepository(basePath string, journal *Journal) (*JSONContentRepository, error) {
+	if err := os.MkdirAll(basePath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &JSONContentRepository{basePath: basePath, journal: journal}, nil
+}
+
+func (r *JSONContentRepository) contentFilePath(model string) string {
+	return filepath.Join(r.basePath, fmt.Sprintf("%s.json", model))
+}
+
+func (r *JSONContentRepository) CreateEntry(entry domain.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.load(entry.Model)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ID == entry.ID {
+			return fmt.Errorf("%w: %s", domain.ErrEntryAlreadyExists, entry.ID)
+		}
+	}
+
+	entries = append(entries, entryDTOFromDomain(entry))
+	return r.save(entry.Model, entries)
+}
+
+func (r *JSONContentRepository) UpdateEntry(entry domain.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.load(entry.Model)
+	if err != nil {
+		return err
+	}
+	for i, e := range entries {
+		if e.ID == entry.ID {
+			entries[i] = entryDTOFromDomain(entry)
+			return r.save(entry.Model, entries)
+		}
+	}
+
+	return fmt.Errorf("%w: %s", domain.ErrEntryNotFound, entry.ID)
+}
+
+func (r *JSONContentRepository) DeleteEntry(model, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.load(model)
+	if err != nil {
+		return err
+	}
+	for i, e := range entries {
+		if e.ID == id {
+			entries = append(entries[:i], entries[i+1:]...)
+			return r.save(model, entries)
+		}
+	}
+
+	return fmt.Errorf("%w: %s", domain.ErrEntryNotFound, id)
+}
+
+func (r *JSONContentRepository) GetEntry(model, id string) (domain.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.load(model)
+	if err != nil {
+		return domain.Entry{}, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e.toDomain(model), nil
+		}
+	}
+
+	return domain.Entry{}, fmt.Errorf("%w: %s", domain.ErrEntryNotFound, id)
+}
+
+func (r *JSONContentRepository) GetEntries(model string) ([]domain.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.load(model)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Entry, len(entries))
+	for i, e := range entries {
+		result[i] = e.toDomain(model)
+	}
+	return result, nil
+}
+
+func (r *JSONContentRepository) DeleteEntries(model string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.journal.Remove(r.contentFilePath(model)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove file: %w", err)
+	}
+	return nil
+}
+
+func (r *JSONContentRepository) RenameEntries(model, newModel string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.journal.Exists(r.contentFilePath(newModel)) {
+		return fmt.Errorf("entries of model %s already exist", newModel)
+	}
+	if err := r.journal.Rename(r.contentFilePath(model), r.contentFilePath(newModel)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+	return nil
+}
+
+func (r *JSONContentRepository) load(model string) ([]entryDTO, error) {
+	data, err := r.journal.ReadFile(r.contentFilePath(model))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var entries []entryDTO
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *JSONContentRepository) save(model string, entries []entryDTO) error {
+	if entries == nil {
+		entries = []entryDTO{}
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal entries: %w", err)
+	}
+
+	if err := r.journal.WriteFile(r.contentFilePath(model), data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
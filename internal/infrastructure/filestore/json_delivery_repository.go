@@ -0,0 +1,201 @@
+package filestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// JSONDeliveryRepository persists the webhook delivery queue and log in a
+// single JSON file, oldest delivery first, so pending deliveries survive
+// server restarts.
+type JSONDeliveryRepository struct {
+	mu       sync.Mutex
+	filePath string
+	journal  *Journal
+}
+
+type deliveryDTO struct {
+	ID             string     `json:"id"`
+	Webhook        string     `json:"webhook"`
+	Event          eventDTO   `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  *time.Time `json:"lastAttemptAt,omitempty"`
+	ResponseStatus int        `json:"responseStatus,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+func NewJSONDeliveryRepository(filePath string, journal *Journal) (*JSONDeliveryRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &JSONDeliveryRepository{filePath: filePath, journal: journal}, nil
+}
+
+func (r *JSONDeliveryRepository) CreateDelivery(d domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries, err := r.load()
+	if err != nil {
+		return err
+	}
+	return r.save(append(deliveries, toDeliveryDTO(d)))
+}
+
+func (r *JSONDeliveryRepository) UpdateDelivery(d domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries, err := r.load()
+	if err != nil {
+		return err
+	}
+	for i, existing := range deliveries {
+		if existing.ID == d.ID {
+			deliveries[i] = toDeliveryDTO(d)
+			return r.save(deliveries)
+		}
+	}
+	return fmt.Errorf("%w: %s", domain.ErrDeliveryNotFound, d.ID)
+}
+
+func (r *JSONDeliveryRepository) GetDelivery(id string) (domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries, err := r.load()
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	for _, d := range deliveries {
+		if d.ID == id {
+			return d.toDomain(), nil
+		}
+	}
+	return domain.WebhookDelivery{}, fmt.Errorf("%w: %s", domain.ErrDeliveryNotFound, id)
+}
+
+func (r *JSONDeliveryRepository) GetDeliveries(filter domain.DeliveryFilter, limit int) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.WebhookDelivery, 0)
+	for i := len(deliveries) - 1; i >= 0; i-- {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		d := deliveries[i]
+		if (filter.Webhook == "" || d.Webhook == filter.Webhook) &&
+			(filter.Status == "" || domain.DeliveryStatus(d.Status) == filter.Status) {
+			result = append(result, d.toDomain())
+		}
+	}
+	return result, nil
+}
+
+func (r *JSONDeliveryRepository) PruneDeliveries(keep int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	finished := 0
+	for _, d := range deliveries {
+		if domain.DeliveryStatus(d.Status) != domain.DeliveryPending {
+			finished++
+		}
+	}
+	if finished <= keep {
+		return nil
+	}
+
+	kept := make([]deliveryDTO, 0, len(deliveries)-(finished-keep))
+	for _, d := range deliveries {
+		if domain.DeliveryStatus(d.Status) != domain.DeliveryPending && finished > keep {
+			finished--
+			continue
+		}
+		kept = append(kept, d)
+	}
+	return r.save(kept)
+}
+
+func toDeliveryDTO(d domain.WebhookDelivery) deliveryDTO {
+	return deliveryDTO{
+		ID:             d.ID,
+		Webhook:        d.Webhook,
+		Event:          toEventDTO(d.Event),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  timePtr(d.NextAttemptAt),
+		LastAttemptAt:  timePtr(d.LastAttemptAt),
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+	}
+}
+
+func (d deliveryDTO) toDomain() domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:             d.ID,
+		Webhook:        d.Webhook,
+		Event:          d.Event.toDomain(),
+		Status:         domain.DeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  timeValue(d.NextAttemptAt),
+		LastAttemptAt:  timeValue(d.LastAttemptAt),
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+	}
+}
+
+func (r *JSONDeliveryRepository) load() ([]deliveryDTO, error) {
+	data, err := r.journal.ReadFile(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var deliveries []deliveryDTO
+	if err := json.Unmarshal(data, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *JSONDeliveryRepository) save(deliveries []deliveryDTO) error {
+	if deliveries == nil {
+		deliveries = []deliveryDTO{}
+	}
+
+	data, err := json.MarshalIndent(deliveries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal deliveries: %w", err)
+	}
+
+	if err := r.journal.WriteFile(r.filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package filestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// JSONMigrationRepository appends the migration history of a project to a
+// single JSON file.
+type JSONMigrationRepository struct {
+	mu       sync.Mutex
+	filePath string
+	journal  *Journal
+}
+
+type migrationDTO struct {
+	ID    string    `json:"id"`
+	Type  string    `json:"type"`
+	Model string    `json:"model"`
+	Field string    `json:"field,omitempty"`
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+	At    time.Time `json:"at"`
+}
+
+func NewJSONMigrationRepository(filePath string, journal *Journal) (*JSONMigrationRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &JSONMigrationRepository{filePath: filePath, journal: journal}, nil
+}
+
+func (r *JSONMigrationRepository) AddMigration(m domain.Migration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	migrations, err := r.load()
+	if err != nil {
+		return err
+	}
+	migrations = append(migrations, migrationDTO{
+		ID:    m.ID,
+		Type:  string(m.Type),
+		Model: m.Model,
+		Field: m.Field,
+		From:  m.From,
+		To:    m.To,
+		At:    m.At,
+	})
+	return r.save(migrations)
+}
+
+func (r *JSONMigrationRepository) GetMigrations() ([]domain.Migration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	migrations, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Migration, len(migrations))
+	for i, m := range migrations {
+		result[i] = domain.Migration{
+			ID:    m.ID,
+			Type:  domain.MigrationType(m.Type),
+			Model: m.Model,
+			Field: m.Field,
+			From:  m.From,
+			To:    m.To,
+			At:    m.At,
+		}
+	}
+	return result, nil
+}
+
+func (r *JSONMigrationRepository) load() ([]migrationDTO, error) {
+	data, err := r.journal.ReadFile(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var migrations []migrationDTO
+	if err := json.Unmarshal(data, &migrations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return migrations, nil
+}
+
+func (r *JSONMigrationRepository) save(migrations []migrationDTO) error {
+	data, err := json.MarshalIndent(migrations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal migrations: %w", err)
+	}
+
+	if err := r.journal.WriteFile(r.filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package filestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// JSONScheduleRepository persists pending scheduled jobs in a single JSON file
+// so they survive server restarts.
+type JSONScheduleRepository struct {
+	mu       sync.Mutex
+	filePath string
+	journal  *Journal
+}
+
+type scheduledJobDTO struct {
+	Model   string    `json:"model"`
+	EntryID string    `json:"entryId"`
+	Action  string    `json:"action"`
+	At      time.Time `json:"at"`
+}
+
+func NewJSONScheduleRepository(filePath string, journal *Journal) (*JSONScheduleRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &JSONScheduleRepository{filePath: filePath, journal: journal}, nil
+}
+
+func (r *JSONScheduleRepository) PutJob(job domain.ScheduledJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	dto := scheduledJobDTO{
+		Model:   job.Model,
+		EntryID: job.EntryID,
+		Action:  string(job.Action),
+		At:      job.At,
+	}
+	for i, j := range jobs {
+		if j.Model == job.Model && j.EntryID == job.EntryID && j.Action == string(job.Action) {
+			jobs[i] = dto
+			return r.save(jobs)
+		}
+	}
+
+	return r.save(append(jobs, dto))
+}
+
+func (r *JSONScheduleRepository) DeleteJob(model, entryID string, action domain.ScheduleAction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs, err := r.load()
+	if err != nil {
+		return err
+	}
+	for i, j := range jobs {
+		if j.Model == model && j.EntryID == entryID && j.Action == string(action) {
+			return r.save(append(jobs[:i], jobs[i+1:]...))
+		}
+	}
+
+	return nil
+}
+
+func (r *JSONScheduleRepository) GetJobs() ([]domain.ScheduledJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ScheduledJob, len(jobs))
+	for i, j := range jobs {
+		result[i] = domain.ScheduledJob{
+			Model:   j.Model,
+			EntryID: j.EntryID,
+			Action:  domain.ScheduleAction(j.Action),
+			At:      j.At,
+		}
+	}
+	return result, nil
+}
+
+func (r *JSONScheduleRepository) load() ([]scheduledJobDTO, error) {
+	data, err := r.journal.ReadFile(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var jobs []scheduledJobDTO
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *JSONScheduleRepository) save(jobs []scheduledJobDTO) error {
+	if jobs == nil {
+		jobs = []scheduledJobDTO{}
+	}
+
+	data, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal jobs: %w", err)
+	}
+
+	if err := r.journal.WriteFile(r.filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
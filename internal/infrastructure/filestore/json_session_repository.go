@@ -0,0 +1,138 @@
+package filestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// JSONSessionRepository persists the sessions of the admin users in a single
+// JSON file, only readable by its owner.
+type JSONSessionRepository struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+type sessionDTO struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func NewJSONSessionRepository(filePath string) (*JSONSessionRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &JSONSessionRepository{filePath: filePath}, nil
+}
+
+func (r *JSONSessionRepository) PutSession(s domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	dto := sessionDTO{ID: s.ID, Username: s.Username, CreatedAt: s.CreatedAt, ExpiresAt: s.ExpiresAt}
+	for i, existing := range sessions {
+		if existing.ID == s.ID {
+			sessions[i] = dto
+			return r.save(sessions)
+		}
+	}
+	return r.save(append(sessions, dto))
+}
+
+func (r *JSONSessionRepository) GetSession(id string) (domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions, err := r.load()
+	if err != nil {
+		return domain.Session{}, err
+	}
+	for _, s := range sessions {
+		if s.ID == id {
+			return domain.Session{ID: s.ID, Username: s.Username, CreatedAt: s.CreatedAt, ExpiresAt: s.ExpiresAt}, nil
+		}
+	}
+	return domain.Session{}, domain.ErrSessionNotFound
+}
+
+func (r *JSONSessionRepository) DeleteSession(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions, err := r.load()
+	if err != nil {
+		return err
+	}
+	for i, s := range sessions {
+		if s.ID == id {
+			return r.save(append(sessions[:i], sessions[i+1:]...))
+		}
+	}
+	return nil
+}
+
+func (r *JSONSessionRepository) DeleteExpiredSessions(now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	kept := sessions[:0]
+	for _, s := range sessions {
+		if now.Before(s.ExpiresAt) {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == len(sessions) {
+		return nil
+	}
+	return r.save(kept)
+}
+
+func (r *JSONSessionRepository) load() ([]sessionDTO, error) {
+	data, err := os.ReadFile(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var sessions []sessionDTO
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return sessions, nil
+}
+
+func (r *JSONSessionRepository) save(sessions []sessionDTO) error {
+	if sessions == nil {
+		sessions = []sessionDTO{}
+	}
+
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sessions: %w", err)
+	}
+
+	if err := os.WriteFile(r.filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package filestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// JSONTokenRepository persists the API tokens of a project in a single JSON
+// file, only readable by its owner.
+type JSONTokenRepository struct {
+	mu       sync.Mutex
+	filePath string
+	journal  *Journal
+}
+
+type tokenDTO struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"hash"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+func NewJSONTokenRepository(filePath string, journal *Journal) (*JSONTokenRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &JSONTokenRepository{filePath: filePath, journal: journal}, nil
+}
+
+func (r *JSONTokenRepository) CreateToken(t domain.APIToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokens, err := r.load()
+	if err != nil {
+		return err
+	}
+	return r.save(append(tokens, toTokenDTO(t)))
+}
+
+func (r *JSONTokenRepository) UpdateToken(t domain.APIToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokens, err := r.load()
+	if err != nil {
+		return err
+	}
+	for i, existing := range tokens {
+		if existing.ID == t.ID {
+			tokens[i] = toTokenDTO(t)
+			return r.save(tokens)
+		}
+	}
+	return fmt.Errorf("%w: %s", domain.ErrTokenNotFound, t.ID)
+}
+
+func (r *JSONTokenRepository) DeleteToken(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokens, err := r.load()
+	if err != nil {
+		return err
+	}
+	for i, t := range tokens {
+		if t.ID == id {
+			return r.save(append(tokens[:i], tokens[i+1:]...))
+		}
+	}
+	return fmt.Errorf("%w: %s", domain.ErrTokenNotFound, id)
+}
+
+func (r *JSONTokenRepository) GetTokens() ([]domain.APIToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokens, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.APIToken, 0, len(tokens))
+	for _, t := range tokens {
+		token := domain.APIToken{
+			ID:         t.ID,
+			Name:       t.Name,
+			Prefix:     t.Prefix,
+			Hash:       t.Hash,
+			CreatedAt:  t.CreatedAt,
+			ExpiresAt:  timeValue(t.ExpiresAt),
+			LastUsedAt: timeValue(t.LastUsedAt),
+		}
+		for _, s := range t.Scopes {
+			scope, err := domain.ParseScope(s)
+			if err != nil {
+				return nil, fmt.Errorf("token %s: %w", t.ID, err)
+			}
+			token.Scopes = append(token.Scopes, scope)
+		}
+		result = append(result, token)
+	}
+	return result, nil
+}
+
+func toTokenDTO(t domain.APIToken) tokenDTO {
+	dto := tokenDTO{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Hash:       t.Hash,
+		Scopes:     make([]string, len(t.Scopes)),
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  timePtr(t.ExpiresAt),
+		LastUsedAt: timePtr(t.LastUsedAt),
+	}
+	for i, s := range t.Scopes {
+		dto.Scopes[i] = s.String()
+	}
+	return dto
+}
+
+func (r *JSONTokenRepository) load() ([]tokenDTO, error) {
+	data, err := r.journal.ReadFile(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var tokens []tokenDTO
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return tokens, nil
+}
+
+func (r *JSONTokenRepository) save(tokens []tokenDTO) error {
+	if tokens == nil {
+		tokens = []tokenDTO{}
+	}
+
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tokens: %w", err)
+	}
+
+	if err := r.journal.WriteFile(r.filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package filestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// JSONUserRepository persists the admin users of a project in a single JSON
+// file, only readable by its owner since it holds password hashes.
+type JSONUserRepository struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+type userDTO struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"passwordHash"`
+	Role         string    `json:"role,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func NewJSONUserRepository(filePath string) (*JSONUserRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &JSONUserRepository{filePath: filePath}, nil
+}
+
+func (r *JSONUserRepository) CreateUser(u domain.AdminUser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users, err := r.load()
+	if err != nil {
+		return err
+	}
+	for _, existing := range users {
+		if existing.Username == u.Username {
+			return fmt.Errorf("%w: %s", domain.ErrUserAlreadyExists, u.Username)
+		}
+	}
+
+	users = append(users, userDTOFromDomain(u))
+	return r.save(users)
+}
+
+func (r *JSONUserRepository) UpdateUser(u domain.AdminUser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users, err := r.load()
+	if err != nil {
+		return err
+	}
+	for i, existing := range users {
+		if existing.Username == u.Username {
+			users[i] = userDTOFromDomain(u)
+			return r.save(users)
+		}
+	}
+	return fmt.Errorf("%w: %s", domain.ErrUserNotFound, u.Username)
+}
+
+func (r *JSONUserRepository) GetUser(username string) (domain.AdminUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users, err := r.load()
+	if err != nil {
+		return domain.AdminUser{}, err
+	}
+	for _, u := range users {
+		if u.Username == username {
+			return u.toDomain(), nil
+		}
+	}
+	return domain.AdminUser{}, fmt.Errorf("%w: %s", domain.ErrUserNotFound, username)
+}
+
+func (r *JSONUserRepository) GetUsers() ([]domain.AdminUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.AdminUser, len(users))
+	for i, u := range users {
+		result[i] = u.toDomain()
+	}
+	return result, nil
+}
+
+func userDTOFromDomain(u domain.AdminUser) userDTO {
+	return userDTO{
+		ID:           u.ID,
+		Username:     u.Username,
+		PasswordHash: u.PasswordHash,
+		Role:         u.Role,
+		CreatedAt:    u.CreatedAt,
+	}
+}
+
+func (u userDTO) toDomain() domain.AdminUser {
+	return domain.AdminUser{
+		ID:           u.ID,
+		Username:     u.Username,
+		PasswordHash: u.PasswordHash,
+		Role:         u.Role,
+		CreatedAt:    u.CreatedAt,
+	}
+}
+
+func (r *JSONUserRepository) load() ([]userDTO, error) {
+	data, err := os.ReadFile(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var users []userDTO
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	return users, nil
+}
+
+func (r *JSONUserRepository) save(users []userDTO) error {
+	if users == nil {
+		users = []userDTO{}
+	}
+
+	data, err := json.MarshalIndent(users, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal users: %w", err)
+	}
+
+	if err := os.WriteFile(r.filePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
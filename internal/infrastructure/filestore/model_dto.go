@@ -11,21 +11,26 @@ type modelDTO struct {
 	Name          string     `yaml:"name"`
 	Slug          string     `yaml:"slug"`
 	Description   string     `yaml:"description,omitempty"`
+	Kind          string     `yaml:"kind,omitempty"`
 	Fields        []fieldDTO `yaml:"fields"`
 	Status        string     `yaml:"status"`
 	SchemaVersion int        `yaml:"schemaVersion"`
+	DeletedAt     time.Time  `yaml:"deletedAt,omitempty"`
 }
 
 type fieldDTO struct {
-	ID          string    `yaml:"id"`
-	Name        string    `yaml:"name"`
-	Type        string    `yaml:"type"`
-	Description string    `yaml:"description,omitempty"`
-	Unique      bool      `yaml:"unique,omitempty"`
-	Required    bool      `yaml:"required,omitempty"`
-	Status      string    `yaml:"status"`
-	CreatedAt   time.Time `yaml:"createdAt,omitempty"`
-	UpdatedAt   time.Time `yaml:"updatedAt,omitempty"`
+	ID          string         `yaml:"id"`
+	Name        string         `yaml:"name"`
+	Type        string         `yaml:"type"`
+	Target      string         `yaml:"target,omitempty"`
+	Options     map[string]any `yaml:"options,omitempty"`
+	Description string         `yaml:"description,omitempty"`
+	Unique      bool           `yaml:"unique,omitempty"`
+	Required    bool           `yaml:"required,omitempty"`
+	Status      string         `yaml:"status"`
+	CreatedAt   time.Time      `yaml:"createdAt,omitempty"`
+	UpdatedAt   time.Time      `yaml:"updatedAt,omitempty"`
+	DeletedAt   time.Time      `yaml:"deletedAt,omitempty"`
 }
 
 func modelDTOFromDomain(m domain.Model) modelDTO {
@@ -35,12 +40,15 @@ func modelDTOFromDomain(m domain.Model) modelDTO {
 			ID:          f.ID,
 			Name:        f.Name,
 			Type:        string(f.Type),
+			Target:      f.Target,
+			Options:     f.Options,
 			Description: f.Description,
 			Unique:      f.Unique,
 			Required:    f.Required,
 			Status:      string(f.Status),
 			CreatedAt:   f.CreatedAt,
 			UpdatedAt:   f.UpdatedAt,
+			DeletedAt:   f.DeletedAt,
 		}
 	}
 
@@ -49,9 +57,11 @@ func modelDTOFromDomain(m domain.Model) modelDTO {
 		Name:          m.Name,
 		Slug:          m.Slug,
 		Description:   m.Description,
+		Kind:          string(m.Kind),
 		Fields:        fields,
 		Status:        string(m.Status),
 		SchemaVersion: m.SchemaVersion,
+		DeletedAt:     m.DeletedAt,
 	}
 }
 
@@ -62,22 +72,33 @@ func (dto modelDTO) toDomain() domain.Model {
 			ID:          f.ID,
 			Name:        f.Name,
 			Type:        domain.FieldType(f.Type),
+			Target:      f.Target,
+			Options:     f.Options,
 			Description: f.Description,
 			Unique:      f.Unique,
 			Required:    f.Required,
 			Status:      domain.Status(f.Status),
 			CreatedAt:   f.CreatedAt,
 			UpdatedAt:   f.UpdatedAt,
+			DeletedAt:   f.DeletedAt,
 		}
 	}
 
+	// Models written before kinds existed are collections.
+	kind := domain.ModelKind(dto.Kind)
+	if kind == "" {
+		kind = domain.KindCollection
+	}
+
 	return domain.Model{
 		ID:            dto.ID,
 		Name:          dto.Name,
 		Slug:          dto.Slug,
 		Description:   dto.Description,
+		Kind:          kind,
 		Fields:        fields,
 		Status:        domain.Status(dto.Status),
 		SchemaVersion: dto.SchemaVersion,
+		DeletedAt:     dto.DeletedAt,
 	}
 }
@@ -0,0 +1,197 @@
+package filestore
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// FindEntries evaluates q in memory over the entries of the model.
+func (r *JSONContentRepository) FindEntries(model string, q domain.Query) (domain.EntryPage, error) {
+	entries, err := r.GetEntries(model)
+	if err != nil {
+		return domain.EntryPage{}, err
+	}
+
+	matched := make([]domain.Entry, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDeleted() {
+			continue
+		}
+		if q.Published {
+			var ok bool
+			if e, ok = e.PublishedVersion(); !ok {
+				continue
+			}
+		}
+		if q.Filter == nil || matchFilter(e, *q.Filter) {
+			matched = append(matched, e)
+		}
+	}
+
+	keys := q.SortKeys()
+	sort.SliceStable(matched, func(i, j int) bool {
+		return compareSortValues(sortValues(matched[i], keys), sortValues(matched[j], keys), keys) < 0
+	})
+
+	page := domain.EntryPage{Total: len(matched)}
+
+	if q.After != "" {
+		cursor, err := domain.DecodeCursor(q.After)
+		if err != nil {
+			return domain.EntryPage{}, err
+		}
+		if len(cursor) != len(keys) {
+			return domain.EntryPage{}, &domain.ValidationError{Field: "after", Message: "cursor does not match the sort order"}
+		}
+		start := sort.Search(len(matched), func(i int) bool {
+			return compareSortValues(sortValues(matched[i], keys), cursor, keys) > 0
+		})
+		matched = matched[start:]
+	}
+
+	if q.Offset > 0 {
+		if q.Offset >= len(matched) {
+			matched = nil
+		} else {
+			matched = matched[q.Offset:]
+		}
+	}
+
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+		page.NextCursor = domain.EncodeCursor(sortValues(matched[len(matched)-1], keys))
+	}
+
+	page.Entries = matched
+	return page, nil
+}
+
+func matchFilter(e domain.Entry, f domain.Filter) bool {
+	if f.IsLogical() {
+		for _, child := range f.And {
+			if !matchFilter(e, child) {
+				return false
+			}
+		}
+		if f.Or != nil {
+			for _, child := range f.Or {
+				if matchFilter(e, child) {
+					return true
+				}
+			}
+			return false
+		}
+		return true
+	}
+
+	value := entryValue(e, f.Field)
+	switch f.Op {
+	case domain.OpNull:
+		isNull, _ := f.Value.(bool)
+		return (value == nil) == isNull
+	case domain.OpEq:
+		return value != nil && domain.CompareValues(value, f.Value) == 0
+	case domain.OpNe:
+		return value == nil || domain.CompareValues(value, f.Value) != 0
+	case domain.OpLt:
+		return value != nil && orderable(value, f.Value) && domain.CompareValues(value, f.Value) < 0
+	case domain.OpGt:
+		return value != nil && orderable(value, f.Value) && domain.CompareValues(value, f.Value) > 0
+	case domain.OpIn:
+		values, _ := f.Value.([]any)
+		for _, v := range values {
+			if value != nil && domain.CompareValues(value, v) == 0 {
+				return true
+			}
+		}
+		return false
+	case domain.OpContains:
+		s, ok := value.(string)
+		sub, _ := f.Value.(string)
+		return ok && strings.Contains(s, sub)
+	case domain.OpStartsWith:
+		s, ok := value.(string)
+		prefix, _ := f.Value.(string)
+		return ok && strings.HasPrefix(s, prefix)
+	}
+	return false
+}
+
+// entryValue returns the value of a model field, falling back to the system
+// fields of the entry. Timestamps are returned as RFC 3339 strings so they
+// compare like datetime fields.
+func entryValue(e domain.Entry, field string) any {
+	if value, ok := e.Data[field]; ok {
+		return value
+	}
+
+	switch field {
+	case domain.SystemFieldID:
+		return e.ID
+	case domain.SystemFieldCreatedAt:
+		return formatTime(e.CreatedAt)
+	case domain.SystemFieldUpdatedAt:
+		return formatTime(e.UpdatedAt)
+	case domain.SystemFieldPublishedAt:
+		return formatTime(e.PublishedAt)
+	case domain.SystemFieldCreatedBy:
+		if e.CreatedBy == "" {
+			return nil
+		}
+		return e.CreatedBy
+	}
+	return nil
+}
+
+func formatTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func sortValues(e domain.Entry, keys []domain.SortKey) []any {
+	values := make([]any, len(keys))
+	for i, k := range keys {
+		values[i] = entryValue(e, k.Field)
+	}
+	return values
+}
+
+func compareSortValues(a, b []any, keys []domain.SortKey) int {
+	for i, k := range keys {
+		c := domain.CompareValues(a[i], b[i])
+		if k.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func orderable(a, b any) bool {
+	switch a.(type) {
+	case float64, int:
+		_, ok := toFloat(b)
+		return ok
+	case string:
+		_, ok := b.(string)
+		return ok
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
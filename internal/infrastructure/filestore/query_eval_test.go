@@ -0,0 +1,185 @@
+package filestore
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+func newTestEntries(t *testing.T) *JSONContentRepository {
+	t.Helper()
+	repo, err := NewJSONContentRepository(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []domain.Entry{
+		{ID: "a", Data: map[string]any{"title": "Alpha", "views": 10.0, "tag": "go"}},
+		{ID: "b", Data: map[string]any{"title": "Beta", "views": 30.0, "tag": "rust"}},
+		{ID: "c", Data: map[string]any{"title": "Gamma", "views": 20.0}},
+		{ID: "d", Data: map[string]any{"title": "Delta", "views": 20.0, "tag": "go"}},
+		{ID: "e", Data: map[string]any{"title": "Epsilon", "views": 5.0, "tag": "go"}, Status: domain.StatusDelete},
+	}
+	for i, e := range entries {
+		e.Model = "posts"
+		if e.Status == "" {
+			e.Status = domain.StatusDraft
+		}
+		e.CreatedAt = day.AddDate(0, 0, i)
+		e.UpdatedAt = e.CreatedAt
+		if e.ID == "b" {
+			e.Publish(day)
+		}
+		if err := repo.CreateEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func entryIDs(entries []domain.Entry) []string {
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestFindEntriesFilter(t *testing.T) {
+	repo := newTestEntries(t)
+	tests := []struct {
+		name      string
+		filter    domain.Filter
+		published bool
+		want      []string
+	}{
+		{"eq", domain.Filter{Field: "tag", Op: domain.OpEq, Value: "go"}, false, []string{"a", "d"}},
+		{"ne keeps missing values", domain.Filter{Field: "tag", Op: domain.OpNe, Value: "go"}, false, []string{"b", "c"}},
+		{"lt", domain.Filter{Field: "views", Op: domain.OpLt, Value: 20.0}, false, []string{"a"}},
+		{"gt", domain.Filter{Field: "views", Op: domain.OpGt, Value: 10.0}, false, []string{"b", "c", "d"}},
+		{"lt on another type", domain.Filter{Field: "views", Op: domain.OpLt, Value: "20"}, false, nil},
+		{"in", domain.Filter{Field: "title", Op: domain.OpIn, Value: []any{"Beta", "Gamma", "Omega"}}, false, []string{"b", "c"}},
+		{"contains", domain.Filter{Field: "title", Op: domain.OpContains, Value: "lt"}, false, []string{"d"}},
+		{"startsWith", domain.Filter{Field: "title", Op: domain.OpStartsWith, Value: "Al"}, false, []string{"a"}},
+		{"null", domain.Filter{Field: "tag", Op: domain.OpNull, Value: true}, false, []string{"c"}},
+		{"not null", domain.Filter{Field: "tag", Op: domain.OpNull, Value: false}, false, []string{"a", "b", "d"}},
+		{"system field", domain.Filter{Field: domain.SystemFieldCreatedAt, Op: domain.OpGt, Value: "2026-01-02T00:00:00Z"}, false, []string{"c", "d"}},
+		{"and", domain.Filter{And: []domain.Filter{
+			{Field: "tag", Op: domain.OpEq, Value: "go"},
+			{Field: "views", Op: domain.OpGt, Value: 10.0},
+		}}, false, []string{"d"}},
+		{"or", domain.Filter{Or: []domain.Filter{
+			{Field: "views", Op: domain.OpEq, Value: 30.0},
+			{Field: "tag", Op: domain.OpNull, Value: true},
+		}}, false, []string{"b", "c"}},
+		{"nested", domain.Filter{And: []domain.Filter{
+			{Field: "views", Op: domain.OpGt, Value: 5.0},
+			{Or: []domain.Filter{
+				{Field: "title", Op: domain.OpStartsWith, Value: "A"},
+				{Field: "title", Op: domain.OpStartsWith, Value: "G"},
+			}},
+		}}, false, []string{"a", "c"}},
+		{"published versions only", domain.Filter{Field: "views", Op: domain.OpGt, Value: 0.0}, true, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			page, err := repo.FindEntries("posts", domain.Query{Filter: &filter, Published: tt.published})
+			if err != nil {
+				t.Fatalf("FindEntries() error = %v", err)
+			}
+			got := entryIDs(page.Entries)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FindEntries() = %v, want %v", got, tt.want)
+			}
+			if page.Total != len(tt.want) {
+				t.Errorf("Total = %d, want %d", page.Total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestFindEntriesPagination(t *testing.T) {
+	repo := newTestEntries(t)
+	tests := []struct {
+		name string
+		sort []domain.SortKey
+		want []string
+	}{
+		{"by id", nil, []string{"a", "b", "c", "d"}},
+		{"ties broken by id", []domain.SortKey{{Field: "views", Desc: true}}, []string{"b", "c", "d", "a"}},
+		{"missing values first", []domain.SortKey{{Field: "tag"}}, []string{"c", "a", "d", "b"}},
+		{"multiple keys", []domain.SortKey{{Field: "tag", Desc: true}, {Field: "title", Desc: true}}, []string{"b", "d", "a", "c"}},
+		{"system field", []domain.SortKey{{Field: domain.SystemFieldCreatedAt, Desc: true}}, []string{"d", "c", "b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all, err := repo.FindEntries("posts", domain.Query{Sort: tt.sort})
+			if err != nil {
+				t.Fatalf("FindEntries() error = %v", err)
+			}
+			if got := entryIDs(all.Entries); !slices.Equal(got, tt.want) {
+				t.Fatalf("FindEntries() = %v, want %v", got, tt.want)
+			}
+
+			for limit := 1; limit <= len(tt.want); limit++ {
+				var got []string
+				after := ""
+				for pages := 0; ; pages++ {
+					if pages > len(tt.want) {
+						t.Fatalf("limit %d: cursor does not advance", limit)
+					}
+					page, err := repo.FindEntries("posts", domain.Query{Sort: tt.sort, Limit: limit, After: after})
+					if err != nil {
+						t.Fatalf("limit %d: FindEntries() error = %v", limit, err)
+					}
+					got = append(got, entryIDs(page.Entries)...)
+					if page.NextCursor == "" {
+						break
+					}
+					after = page.NextCursor
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("limit %d: pages = %v, want %v", limit, got, tt.want)
+				}
+			}
+
+			for offset := range len(tt.want) + 1 {
+				page, err := repo.FindEntries("posts", domain.Query{Sort: tt.sort, Offset: offset, Limit: 2})
+				if err != nil {
+					t.Fatalf("offset %d: FindEntries() error = %v", offset, err)
+				}
+				want := tt.want[offset:min(offset+2, len(tt.want))]
+				if got := entryIDs(page.Entries); !slices.Equal(got, want) {
+					t.Errorf("offset %d: FindEntries() = %v, want %v", offset, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFindEntriesInvalidCursor(t *testing.T) {
+	repo := newTestEntries(t)
+	tests := []struct {
+		name  string
+		after string
+	}{
+		{"not a cursor", "%%%"},
+		{"other sort order", domain.EncodeCursor([]any{20.0, "c", "x"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.FindEntries("posts", domain.Query{After: tt.after})
+			var verr *domain.ValidationError
+			if !errors.As(err, &verr) {
+				t.Errorf("FindEntries() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package filestore
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const secretSize = 32
+
+// LoadOrCreateSecret returns the random key stored in filePath, generating
+// it on first use. The key is only readable by the owner of the file.
+func LoadOrCreateSecret(filePath string) ([]byte, error) {
+	secret, err := os.ReadFile(filePath)
+	if err == nil {
+		if len(secret) < secretSize {
+			return nil, fmt.Errorf("secret %s is too short, delete it to generate a new one", filePath)
+		}
+		return secret, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read secret: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	secret = make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	// O_EXCL keeps the secret of a process that created it concurrently.
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return LoadOrCreateSecret(filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret: %w", err)
+	}
+	if _, err := f.Write(secret); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write secret: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write secret: %w", err)
+	}
+	return secret, nil
+}
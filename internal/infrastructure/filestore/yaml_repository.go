@@ -12,13 +12,14 @@ import (
 
 type YamlRepository struct {
 	basePath string
+	journal  *Journal
 }
 
-func NewYamlRepository(basePath string) (*YamlRepository, error) {
+func NewYamlRepository(basePath string, journal *Journal) (*YamlRepository, error) {
 	if err := os.MkdirAll(basePath, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
-	return &YamlRepository{basePath: basePath}, nil
+	return &YamlRepository{basePath: basePath, journal: journal}, nil
 }
 
 func (r *YamlRepository) modelFilePath(slug string) string {
@@ -31,29 +32,22 @@ func (r *YamlRepository) fallbackModelFilePath(slug string) string {
 
 func (r *YamlRepository) CreateModel(model domain.Model) error {
 	filePath := r.modelFilePath(model.Slug)
-	if _, err := os.Stat(filePath); err == nil {
-		return fmt.Errorf("model with slug %s already exists", model.Slug)
+	if r.journal.Exists(filePath) {
+		return fmt.Errorf("%w: %s", domain.ErrModelAlreadyExists, model.Slug)
 	}
-	if _, err := os.Stat(r.fallbackModelFilePath(model.Slug)); err == nil {
-		return fmt.Errorf("model with slug %s already exists", model.Slug)
+	if r.journal.Exists(r.fallbackModelFilePath(model.Slug)) {
+		return fmt.Errorf("%w: %s", domain.ErrModelAlreadyExists, model.Slug)
 	}
 	return r.saveModel(model)
 }
 
 
 func (r *YamlRepository) UpdateModel(model domain.Model) error {
-	yamlPath := r.modelFilePath(model.Slug)
-	if _, err := os.Stat(yamlPath); err == nil {
+	if r.journal.Exists(r.modelFilePath(model.Slug)) {
 		return r.saveModel(model)
-	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to stat file: %w", err)
 	}
-
-	ymlPath := r.fallbackModelFilePath(model.Slug)
-	if _, err := os.Stat(ymlPath); err == nil {
+	if r.journal.Exists(r.fallbackModelFilePath(model.Slug)) {
 		return r.saveModel(model)
-	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to stat fallback file: %w", err)
 	}
 
 	return fmt.Errorf("model with slug %s does not exist", model.Slug)
@@ -61,26 +55,26 @@ func (r *YamlRepository) UpdateModel(model domain.Model) error {
 
 func (r *YamlRepository) DeleteModel(slug string) error {
 	filePath := r.modelFilePath(slug)
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	if !r.journal.Exists(filePath) {
 		ymlPath := r.fallbackModelFilePath(slug)
-		if _, ymlErr := os.Stat(ymlPath); os.IsNotExist(ymlErr) {
+		if !r.journal.Exists(ymlPath) {
 			return fmt.Errorf("model with slug %s does not exist", slug)
 		}
 		filePath = ymlPath
 	}
-	return os.Remove(filePath)
+	return r.journal.Remove(filePath)
 }
 
 func (r *YamlRepository) GetModel(slug string) (domain.Model, error) {
 	filePath := r.modelFilePath(slug)
-	data, err := os.ReadFile(filePath)
+	data, err := r.journal.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			ymlPath := r.fallbackModelFilePath(slug)
-			ymlData, ymlErr := os.ReadFile(ymlPath)
+			ymlData, ymlErr := r.journal.ReadFile(ymlPath)
 			if ymlErr != nil {
 				if os.IsNotExist(ymlErr) {
-					return domain.Model{}, fmt.Errorf("model with slug %s not found", slug)
+					return domain.Model{}, fmt.Errorf("%w: %s", domain.ErrModelNotFound, slug)
 				}
 				return domain.Model{}, fmt.Errorf("failed to read file: %w", ymlErr)
 			}
@@ -100,23 +94,20 @@ func (r *YamlRepository) GetModel(slug string) (domain.Model, error) {
 }
 
 func (r *YamlRepository) GetModels() ([]domain.Model, error) {
-	entries, err := os.ReadDir(r.basePath)
+	names, err := r.journal.ReadDir(r.basePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read directory: %w", err)
 	}
 
 	var models []domain.Model
 	seen := make(map[string]struct{})
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		ext := strings.ToLower(filepath.Ext(entry.Name()))
+	for _, name := range names {
+		ext := strings.ToLower(filepath.Ext(name))
 		if ext != ".yml" && ext != ".yaml" {
 			continue
 		}
 
-		slug := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		slug := strings.TrimSuffix(name, filepath.Ext(name))
 		if _, ok := seen[slug]; ok {
 			continue
 		}
@@ -131,15 +122,34 @@ func (r *YamlRepository) GetModels() ([]domain.Model, error) {
 	return models, nil
 }
 
+func (r *YamlRepository) RenameModel(oldSlug string, model domain.Model) error {
+	if err := r.CreateModel(model); err != nil {
+		return err
+	}
+	if err := r.DeleteModel(oldSlug); err != nil {
+		_ = r.journal.Remove(r.modelFilePath(model.Slug))
+		return err
+	}
+	return nil
+}
+
+// MarshalModelYAML encodes a model the way it is stored in its YAML file.
+func MarshalModelYAML(model domain.Model) ([]byte, error) {
+	data, err := yaml.Marshal(modelDTOFromDomain(model))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal model: %w", err)
+	}
+	return data, nil
+}
+
 func (r *YamlRepository) saveModel(model domain.Model) error {
-	dto := modelDTOFromDomain(model)
-	data, err := yaml.Marshal(dto)
+	data, err := MarshalModelYAML(model)
 	if err != nil {
-		return fmt.Errorf("failed to marshal model: %w", err)
+		return err
 	}
 
 	filePath := r.modelFilePath(model.Slug)
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
+	if err := r.journal.WriteFile(filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
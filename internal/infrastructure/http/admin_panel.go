@@ -10,10 +10,23 @@ import (
 	"github.com/axarus/vectrag/admin"
 )
 
-type AdminHandlerProvider struct{}
+// AdminHandlerProvider serves the admin panel. The panel of the project of
+// Routes requires its admin users to log in; without Routes it is public.
+type AdminHandlerProvider struct {
+	Routes *APIRoutesProvider
+}
 
-func (AdminHandlerProvider) Handler() http.Handler {
-	return AdminHandler()
+func (p AdminHandlerProvider) Handler() http.Handler {
+	if p.Routes == nil {
+		return AdminHandler()
+	}
+	project, err := p.Routes.load()
+	if err != nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		})
+	}
+	return NewAuthenticator(project).RequireLogin(AdminHandler())
 }
 
 // AdminHandler returns an HTTP handler that serves the admin panel.
@@ -2,30 +2,61 @@ package http
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"os"
+	"sync"
 
 	"github.com/axarus/vectrag/internal/application"
 )
 
-type APIRoutesProvider struct{}
+// APIRoutesProvider loads the project found from the working directory once
+// and exposes its APIs and background workers.
+type APIRoutesProvider struct {
+	once    sync.Once
+	project *Project
+	err     error
+}
 
-func (APIRoutesProvider) Register(mux *http.ServeMux) error {
-	wd, err := os.Getwd()
+func (p *APIRoutesProvider) Register(mux *http.ServeMux) error {
+	project, err := p.load()
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
+		return err
 	}
 
-	projectRoot, err := application.FindProjectRoot(wd)
-	if err != nil {
-		return err
+	project.Register(mux)
+
+	if enabled, err := project.auth.Enabled(); err == nil && !enabled {
+		log.Printf("no admin users: the admin panel and the APIs changing the project are open to anyone reaching the server, create one with vectrag admin create-user")
 	}
 
-	modelsAPI, err := NewModelsAPI(projectRoot)
+	return nil
+}
+
+func (p *APIRoutesProvider) Workers() ([]application.BackgroundWorker, error) {
+	project, err := p.load()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	modelsAPI.Register(mux)
 
-	return nil
+	return project.Workers(), nil
+}
+
+func (p *APIRoutesProvider) load() (*Project, error) {
+	p.once.Do(func() {
+		wd, err := os.Getwd()
+		if err != nil {
+			p.err = fmt.Errorf("failed to get working directory: %w", err)
+			return
+		}
+
+		projectRoot, err := application.FindProjectRoot(wd)
+		if err != nil {
+			p.err = err
+			return
+		}
+
+		p.project, p.err = LoadProject(projectRoot)
+	})
+	return p.project, p.err
 }
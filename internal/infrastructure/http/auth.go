@@ -0,0 +1,270 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+const sessionCookie = "vectrag_session"
+
+var (
+	errAuthenticationRequired = errors.New("authentication required")
+	errAuthenticationFailed   = errors.New("authentication failed")
+)
+
+// Authenticator is the middleware identifying the caller of the API routes,
+// from its admin session cookie or its API token, and checking that its
+// policy allows the request. Projects without admin users are left open to
+// anonymous callers, as they were before users existed.
+type Authenticator struct {
+	tx         application.Transactor
+	auth       *application.AuthService
+	tokens     *application.TokenService
+	roles      *application.RoleService
+	external   ExternalAuth
+	enableCORS bool
+}
+
+// ExternalAuth identifies the callers of a program embedding a project, e.g.
+// from the sessions of its own users. It returns a nil Identity to leave the
+// request to admin sessions and API tokens, and an error to reject it.
+type ExternalAuth func(r *http.Request) (*Identity, error)
+
+// Identity is a caller identified by an ExternalAuth.
+type Identity struct {
+	// Name identifies the caller in error messages, and in the CreatedBy of
+	// its entries when Policy has no Owner.
+	Name   string
+	Policy domain.Policy
+}
+
+// caller is the authenticated caller of a request. Its policy decides what it
+// may do, finer checks on entries and fields are left to the handlers.
+type caller struct {
+	// open is true for projects without admin users.
+	open     bool
+	user     *domain.AdminUser
+	token    *domain.APIToken
+	identity *Identity
+	policy   domain.Policy
+}
+
+// permission returns the action a request does and the model it is about.
+type permission func(r *http.Request) (domain.Action, string)
+
+type callerContextKey struct{}
+
+func NewAuthenticator(p *Project) *Authenticator {
+	return &Authenticator{tx: p.tx, auth: p.auth, tokens: p.tokens, roles: p.roles, external: p.extAuth, enableCORS: p.config.Development.EnableCORS}
+}
+
+func (c caller) anonymous() bool {
+	return c.user == nil && c.token == nil && c.identity == nil
+}
+
+// can reports whether the policy of the caller allows action on model, at
+// least on some entries or fields.
+func (c caller) can(action domain.Action, model string) bool {
+	return c.policy.Allows(action, model)
+}
+
+// denied returns the error and status answering a request the caller may not
+// make.
+func (c caller) denied(action domain.Action, model string) (int, error) {
+	scope := domain.Scope{Action: action, Model: model}
+	switch {
+	case c.token != nil:
+		return http.StatusForbidden, fmt.Errorf("API token %s lacks the %s scope", c.token.Prefix, scope)
+	case c.user != nil:
+		return http.StatusForbidden, fmt.Errorf("the %s role of %s does not allow %s", c.user.RoleName(), c.user.Username, scope)
+	case c.identity != nil:
+		return http.StatusForbidden, fmt.Errorf("%s is not allowed %s", c.identity.Name, scope)
+	}
+	return http.StatusUnauthorized, errAuthenticationRequired
+}
+
+// entryView returns an entry written by the caller as returned to it: as it
+// may preview it, or else limited to the fields action allowed it to write.
+func (c caller) entryView(action domain.Action, e domain.Entry) domain.Entry {
+	if view, ok := c.policy.FilterEntry(domain.ActionPreview, e); ok {
+		return view
+	}
+	view, _ := c.policy.FilterEntry(action, e)
+	return view
+}
+
+// Require identifies the caller and answers 401 or 403 to the requests it
+// may not make according to perm. A nil perm only rejects invalid
+// credentials.
+func (a *Authenticator) Require(perm permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Preflight requests carry no credentials.
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		c, err := a.identify(r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, domain.ErrTokenNotFound) || errors.Is(err, errAuthenticationFailed) {
+				status = http.StatusUnauthorized
+			}
+			a.writeError(w, r, status, err)
+			return
+		}
+		if perm != nil {
+			if action, model := perm(r); !c.can(action, model) {
+				status, err := c.denied(action, model)
+				a.writeError(w, r, status, err)
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), callerContextKey{}, c)))
+	})
+}
+
+// RequireLogin serves the login page instead of next to the requests without
+// a valid admin session.
+func (a *Authenticator) RequireLogin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := a.identifySession(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !c.open && c.user == nil {
+			writeLoginPage(w, http.StatusUnauthorized, r.URL.RequestURI(), "")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *Authenticator) writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if a.enableCORS {
+		writeCORS(w, r)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	writeError(w, status, err.Error())
+}
+
+// identify returns the caller of a request identified by the external auth,
+// presenting an API token as bearer token, or else a session cookie. Invalid
+// API tokens fail with domain.ErrTokenNotFound.
+func (a *Authenticator) identify(r *http.Request) (caller, error) {
+	if a.external != nil {
+		identity, err := a.external(r)
+		if err != nil {
+			return caller{}, fmt.Errorf("%w: %v", errAuthenticationFailed, err)
+		}
+		if identity != nil {
+			policy := identity.Policy
+			if policy.Owner == "" {
+				policy.Owner = identity.Name
+			}
+			return caller{identity: identity, policy: policy}, nil
+		}
+	}
+
+	bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !application.IsAPIToken(bearer) {
+		return a.identifySession(r)
+	}
+
+	// Recording the use of the token writes it, like the vectrag token
+	// commands.
+	var token domain.APIToken
+	err := a.tx.Tx(func() error {
+		var err error
+		token, err = a.tokens.Authenticate(bearer)
+		return err
+	})
+	if err != nil {
+		return caller{}, err
+	}
+	return caller{token: &token, policy: a.roles.TokenPolicy(token)}, nil
+}
+
+// identifySession returns the caller of a request from its session cookie.
+// Requests without a valid session are anonymous, with the policy of the
+// public role.
+func (a *Authenticator) identifySession(r *http.Request) (caller, error) {
+	enabled, err := a.auth.Enabled()
+	if err != nil {
+		return caller{}, err
+	}
+	if !enabled {
+		return caller{open: true, policy: domain.FullAccess("")}, nil
+	}
+
+	anonymous := caller{policy: a.roles.PublicPolicy()}
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return anonymous, nil
+	}
+	user, err := a.auth.Authenticate(cookie.Value)
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return anonymous, nil
+	}
+	if err != nil {
+		return caller{}, err
+	}
+	return caller{user: &user, policy: a.roles.UserPolicy(user)}, nil
+}
+
+// callerFrom returns the caller identified by Require.
+func callerFrom(ctx context.Context) caller {
+	c, _ := ctx.Value(callerContextKey{}).(caller)
+	return c
+}
+
+// modelsPermission requires the schema scope of the model of the path, or
+// of any model for the routes about all models.
+func modelsPermission(r *http.Request) (domain.Action, string) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/models"), "/")
+	if slug, _, _ := strings.Cut(rest, "/"); slug != "" {
+		return domain.ActionSchema, slug
+	}
+	return domain.ActionSchema, domain.AnyModel
+}
+
+// contentPermission maps content requests to the action they do on their
+// model.
+func contentPermission(r *http.Request) (domain.Action, string) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/content/"), "/"), "/")
+	model := parts[0]
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return domain.ActionRead, model
+	case http.MethodPut:
+		return domain.ActionUpdate, model
+	case http.MethodDelete:
+		return domain.ActionDelete, model
+	}
+	if len(parts) > 1 && isLifecycleAction(parts[len(parts)-1]) {
+		return domain.ActionPublish, model
+	}
+	return domain.ActionCreate, model
+}
+
+// trashPermission requires the schema scope of the model of the path, of
+// any model to list trashed models and fields.
+func trashPermission(r *http.Request) (domain.Action, string) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/trash/"), "/"), "/")
+	if len(parts) > 1 && parts[1] != "" {
+		return domain.ActionSchema, parts[1]
+	}
+	return domain.ActionSchema, domain.AnyModel
+}
+
+func adminPermission(*http.Request) (domain.Action, string) {
+	return domain.ActionAdmin, domain.AnyModel
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// AuthAPI logs admin users in and out. Sessions are kept in a cookie, so the
+// admin panel and browsers authenticate without handling tokens.
+type AuthAPI struct {
+	tx            application.Transactor
+	auth          *application.AuthService
+	authenticator *Authenticator
+	enableCORS    bool
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// SessionResponse describes the session of the caller. AuthRequired is false
+// for projects without admin users.
+type SessionResponse struct {
+	AuthRequired bool       `json:"authRequired"`
+	Username     string     `json:"username,omitempty"`
+	Role         string     `json:"role,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+}
+
+func NewAuthAPI(p *Project) *AuthAPI {
+	return &AuthAPI{
+		tx:            p.tx,
+		auth:          p.auth,
+		authenticator: NewAuthenticator(p),
+		enableCORS:    p.config.Development.EnableCORS,
+	}
+}
+
+func (api *AuthAPI) Register(mux *http.ServeMux) {
+	mux.Handle("/api/auth/", api.authenticator.Require(nil, api))
+}
+
+func (api *AuthAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if api.enableCORS && writeCORS(w, r) {
+		return
+	}
+
+	switch strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/auth/"), "/") {
+	case "login":
+		if r.Method != http.MethodPost {
+			w.Header().Set("Content-Type", "application/json")
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if isFormRequest(r) {
+			api.handleLoginForm(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		api.handleLogin(w, r)
+	case "logout":
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		api.handleLogout(w, r)
+	case "session":
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		api.handleSession(w, r)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (api *AuthAPI) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	token, session, err := api.login(req.Username, req.Password)
+	if errors.Is(err, domain.ErrInvalidCredentials) {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	user, err := api.auth.Authenticate(token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	setSessionCookie(w, r, token, session.ExpiresAt)
+	writeJSON(w, http.StatusOK, SessionResponse{
+		AuthRequired: true,
+		Username:     session.Username,
+		Role:         user.RoleName(),
+		ExpiresAt:    &session.ExpiresAt,
+	})
+}
+
+// handleLoginForm handles the login page, redirecting to the page that
+// required the login on success and showing the page again otherwise.
+func (api *AuthAPI) handleLoginForm(w http.ResponseWriter, r *http.Request) {
+	redirect := r.PostFormValue("redirect")
+	// Only paths of this server are followed, not "//host" or "http://host".
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		redirect = "/"
+	}
+
+	token, session, err := api.login(r.PostFormValue("username"), r.PostFormValue("password"))
+	if errors.Is(err, domain.ErrInvalidCredentials) {
+		writeLoginPage(w, http.StatusUnauthorized, redirect, "Invalid username or password.")
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	setSessionCookie(w, r, token, session.ExpiresAt)
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+func (api *AuthAPI) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		err := api.tx.Tx(func() error {
+			return api.auth.Logout(cookie.Value)
+		})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// login opens a session, see application.AuthService.Login, in a transaction
+// serializing it with the other writers of users and sessions.
+func (api *AuthAPI) login(username, password string) (string, domain.Session, error) {
+	var token string
+	var session domain.Session
+	err := api.tx.Tx(func() error {
+		var err error
+		token, session, err = api.auth.Login(username, password)
+		return err
+	})
+	return token, session, err
+}
+
+func (api *AuthAPI) handleSession(w http.ResponseWriter, r *http.Request) {
+	c := callerFrom(r.Context())
+	switch {
+	case c.user != nil:
+		writeJSON(w, http.StatusOK, SessionResponse{AuthRequired: true, Username: c.user.Username, Role: c.user.RoleName()})
+	case c.open:
+		writeJSON(w, http.StatusOK, SessionResponse{AuthRequired: false})
+	default:
+		writeError(w, http.StatusUnauthorized, errAuthenticationRequired.Error())
+	}
+}
+
+// setSessionCookie stores the session token in an HTTP-only cookie. SameSite
+// keeps other sites from sending it along with the requests they trigger.
+func setSessionCookie(w http.ResponseWriter, r *http.Request, token string, expiresAt time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func isFormRequest(r *http.Request) bool {
+	return mediaType(r) == "application/x-www-form-urlencoded"
+}
+
+// isJSONRequest reports whether the body of r is JSON. Browsers only send
+// JSON to other sites after a CORS preflight.
+func isJSONRequest(r *http.Request) bool {
+	return mediaType(r) == "application/json"
+}
+
+func mediaType(r *http.Request) string {
+	contentType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";")
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+const (
+	// defaultChangeLimit is how many changes are read when no limit is
+	// given, and maxChangeLimit how many may be.
+	defaultChangeLimit = 100
+	maxChangeLimit     = 1000
+)
+
+// ChangesAPI reads the change log of the project from an offset, for the
+// consumers polling it rather than streaming /api/events:
+//
+//	GET /api/changes?after=0&limit=100&model=article,page
+//
+// Consumers store the next offset of each response and read after it. The
+// changes the caller may not see are skipped, but still move the offset.
+type ChangesAPI struct {
+	changes      *application.ChangeFeed
+	auth         *Authenticator
+	enableCORS   bool
+	previewToken string
+}
+
+type changeResponse struct {
+	Seq   int64         `json:"seq"`
+	Event eventResponse `json:"event"`
+}
+
+type changePageResponse struct {
+	Changes []changeResponse `json:"changes"`
+	// Next is the offset of the next page.
+	Next int64 `json:"next"`
+	More bool  `json:"more"`
+	// Complete is false when changes after the offset were dropped from the
+	// log, and the consumer should reload the content.
+	Complete bool `json:"complete"`
+}
+
+func NewChangesAPI(p *Project) *ChangesAPI {
+	return &ChangesAPI{
+		changes:      p.changes,
+		auth:         NewAuthenticator(p),
+		enableCORS:   p.config.Development.EnableCORS,
+		previewToken: p.config.Content.PreviewToken,
+	}
+}
+
+func (api *ChangesAPI) Register(mux *http.ServeMux) {
+	mux.Handle("/api/changes", api.auth.Require(nil, api))
+}
+
+func (api *ChangesAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if api.enableCORS && writeCORS(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	models := modelsParam(r)
+	visible, status, err := changeAccess(r, models, api.previewToken)
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+
+	var after int64
+	if s := r.URL.Query().Get("after"); s != "" {
+		after, err = strconv.ParseInt(s, 10, 64)
+		if err != nil || after < 0 {
+			writeServiceError(w, &domain.ValidationError{Field: "after", Message: "must be a non-negative integer"})
+			return
+		}
+	}
+	limit, err := intParam(r.URL.Query(), "limit")
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if limit == 0 {
+		limit = defaultChangeLimit
+	}
+	limit = min(limit, maxChangeLimit)
+
+	page, err := api.changes.Changes(after, limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := changePageResponse{Changes: []changeResponse{}, Next: page.Next, More: page.More, Complete: page.Complete}
+	for _, c := range page.Changes {
+		if changeVisible(c, models, visible) {
+			resp.Changes = append(resp.Changes, changeResponse{Seq: c.Seq, Event: newEventResponse(c.Event)})
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,640 @@
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+type ContentAPI struct {
+	tx           application.Transactor
+	modelSvc     *application.ModelService
+	contentSvc   *application.ContentService
+	scheduler    *application.Scheduler
+	migrations   *application.MigrationService
+	auth         *Authenticator
+	enableCORS   bool
+	previewToken string
+}
+
+type entryResponse struct {
+	ID          string         `json:"id,omitempty"`
+	Data        map[string]any `json:"data"`
+	Status      string         `json:"status"`
+	CreatedBy   string         `json:"createdBy,omitempty"`
+	PublishedAt *time.Time     `json:"publishedAt,omitempty"`
+	PublishAt   *time.Time     `json:"publishAt,omitempty"`
+	UnpublishAt *time.Time     `json:"unpublishAt,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+type ScheduleRequest struct {
+	PublishAt   *time.Time `json:"publishAt"`
+	UnpublishAt *time.Time `json:"unpublishAt"`
+}
+
+func NewContentAPI(p *Project) *ContentAPI {
+	return &ContentAPI{
+		tx:           p.tx,
+		modelSvc:     p.modelSvc,
+		contentSvc:   p.contentSvc,
+		scheduler:    p.scheduler,
+		migrations:   p.migrations,
+		auth:         NewAuthenticator(p),
+		enableCORS:   p.config.Development.EnableCORS,
+		previewToken: p.config.Content.PreviewToken,
+	}
+}
+
+func (api *ContentAPI) Register(mux *http.ServeMux) {
+	mux.Handle("/api/content/", api.auth.Require(contentPermission, api))
+}
+
+func (api *ContentAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if api.enableCORS && writeCORS(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/content/"), "/")
+	parts := strings.Split(path, "/")
+	if path == "" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	slug := parts[0]
+	var model domain.Model
+	var target string
+	var alias bool
+	err := api.tx.Tx(func() error {
+		var err error
+		if model, err = api.modelSvc.Get(slug); err != nil {
+			target, alias = api.migrations.ResolveAlias(slug)
+		}
+		return err
+	})
+	if err != nil {
+		if alias {
+			redirectAlias(w, r, "/api/content/", slug, target)
+			return
+		}
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if model.Kind == domain.KindSingle {
+		if len(parts) == 2 && isLifecycleAction(parts[1]) {
+			api.handleLifecycle(w, r, slug, "", parts[1])
+			return
+		}
+		if len(parts) != 1 {
+			writeError(w, http.StatusNotFound, "single models have no entry IDs")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			api.handleGetSingle(w, r, slug)
+		case http.MethodPut:
+			api.handlePutSingle(w, r, slug)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			api.handleList(w, r, slug)
+		case http.MethodPost:
+			api.handleCreate(w, r, slug)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	if len(parts) == 2 && parts[1] == "aggregate" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		api.handleAggregate(w, r, slug)
+		return
+	}
+
+	id := parts[1]
+	if len(parts) == 3 {
+		if !isLifecycleAction(parts[2]) {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		api.handleLifecycle(w, r, slug, id, parts[2])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		api.handleGet(w, r, slug, id)
+	case http.MethodPut:
+		api.handleUpdate(w, r, slug, id)
+	case http.MethodDelete:
+		api.handleDelete(w, r, slug, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+type listResponse struct {
+	Data []entryResponse `json:"data"`
+	Meta listMeta        `json:"meta"`
+}
+
+type listMeta struct {
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit,omitempty"`
+	Offset     int    `json:"offset,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+func (api *ContentAPI) handleList(w http.ResponseWriter, r *http.Request, slug string) {
+	draft, ok := readDrafts(w, r, api.previewToken, slug)
+	if !ok {
+		return
+	}
+
+	q, err := parseContentQuery(r.URL.Query())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	c := callerFrom(r.Context())
+
+	var page domain.EntryPage
+	err = api.tx.Tx(func() error {
+		var err error
+		page, err = api.contentSvc.Query(slug, q, draft, c.policy)
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := listResponse{
+		Data: make([]entryResponse, len(page.Entries)),
+		Meta: listMeta{
+			Total:      page.Total,
+			Limit:      q.Limit,
+			Offset:     q.Offset,
+			NextCursor: page.NextCursor,
+		},
+	}
+	for i, e := range page.Entries {
+		e, _ = readView(c.policy, draft, e)
+		resp.Data[i] = newEntryResponse(e)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// aggregateGroup is a group in an aggregation response. Aggregated values are
+// keyed by function then field, e.g. {"sum": {"views": 42}}.
+type aggregateGroup struct {
+	Group map[string]any `json:"group"`
+	Count int            `json:"count"`
+	Sum   map[string]any `json:"sum,omitempty"`
+	Avg   map[string]any `json:"avg,omitempty"`
+	Min   map[string]any `json:"min,omitempty"`
+	Max   map[string]any `json:"max,omitempty"`
+}
+
+func (api *ContentAPI) handleAggregate(w http.ResponseWriter, r *http.Request, slug string) {
+	draft, ok := readDrafts(w, r, api.previewToken, slug)
+	if !ok {
+		return
+	}
+
+	q, err := parseAggregateQuery(r.URL.Query())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	policy := callerFrom(r.Context()).policy
+
+	var groups []domain.AggregateGroup
+	err = api.tx.Tx(func() error {
+		var err error
+		groups, err = api.contentSvc.Aggregate(slug, q, draft, policy)
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	data := make([]aggregateGroup, len(groups))
+	for i, g := range groups {
+		resp := aggregateGroup{Group: g.Key, Count: g.Count}
+		for agg, value := range g.Values {
+			var values *map[string]any
+			switch agg.Func {
+			case domain.AggSum:
+				values = &resp.Sum
+			case domain.AggAvg:
+				values = &resp.Avg
+			case domain.AggMin:
+				values = &resp.Min
+			case domain.AggMax:
+				values = &resp.Max
+			default:
+				continue
+			}
+			if *values == nil {
+				*values = map[string]any{}
+			}
+			(*values)[agg.Field] = value
+		}
+		data[i] = resp
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": data})
+}
+
+func (api *ContentAPI) handleGet(w http.ResponseWriter, r *http.Request, slug, id string) {
+	draft, ok := readDrafts(w, r, api.previewToken, slug)
+	if !ok {
+		return
+	}
+
+	var entry domain.Entry
+	err := api.tx.Tx(func() error {
+		var err error
+		if draft {
+			entry, err = api.contentSvc.Get(slug, id)
+		} else {
+			entry, err = api.contentSvc.GetPublished(slug, id)
+		}
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	// Entries the caller may not read are reported missing, not forbidden.
+	entry, ok = readView(callerFrom(r.Context()).policy, draft, entry)
+	if !ok {
+		writeServiceError(w, fmt.Errorf("%w: %s", domain.ErrEntryNotFound, id))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newEntryResponse(entry))
+}
+
+func (api *ContentAPI) handleCreate(w http.ResponseWriter, r *http.Request, slug string) {
+	var data map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	c := callerFrom(r.Context())
+	now := time.Now().UTC()
+	entry := domain.Entry{
+		ID:        newID(),
+		Model:     slug,
+		Status:    domain.StatusDraft,
+		CreatedBy: c.policy.Owner,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	data, err := c.policy.AuthorizeWrite(domain.ActionCreate, entry, data)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	entry.Data = data
+
+	err = api.tx.Tx(func() error {
+		var err error
+		entry, err = api.contentSvc.Create(entry)
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newEntryResponse(c.entryView(domain.ActionCreate, entry)))
+}
+
+func (api *ContentAPI) handleUpdate(w http.ResponseWriter, r *http.Request, slug, id string) {
+	var data map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	c := callerFrom(r.Context())
+	var updated domain.Entry
+	err := api.tx.Tx(func() error {
+		existing, err := api.contentSvc.Get(slug, id)
+		if err != nil {
+			return err
+		}
+		if data, err = c.policy.AuthorizeWrite(domain.ActionUpdate, existing, data); err != nil {
+			return err
+		}
+
+		updated = existing
+		updated.Data = data
+		updated.UpdatedAt = time.Now().UTC()
+		updated, err = api.contentSvc.Update(updated)
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newEntryResponse(c.entryView(domain.ActionUpdate, updated)))
+}
+
+func (api *ContentAPI) handleDelete(w http.ResponseWriter, r *http.Request, slug, id string) {
+	err := api.tx.Tx(func() error {
+		existing, err := api.contentSvc.Get(slug, id)
+		if err != nil {
+			return err
+		}
+		if err := authorizeEntry(r, domain.ActionDelete, existing); err != nil {
+			return err
+		}
+		return api.contentSvc.Delete(slug, id)
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"deleted": true})
+}
+
+func (api *ContentAPI) handleGetSingle(w http.ResponseWriter, r *http.Request, slug string) {
+	draft, ok := readDrafts(w, r, api.previewToken, slug)
+	if !ok {
+		return
+	}
+
+	var entry domain.Entry
+	err := api.tx.Tx(func() error {
+		var err error
+		if draft {
+			entry, err = api.contentSvc.GetSingle(slug)
+		} else {
+			entry, err = api.contentSvc.GetSinglePublished(slug)
+		}
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	entry, ok = readView(callerFrom(r.Context()).policy, draft, entry)
+	if !ok {
+		writeServiceError(w, fmt.Errorf("%w: %s", domain.ErrEntryNotFound, slug))
+		return
+	}
+
+	resp := newEntryResponse(entry)
+	resp.ID = ""
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (api *ContentAPI) handlePutSingle(w http.ResponseWriter, r *http.Request, slug string) {
+	var data map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	c := callerFrom(r.Context())
+	now := time.Now().UTC()
+	entry := domain.Entry{
+		Model:     slug,
+		Status:    domain.StatusDraft,
+		CreatedBy: c.policy.Owner,
+		CreatedAt: now,
+	}
+	err := api.tx.Tx(func() error {
+		if existing, err := api.contentSvc.GetSingle(slug); err == nil {
+			entry = existing
+		}
+		data, err := c.policy.AuthorizeWrite(domain.ActionUpdate, entry, data)
+		if err != nil {
+			return err
+		}
+		entry.Data = data
+		entry.UpdatedAt = now
+
+		entry, err = api.contentSvc.PutSingle(entry)
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := newEntryResponse(c.entryView(domain.ActionUpdate, entry))
+	resp.ID = ""
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (api *ContentAPI) handleLifecycle(w http.ResponseWriter, r *http.Request, slug, id, action string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req ScheduleRequest
+	if action == "schedule" {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+	}
+
+	var entry domain.Entry
+	err := api.tx.Tx(func() error {
+		var err error
+		if id == "" {
+			entry, err = api.contentSvc.GetSingle(slug)
+		} else {
+			entry, err = api.contentSvc.Get(slug, id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := authorizeEntry(r, domain.ActionPublish, entry); err != nil {
+			return err
+		}
+
+		switch action {
+		case "publish":
+			entry, err = api.contentSvc.Publish(slug, id, time.Now().UTC())
+		case "unpublish":
+			entry, err = api.contentSvc.Unpublish(slug, id)
+		case "schedule":
+			entry, err = api.scheduler.Schedule(slug, id, timeValue(req.PublishAt), timeValue(req.UnpublishAt))
+		}
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := newEntryResponse(callerFrom(r.Context()).entryView(domain.ActionPublish, entry))
+	if id == "" {
+		resp.ID = ""
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// readDrafts reports whether the request asked for draft content of model
+// with ?status=draft. Drafts are only served to callers presenting the
+// preview token, and to callers whose policy allows previewing the model;
+// ok is false when an error response has already been written. GraphQL
+// passes domain.AnyModel and checks each model it resolves.
+func readDrafts(w http.ResponseWriter, r *http.Request, previewToken, model string) (draft bool, ok bool) {
+	switch r.URL.Query().Get("status") {
+	case "", "published":
+		return false, true
+	case "draft":
+	default:
+		writeError(w, http.StatusBadRequest, "status must be either 'draft' or 'published'")
+		return false, false
+	}
+
+	c := callerFrom(r.Context())
+	if !c.anonymous() {
+		if model != domain.AnyModel && !c.can(domain.ActionPreview, model) {
+			status, err := c.denied(domain.ActionPreview, model)
+			writeError(w, status, err.Error())
+			return false, false
+		}
+		return true, true
+	}
+	// The public role may allow anonymous previews.
+	if !c.open && c.can(domain.ActionPreview, model) {
+		return true, true
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if previewToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(previewToken)) != 1 {
+		writeError(w, http.StatusUnauthorized, "draft preview requires authentication")
+		return false, false
+	}
+	return true, true
+}
+
+// readAction returns the action of reading drafts or published entries.
+func readAction(draft bool) domain.Action {
+	if draft {
+		return domain.ActionPreview
+	}
+	return domain.ActionRead
+}
+
+// readView returns an entry as a caller with policy may read it, reporting
+// false when it may not. Published reads do not disclose who created entries.
+func readView(policy domain.Policy, draft bool, e domain.Entry) (domain.Entry, bool) {
+	e, ok := policy.FilterEntry(readAction(draft), e)
+	if !draft {
+		e.CreatedBy = ""
+	}
+	return e, ok
+}
+
+// authorizeEntry checks that the policy of the caller allows action on e.
+func authorizeEntry(r *http.Request, action domain.Action, e domain.Entry) error {
+	if !callerFrom(r.Context()).policy.AllowsEntry(action, e) {
+		return fmt.Errorf("%w: %s is not allowed on this %s entry", domain.ErrForbidden, action, e.Model)
+	}
+	return nil
+}
+
+func isLifecycleAction(action string) bool {
+	return action == "publish" || action == "unpublish" || action == "schedule"
+}
+
+func newEntryResponse(e domain.Entry) entryResponse {
+	resp := entryResponse{
+		ID:        e.ID,
+		Data:      e.Data,
+		Status:    string(e.Status),
+		CreatedBy: e.CreatedBy,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+	resp.PublishedAt = timePtr(e.PublishedAt)
+	resp.PublishAt = timePtr(e.PublishAt)
+	resp.UnpublishAt = timePtr(e.UnpublishAt)
+	return resp
+}
+
+// timePtr maps the zero time to nil so optional timestamps are omitted.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+// writeServiceError maps application and domain errors to HTTP statuses.
+// statusError is an error answered with a given status, returned by the
+// handlers reporting errors from their transactions.
+type statusError struct {
+	status int
+	err    error
+}
+
+func withStatus(status int, err error) error {
+	return &statusError{status: status, err: err}
+}
+
+func (e *statusError) Error() string { return e.err.Error() }
+func (e *statusError) Unwrap() error { return e.err }
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	var validationErr *domain.ValidationError
+	var statusErr *statusError
+	switch {
+	case errors.As(err, &statusErr):
+		writeError(w, statusErr.status, err.Error())
+	case errors.As(err, &validationErr):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		writeError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrModelNotFound), errors.Is(err, domain.ErrFieldNotFound), errors.Is(err, domain.ErrEntryNotFound),
+		errors.Is(err, domain.ErrTokenNotFound), errors.Is(err, domain.ErrWebhookNotFound), errors.Is(err, domain.ErrDeliveryNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrEntryAlreadyExists), errors.Is(err, domain.ErrModelAlreadyExists):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
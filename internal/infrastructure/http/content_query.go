@@ -0,0 +1,268 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// parseContentQuery reads a listing query from URL parameters:
+//
+//	filter[title][eq]=Hello             condition on a field
+//	filter[author.name][startsWith]=J   condition on a relation sub-field
+//	filter[tags][in]=a,b                "in" takes a comma separated list
+//	filter[or][0][views][gt]=10         nested and/or groups, indexed
+//	sort=-publishedAt,title             multi-key sort, "-" for descending
+//	fields=title,summary                field selection
+//	limit=10&offset=20                  offset pagination
+//	limit=10&after=<cursor>             cursor pagination
+//
+// Sibling conditions are combined with "and".
+func parseContentQuery(values url.Values) (domain.Query, error) {
+	var q domain.Query
+
+	filter, err := parseFilterParams(values)
+	if err != nil {
+		return domain.Query{}, err
+	}
+	q.Filter = filter
+
+	if s := values.Get("sort"); s != "" {
+		for _, key := range strings.Split(s, ",") {
+			key = strings.TrimSpace(key)
+			desc := strings.HasPrefix(key, "-")
+			key = strings.TrimPrefix(key, "-")
+			if key == "" {
+				return domain.Query{}, &domain.ValidationError{Field: "sort", Message: "empty sort key"}
+			}
+			q.Sort = append(q.Sort, domain.SortKey{Field: key, Desc: desc})
+		}
+	}
+
+	if s := values.Get("fields"); s != "" {
+		for _, name := range strings.Split(s, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				q.Fields = append(q.Fields, name)
+			}
+		}
+	}
+
+	if q.Limit, err = intParam(values, "limit"); err != nil {
+		return domain.Query{}, err
+	}
+	if q.Offset, err = intParam(values, "offset"); err != nil {
+		return domain.Query{}, err
+	}
+	q.After = values.Get("after")
+
+	return q, nil
+}
+
+// filterNode is the tree built from the bracketed filter parameters before
+// it is converted to a domain.Filter.
+type filterNode struct {
+	children map[string]*filterNode
+	values   []string
+}
+
+func parseFilterParams(values url.Values) (*domain.Filter, error) {
+	root := &filterNode{}
+	found := false
+
+	for key, vals := range values {
+		if !strings.HasPrefix(key, "filter[") {
+			continue
+		}
+		path, err := bracketPath(strings.TrimPrefix(key, "filter"))
+		if err != nil {
+			return nil, err
+		}
+
+		node := root
+		for _, part := range path {
+			if node.children == nil {
+				node.children = map[string]*filterNode{}
+			}
+			child, ok := node.children[part]
+			if !ok {
+				child = &filterNode{}
+				node.children[part] = child
+			}
+			node = child
+		}
+		node.values = append(node.values, vals...)
+		found = true
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	filter, err := root.toFilter("")
+	if err != nil {
+		return nil, err
+	}
+	return &filter, nil
+}
+
+// bracketPath splits "[a][b][c]" into its parts.
+func bracketPath(s string) ([]string, error) {
+	var parts []string
+	for s != "" {
+		if !strings.HasPrefix(s, "[") {
+			return nil, &domain.ValidationError{Field: "filter", Message: fmt.Sprintf("malformed filter parameter near '%s'", s)}
+		}
+		end := strings.Index(s, "]")
+		if end < 2 {
+			return nil, &domain.ValidationError{Field: "filter", Message: fmt.Sprintf("malformed filter parameter near '%s'", s)}
+		}
+		parts = append(parts, s[1:end])
+		s = s[end+1:]
+	}
+	return parts, nil
+}
+
+// toFilter converts a node to a filter. field is set when the node's children
+// are operators applied to that field.
+func (n *filterNode) toFilter(field string) (domain.Filter, error) {
+	keys := make([]string, 0, len(n.children))
+	for k := range n.children {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var conditions []domain.Filter
+	for _, key := range keys {
+		child := n.children[key]
+
+		switch {
+		case field != "":
+			op := domain.Operator(key)
+			if child.children != nil || len(child.values) == 0 {
+				return domain.Filter{}, &domain.ValidationError{Field: "filter", Message: fmt.Sprintf("%s: operator '%s' needs a value", field, key)}
+			}
+			conditions = append(conditions, domain.Filter{Field: field, Op: op, Value: opValue(op, child.values)})
+		case key == "and" || key == "or":
+			group, err := child.toGroup(key)
+			if err != nil {
+				return domain.Filter{}, err
+			}
+			conditions = append(conditions, group)
+		default:
+			if child.children == nil {
+				return domain.Filter{}, &domain.ValidationError{Field: "filter", Message: fmt.Sprintf("%s: missing operator", key)}
+			}
+			f, err := child.toFilter(key)
+			if err != nil {
+				return domain.Filter{}, err
+			}
+			conditions = append(conditions, f)
+		}
+	}
+
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+	return domain.Filter{And: conditions}, nil
+}
+
+// toGroup converts the indexed children of an and/or node.
+func (n *filterNode) toGroup(kind string) (domain.Filter, error) {
+	indexes := make([]int, 0, len(n.children))
+	byIndex := make(map[int]*filterNode, len(n.children))
+	for k, child := range n.children {
+		i, err := strconv.Atoi(k)
+		if err != nil {
+			return domain.Filter{}, &domain.ValidationError{Field: "filter", Message: fmt.Sprintf("%s: expected an index, got '%s'", kind, k)}
+		}
+		indexes = append(indexes, i)
+		byIndex[i] = child
+	}
+	sort.Ints(indexes)
+
+	group := make([]domain.Filter, 0, len(indexes))
+	for _, i := range indexes {
+		f, err := byIndex[i].toFilter("")
+		if err != nil {
+			return domain.Filter{}, err
+		}
+		group = append(group, f)
+	}
+	if len(group) == 0 {
+		return domain.Filter{}, &domain.ValidationError{Field: "filter", Message: fmt.Sprintf("%s: empty group", kind)}
+	}
+
+	if kind == "or" {
+		return domain.Filter{Or: group}, nil
+	}
+	return domain.Filter{And: group}, nil
+}
+
+func opValue(op domain.Operator, values []string) any {
+	if op != domain.OpIn {
+		return values[0]
+	}
+
+	var list []any
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func intParam(values url.Values, name string) (int, error) {
+	s := values.Get(name)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, &domain.ValidationError{Field: name, Message: "must be a non-negative integer"}
+	}
+	return n, nil
+}
+
+// parseAggregateQuery reads an aggregation from URL parameters, with the
+// filter syntax of parseContentQuery:
+//
+//	groupBy=category,author   fields to group entries by
+//	sum=views&avg=views,price one parameter per aggregation, listing fields
+//
+// Entries are always counted.
+func parseAggregateQuery(values url.Values) (domain.AggregateQuery, error) {
+	var q domain.AggregateQuery
+
+	filter, err := parseFilterParams(values)
+	if err != nil {
+		return domain.AggregateQuery{}, err
+	}
+	q.Filter = filter
+	q.GroupBy = listParam(values, "groupBy")
+
+	for _, fn := range []domain.AggregateFunc{domain.AggSum, domain.AggAvg, domain.AggMin, domain.AggMax} {
+		for _, field := range listParam(values, string(fn)) {
+			q.Aggregations = append(q.Aggregations, domain.Aggregation{Func: fn, Field: field})
+		}
+	}
+
+	return q, nil
+}
+
+// listParam collects the comma separated values of a repeatable parameter.
+func listParam(values url.Values, name string) []string {
+	var list []string
+	for _, v := range values[name] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
@@ -0,0 +1,208 @@
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// eventsHeartbeat is how often a comment is sent on idle event streams, so
+// that proxies keep them open.
+const eventsHeartbeat = 15 * time.Second
+
+// EventsAPI streams the changes of the project as server-sent events:
+//
+//	GET /api/events?model=article,page
+//
+// Each event has the type of the change as event name, its number in the
+// change log as ID and an eventResponse as data. Clients resume a stream
+// with the Last-Event-ID header, or the lastEventId parameter, and are sent
+// a reset event when the changes after it are no longer logged.
+type EventsAPI struct {
+	changes      *application.ChangeFeed
+	auth         *Authenticator
+	enableCORS   bool
+	previewToken string
+}
+
+type eventResponse struct {
+	Type          domain.EventType `json:"type"`
+	Model         string           `json:"model"`
+	EntryID       string           `json:"entryId,omitempty"`
+	PreviousModel string           `json:"previousModel,omitempty"`
+	Scheduled     bool             `json:"scheduled,omitempty"`
+	OccurredAt    time.Time        `json:"occurredAt"`
+}
+
+func NewEventsAPI(p *Project) *EventsAPI {
+	return &EventsAPI{
+		changes:      p.changes,
+		auth:         NewAuthenticator(p),
+		enableCORS:   p.config.Development.EnableCORS,
+		previewToken: p.config.Content.PreviewToken,
+	}
+}
+
+func (api *EventsAPI) Register(mux *http.ServeMux) {
+	mux.Handle("/api/events", api.auth.Require(nil, api))
+}
+
+func (api *EventsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if api.enableCORS && writeCORS(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	models := modelsParam(r)
+	visible, status, err := changeAccess(r, models, api.previewToken)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		writeError(w, status, err.Error())
+		return
+	}
+
+	after := int64(-1)
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("lastEventId")
+	}
+	if lastID != "" {
+		after, err = strconv.ParseInt(lastID, 10, 64)
+		if err != nil || after < 0 {
+			w.Header().Set("Content-Type", "application/json")
+			writeError(w, http.StatusBadRequest, "Last-Event-ID must be the ID of an event of the stream")
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	sub, missed, complete, err := api.changes.Subscribe(after)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		writeServiceError(w, err)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(c domain.Change) {
+		if !changeVisible(c, models, visible) {
+			return
+		}
+		data, _ := json.Marshal(newEventResponse(c.Event))
+		fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", c.Seq, c.Event.Type, data)
+	}
+
+	if !complete {
+		fmt.Fprint(w, "event: reset\ndata: {\"message\":\"the changes after Last-Event-ID are no longer logged, reload the content\"}\n\n")
+	}
+	for _, c := range missed {
+		send(c)
+	}
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case c, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			send(c)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// modelsParam returns the models of the model parameters, each a comma
+// separated list.
+func modelsParam(r *http.Request) []string {
+	var models []string
+	for _, v := range r.URL.Query()["model"] {
+		for _, m := range strings.Split(v, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				models = append(models, m)
+			}
+		}
+	}
+	return models
+}
+
+// changeAccess returns whether the caller may see the changes of a model.
+// Changes reveal drafts, so seeing them requires what reading drafts does: a
+// policy allowing previews, or else the preview token. Projects without admin
+// users are open to every caller, as their content API is. Every model of the
+// filter must be visible.
+func changeAccess(r *http.Request, models []string, previewToken string) (visible func(string) bool, status int, err error) {
+	c := callerFrom(r.Context())
+	if c.open {
+		return func(string) bool { return true }, 0, nil
+	}
+	if !c.anonymous() || c.policy.AllowsSomeModel(domain.ActionPreview) {
+		if len(models) == 0 && !c.policy.AllowsSomeModel(domain.ActionPreview) {
+			status, err := c.denied(domain.ActionPreview, domain.AnyModel)
+			return nil, status, err
+		}
+		for _, m := range models {
+			if !c.can(domain.ActionPreview, m) {
+				status, err := c.denied(domain.ActionPreview, m)
+				return nil, status, err
+			}
+		}
+		return func(model string) bool { return c.can(domain.ActionPreview, model) }, 0, nil
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if previewToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(previewToken)) != 1 {
+		return nil, http.StatusUnauthorized, errAuthenticationRequired
+	}
+	return func(string) bool { return true }, 0, nil
+}
+
+// changeVisible reports whether c concerns one of models, when given, and
+// is visible.
+func changeVisible(c domain.Change, models []string, visible func(string) bool) bool {
+	if len(models) > 0 && !slices.Contains(models, c.Event.Model) && !slices.Contains(models, c.Event.PreviousModel) {
+		return false
+	}
+	return visible(c.Event.Model)
+}
+
+func newEventResponse(e domain.Event) eventResponse {
+	return eventResponse{
+		Type:          e.Type,
+		Model:         e.Model,
+		EntryID:       e.EntryID,
+		PreviousModel: e.PreviousModel,
+		Scheduled:     e.Scheduled,
+		OccurredAt:    e.OccurredAt,
+	}
+}
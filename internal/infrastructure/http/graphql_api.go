@@ -0,0 +1,182 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// GraphQLAPI serves a GraphQL API generated from the models. The schema is
+// regenerated whenever the models change, including when their files are
+// edited by hand.
+type GraphQLAPI struct {
+	tx           application.Transactor
+	modelSvc     *application.ModelService
+	contentSvc   *application.ContentService
+	auth         *Authenticator
+	enableCORS   bool
+	previewToken string
+
+	fingerprint [sha256.Size]byte
+	public      graphql.Schema
+	preview     graphql.Schema
+}
+
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+func NewGraphQLAPI(p *Project) *GraphQLAPI {
+	return &GraphQLAPI{
+		tx:           p.tx,
+		modelSvc:     p.modelSvc,
+		contentSvc:   p.contentSvc,
+		auth:         NewAuthenticator(p),
+		enableCORS:   p.config.Development.EnableCORS,
+		previewToken: p.config.Content.PreviewToken,
+	}
+}
+
+func (api *GraphQLAPI) Register(mux *http.ServeMux) {
+	// Resolvers check the permissions of the caller on each model.
+	mux.Handle("/graphql", api.auth.Require(nil, api))
+}
+
+func (api *GraphQLAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if api.enableCORS && writeCORS(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req GraphQLRequest
+	switch r.Method {
+	case http.MethodGet:
+		values := r.URL.Query()
+		req.Query = values.Get("query")
+		req.OperationName = values.Get("operationName")
+		if v := values.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid variables")
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// GET requests and forms can be sent by any site along with the session
+	// cookie of the caller, so they may only run queries.
+	if r.Method == http.MethodGet && operationType(req.Query, req.OperationName) == ast.OperationTypeMutation {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "mutations must be sent with POST")
+		return
+	}
+	if c := callerFrom(r.Context()); r.Method == http.MethodPost && (c.user != nil || c.open) && !isJSONRequest(r) {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+
+	draft, ok := readDrafts(w, r, api.previewToken, domain.AnyModel)
+	if !ok {
+		return
+	}
+
+	// The fields of a document are resolved in one transaction. Like the
+	// mutations of GraphQL, it is not rolled back when some of them fail.
+	var result *graphql.Result
+	err := api.tx.Tx(func() error {
+		if err := api.refreshSchemas(); err != nil {
+			return err
+		}
+
+		schema := api.public
+		if draft {
+			schema = api.preview
+		}
+
+		c := callerFrom(r.Context())
+		ctx := context.WithValue(r.Context(), graphqlRequestKey{}, &graphqlRequest{
+			loader: newEntryLoader(api.contentSvc, draft, c.policy),
+			caller: c,
+		})
+		result = graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			OperationName:  req.OperationName,
+			VariableValues: req.Variables,
+			Context:        ctx,
+		})
+		return nil
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// operationType returns the type of the operation of document that a request
+// runs, or "" when the document is invalid, which graphql.Do reports.
+func operationType(document, operationName string) string {
+	doc, err := parser.Parse(parser.ParseParams{Source: document})
+	if err != nil {
+		return ""
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+			return op.Operation
+		}
+	}
+	return ""
+}
+
+// refreshSchemas rebuilds the schemas when the models differ from the ones
+// they were built from.
+func (api *GraphQLAPI) refreshSchemas() error {
+	models, err := api.modelSvc.List()
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(models)
+	if err != nil {
+		return err
+	}
+	fingerprint := sha256.Sum256(encoded)
+	if fingerprint == api.fingerprint && api.public.QueryType() != nil {
+		return nil
+	}
+
+	public, err := buildGraphQLSchema(models, api.contentSvc, false)
+	if err != nil {
+		return err
+	}
+	preview, err := buildGraphQLSchema(models, api.contentSvc, true)
+	if err != nil {
+		return err
+	}
+
+	api.public, api.preview, api.fingerprint = public, preview, fingerprint
+	return nil
+}
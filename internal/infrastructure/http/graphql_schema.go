@@ -0,0 +1,719 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/graphql-go/graphql"
+)
+
+// graphqlBuilder generates a GraphQL schema from the models of a project.
+// Every model gets an object type named after its slug ("blog-post" becomes
+// BlogPost). Collections are read with blogPost(id) and
+// blogPostList(filter, sort, limit, offset, after) and written with
+// createBlogPost, updateBlogPost and deleteBlogPost. Singles are read with
+// blogPost and written with updateBlogPost.
+//
+// A schema is built for previews and one for public readers, which only see
+// published fields. Resolvers apply the policy of the caller, so entries and
+// fields it may not read resolve to null.
+type graphqlBuilder struct {
+	content *application.ContentService
+	preview bool
+	models  map[string]domain.Model
+	objects map[string]*graphql.Object
+	inputs  map[string]*graphql.InputObject
+}
+
+var (
+	graphqlTime = graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "Time",
+		Description: "An RFC 3339 timestamp.",
+		Serialize: func(value any) any {
+			if t, ok := value.(time.Time); ok {
+				if t.IsZero() {
+					return nil
+				}
+				return t.UTC().Format(time.RFC3339Nano)
+			}
+			return nil
+		},
+	})
+
+	graphqlStringFilter  = newScalarFilter("StringFilter", graphql.String, true)
+	graphqlFloatFilter   = newScalarFilter("FloatFilter", graphql.Float, false)
+	graphqlBooleanFilter = newScalarFilter("BooleanFilter", graphql.Boolean, false)
+	graphqlIDFilter      = newScalarFilter("IDFilter", graphql.ID, false)
+)
+
+// newScalarFilter builds the input type holding the operators applicable to
+// a scalar, e.g. {eq: "a", startsWith: "b"}.
+func newScalarFilter(name string, scalar graphql.Input, text bool) *graphql.InputObject {
+	ops := []domain.Operator{domain.OpEq, domain.OpNe, domain.OpIn, domain.OpNull}
+	if scalar != graphql.Boolean && scalar != graphql.ID {
+		ops = append(ops, domain.OpLt, domain.OpGt)
+	}
+	if text {
+		ops = append(ops, domain.OpContains, domain.OpStartsWith)
+	}
+
+	fields := graphql.InputObjectConfigFieldMap{}
+	for _, op := range ops {
+		switch op {
+		case domain.OpIn:
+			fields[string(op)] = &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(scalar))}
+		case domain.OpNull:
+			fields[string(op)] = &graphql.InputObjectFieldConfig{Type: graphql.Boolean}
+		default:
+			fields[string(op)] = &graphql.InputObjectFieldConfig{Type: scalar}
+		}
+	}
+
+	return graphql.NewInputObject(graphql.InputObjectConfig{Name: name, Fields: fields})
+}
+
+func buildGraphQLSchema(models []domain.Model, content *application.ContentService, preview bool) (graphql.Schema, error) {
+	b := &graphqlBuilder{
+		content: content,
+		preview: preview,
+		models:  make(map[string]domain.Model, len(models)),
+		objects: make(map[string]*graphql.Object, len(models)),
+		inputs:  make(map[string]*graphql.InputObject, len(models)),
+	}
+	for _, m := range models {
+		b.models[m.Slug] = m
+	}
+	for _, m := range models {
+		b.objects[m.Slug] = b.object(m)
+	}
+
+	query := graphql.Fields{}
+	mutation := graphql.Fields{}
+	read := readAction(preview)
+	for _, m := range models {
+		name := application.ModelTypeName(m.Slug)
+		field := lowerFirst(name)
+
+		if m.Kind == domain.KindSingle {
+			query[field] = authorize(read, m.Slug, b.singleField(m))
+			mutation["update"+name] = authorize(domain.ActionUpdate, m.Slug, b.putSingleField(m))
+			continue
+		}
+
+		query[field] = authorize(read, m.Slug, b.getField(m))
+		query[field+"List"] = authorize(read, m.Slug, b.listField(m))
+		mutation["create"+name] = authorize(domain.ActionCreate, m.Slug, b.createField(m))
+		mutation["update"+name] = authorize(domain.ActionUpdate, m.Slug, b.updateField(m))
+		mutation["delete"+name] = authorize(domain.ActionDelete, m.Slug, b.deleteField(m))
+	}
+
+	// GraphQL requires the query type to have at least one field.
+	if len(query) == 0 {
+		query["_empty"] = &graphql.Field{Type: graphql.Boolean}
+	}
+
+	config := graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: query}),
+	}
+	if len(mutation) > 0 {
+		config.Mutation = graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: mutation})
+	}
+	return graphql.NewSchema(config)
+}
+
+// fields returns the model fields exposed in the schema by GraphQL name.
+// Fields whose name clashes with a system field or another field once
+// converted to a GraphQL name are left out.
+func (b *graphqlBuilder) fields(m domain.Model) map[string]domain.Field {
+	fields := make(map[string]domain.Field, len(m.Fields))
+	for _, f := range m.Fields {
+		if !f.IsVisible(b.preview) {
+			continue
+		}
+		if f.Type == domain.FieldRelation {
+			if _, ok := b.models[f.Target]; !ok {
+				continue
+			}
+		}
+
+		name := graphqlFieldName(f.Name)
+		if _, system := graphqlSystemFields[name]; system {
+			continue
+		}
+		if _, taken := fields[name]; taken {
+			continue
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+var graphqlSystemFields = map[string]struct{}{
+	"id": {}, "status": {}, "createdBy": {}, "createdAt": {}, "updatedAt": {}, "publishedAt": {}, "publishAt": {}, "unpublishAt": {},
+}
+
+func (b *graphqlBuilder) object(m domain.Model) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        application.ModelTypeName(m.Slug),
+		Description: m.Description,
+		// Fields are resolved lazily so that models can relate to each other.
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := graphql.Fields{
+				"status":      entryField(graphql.NewNonNull(graphql.String), func(e domain.Entry) any { return string(e.Status) }),
+				"createdBy":   entryField(graphql.String, func(e domain.Entry) any { return nilIfEmpty(e.CreatedBy) }),
+				"createdAt":   entryField(graphql.NewNonNull(graphqlTime), func(e domain.Entry) any { return e.CreatedAt }),
+				"updatedAt":   entryField(graphql.NewNonNull(graphqlTime), func(e domain.Entry) any { return e.UpdatedAt }),
+				"publishedAt": entryField(graphqlTime, func(e domain.Entry) any { return e.PublishedAt }),
+				"publishAt":   entryField(graphqlTime, func(e domain.Entry) any { return e.PublishAt }),
+				"unpublishAt": entryField(graphqlTime, func(e domain.Entry) any { return e.UnpublishAt }),
+			}
+			if m.Kind != domain.KindSingle {
+				fields["id"] = entryField(graphql.NewNonNull(graphql.ID), func(e domain.Entry) any { return e.ID })
+			}
+
+			for name, f := range b.fields(m) {
+				fields[name] = b.dataField(f)
+			}
+			return fields
+		}),
+	})
+}
+
+func entryField(t graphql.Output, value func(domain.Entry) any) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			e, ok := p.Source.(domain.Entry)
+			if !ok {
+				return nil, nil
+			}
+			return value(e), nil
+		},
+	}
+}
+
+func (b *graphqlBuilder) dataField(f domain.Field) *graphql.Field {
+	if f.Type == domain.FieldRelation {
+		target := f.Target
+		return &graphql.Field{
+			Type:        b.objects[target],
+			Description: f.Description,
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				e, _ := p.Source.(domain.Entry)
+				id, _ := e.Data[f.Name].(string)
+				if id == "" {
+					return nil, nil
+				}
+				return graphqlRequestFrom(p.Context).loader.load(target, id), nil
+			},
+		}
+	}
+
+	return &graphql.Field{
+		Type:        graphqlScalar(f.Type),
+		Description: f.Description,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			e, _ := p.Source.(domain.Entry)
+			return e.Data[f.Name], nil
+		},
+	}
+}
+
+// graphqlScalar returns the scalar of the values of fields of type t, the
+// scalar of their ValueType for custom field types.
+func graphqlScalar(t domain.FieldType) *graphql.Scalar {
+	if t == domain.FieldRelation {
+		return graphql.ID
+	}
+	switch domain.FieldValueType(t) {
+	case domain.ValueNumber:
+		return graphql.Float
+	case domain.ValueBoolean:
+		return graphql.Boolean
+	default:
+		return graphql.String
+	}
+}
+
+func graphqlFilterFor(t domain.FieldType) *graphql.InputObject {
+	if t == domain.FieldRelation {
+		return graphqlIDFilter
+	}
+	switch domain.FieldValueType(t) {
+	case domain.ValueNumber:
+		return graphqlFloatFilter
+	case domain.ValueBoolean:
+		return graphqlBooleanFilter
+	default:
+		return graphqlStringFilter
+	}
+}
+
+func (b *graphqlBuilder) getField(m domain.Model) *graphql.Field {
+	return &graphql.Field{
+		Type: b.objects[m.Slug],
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			id, _ := p.Args["id"].(string)
+			return graphqlRequestFrom(p.Context).loader.load(m.Slug, id), nil
+		},
+	}
+}
+
+func (b *graphqlBuilder) singleField(m domain.Model) *graphql.Field {
+	return &graphql.Field{
+		Type: b.objects[m.Slug],
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			var entry domain.Entry
+			var err error
+			if b.preview {
+				entry, err = b.content.GetSingle(m.Slug)
+			} else {
+				entry, err = b.content.GetSinglePublished(m.Slug)
+			}
+			if err != nil {
+				return nil, nilIfNotFound(err)
+			}
+			entry, ok := readView(graphqlRequestFrom(p.Context).caller.policy, b.preview, entry)
+			if !ok {
+				return nil, nil
+			}
+			return entry, nil
+		},
+	}
+}
+
+func (b *graphqlBuilder) listField(m domain.Model) *graphql.Field {
+	name := application.ModelTypeName(m.Slug)
+	page := graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Page",
+		Fields: graphql.Fields{
+			"data": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(b.objects[m.Slug]))),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(domain.EntryPage).Entries, nil
+				},
+			},
+			"total": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(domain.EntryPage).Total, nil
+				},
+			},
+			"nextCursor": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if cursor := p.Source.(domain.EntryPage).NextCursor; cursor != "" {
+						return cursor, nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+
+	filter, convert := b.filter(m)
+	return &graphql.Field{
+		Type: graphql.NewNonNull(page),
+		Args: graphql.FieldConfigArgument{
+			"filter": &graphql.ArgumentConfig{Type: filter},
+			"sort": &graphql.ArgumentConfig{
+				Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+				Description: `Fields to sort by, prefixed with "-" for descending order.`,
+			},
+			"limit":  &graphql.ArgumentConfig{Type: graphql.Int},
+			"offset": &graphql.ArgumentConfig{Type: graphql.Int},
+			"after":  &graphql.ArgumentConfig{Type: graphql.String},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			var q domain.Query
+			if arg, ok := p.Args["filter"].(map[string]any); ok {
+				f := convert(arg)
+				q.Filter = &f
+			}
+			if keys, ok := p.Args["sort"].([]any); ok {
+				for _, k := range keys {
+					key, _ := k.(string)
+					q.Sort = append(q.Sort, domain.SortKey{Field: strings.TrimPrefix(key, "-"), Desc: strings.HasPrefix(key, "-")})
+				}
+			}
+			q.Limit, _ = p.Args["limit"].(int)
+			q.Offset, _ = p.Args["offset"].(int)
+			q.After, _ = p.Args["after"].(string)
+
+			policy := graphqlRequestFrom(p.Context).caller.policy
+			page, err := b.content.Query(m.Slug, q, b.preview, policy)
+			if err != nil {
+				return nil, err
+			}
+			for i, e := range page.Entries {
+				page.Entries[i], _ = readView(policy, b.preview, e)
+			}
+			return page, nil
+		},
+	}
+}
+
+// filter builds the filter input type of a model and the function converting
+// its values to a domain.Filter.
+func (b *graphqlBuilder) filter(m domain.Model) (*graphql.InputObject, func(map[string]any) domain.Filter) {
+	fields := b.fields(m)
+	columns := map[string]string{}
+	filters := map[string]*graphql.InputObject{}
+
+	for name, f := range fields {
+		columns[name] = f.Name
+		filters[name] = graphqlFilterFor(f.Type)
+	}
+	columns["id"] = domain.SystemFieldID
+	filters["id"] = graphqlIDFilter
+	for _, name := range []string{domain.SystemFieldCreatedBy, domain.SystemFieldCreatedAt, domain.SystemFieldUpdatedAt, domain.SystemFieldPublishedAt} {
+		columns[name] = name
+		filters[name] = graphqlStringFilter
+	}
+
+	var input *graphql.InputObject
+	input = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: application.ModelTypeName(m.Slug) + "Filter",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			config := graphql.InputObjectConfigFieldMap{
+				"and": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(input))},
+				"or":  &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(input))},
+			}
+			for name, filter := range filters {
+				config[name] = &graphql.InputObjectFieldConfig{Type: filter}
+			}
+			return config
+		}),
+	})
+
+	var convert func(map[string]any) domain.Filter
+	convert = func(arg map[string]any) domain.Filter {
+		var conditions []domain.Filter
+		for name, value := range arg {
+			switch name {
+			case "and", "or":
+				list, _ := value.([]any)
+				group := make([]domain.Filter, 0, len(list))
+				for _, item := range list {
+					if child, ok := item.(map[string]any); ok {
+						group = append(group, convert(child))
+					}
+				}
+				if name == "or" {
+					conditions = append(conditions, domain.Filter{Or: group})
+				} else {
+					conditions = append(conditions, domain.Filter{And: group})
+				}
+			default:
+				ops, _ := value.(map[string]any)
+				for op, v := range ops {
+					conditions = append(conditions, domain.Filter{Field: columns[name], Op: domain.Operator(op), Value: v})
+				}
+			}
+		}
+		if len(conditions) == 1 {
+			return conditions[0]
+		}
+		return domain.Filter{And: conditions}
+	}
+
+	return input, convert
+}
+
+// input builds the input type holding the data of an entry of the model.
+func (b *graphqlBuilder) input(m domain.Model) (*graphql.InputObject, func(map[string]any) map[string]any) {
+	fields := b.fields(m)
+	config := graphql.InputObjectConfigFieldMap{}
+	for name, f := range fields {
+		config[name] = &graphql.InputObjectFieldConfig{Type: graphqlScalar(f.Type), Description: f.Description}
+	}
+
+	input, ok := b.inputs[m.Slug]
+	if !ok {
+		input = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name:   application.ModelTypeName(m.Slug) + "Input",
+			Fields: config,
+		})
+		b.inputs[m.Slug] = input
+	}
+	convert := func(arg map[string]any) map[string]any {
+		data := make(map[string]any, len(arg))
+		for name, value := range arg {
+			if f, ok := fields[name]; ok {
+				data[f.Name] = value
+			}
+		}
+		return data
+	}
+	return input, convert
+}
+
+func (b *graphqlBuilder) createField(m domain.Model) *graphql.Field {
+	input, convert := b.input(m)
+	return &graphql.Field{
+		Type: b.objects[m.Slug],
+		Args: graphql.FieldConfigArgument{
+			"data": &graphql.ArgumentConfig{Type: graphql.NewNonNull(input)},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			data, _ := p.Args["data"].(map[string]any)
+			c := graphqlRequestFrom(p.Context).caller
+
+			now := time.Now().UTC()
+			entry := domain.Entry{
+				ID:        newID(),
+				Model:     m.Slug,
+				Status:    domain.StatusDraft,
+				CreatedBy: c.policy.Owner,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			written, err := c.policy.AuthorizeWrite(domain.ActionCreate, entry, convert(data))
+			if err != nil {
+				return nil, err
+			}
+			entry.Data = written
+			if entry, err = b.content.Create(entry); err != nil {
+				return nil, err
+			}
+			return c.entryView(domain.ActionCreate, entry), nil
+		},
+	}
+}
+
+func (b *graphqlBuilder) updateField(m domain.Model) *graphql.Field {
+	input, convert := b.input(m)
+	return &graphql.Field{
+		Type: b.objects[m.Slug],
+		Args: graphql.FieldConfigArgument{
+			"id":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+			"data": &graphql.ArgumentConfig{Type: graphql.NewNonNull(input)},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			id, _ := p.Args["id"].(string)
+			data, _ := p.Args["data"].(map[string]any)
+
+			c := graphqlRequestFrom(p.Context).caller
+
+			entry, err := b.content.Get(m.Slug, id)
+			if err != nil {
+				return nil, err
+			}
+			written, err := c.policy.AuthorizeWrite(domain.ActionUpdate, entry, convert(data))
+			if err != nil {
+				return nil, err
+			}
+			entry.Data = written
+			entry.UpdatedAt = time.Now().UTC()
+			if entry, err = b.content.Update(entry); err != nil {
+				return nil, err
+			}
+			return c.entryView(domain.ActionUpdate, entry), nil
+		},
+	}
+}
+
+func (b *graphqlBuilder) deleteField(m domain.Model) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(graphql.Boolean),
+		Args: graphql.FieldConfigArgument{
+			"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			id, _ := p.Args["id"].(string)
+			entry, err := b.content.Get(m.Slug, id)
+			if err != nil {
+				return false, err
+			}
+			if !graphqlRequestFrom(p.Context).caller.policy.AllowsEntry(domain.ActionDelete, entry) {
+				return false, fmt.Errorf("%w: %s is not allowed on this %s entry", domain.ErrForbidden, domain.ActionDelete, m.Slug)
+			}
+			if err := b.content.Delete(m.Slug, id); err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+	}
+}
+
+func (b *graphqlBuilder) putSingleField(m domain.Model) *graphql.Field {
+	input, convert := b.input(m)
+	return &graphql.Field{
+		Type: b.objects[m.Slug],
+		Args: graphql.FieldConfigArgument{
+			"data": &graphql.ArgumentConfig{Type: graphql.NewNonNull(input)},
+		},
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			data, _ := p.Args["data"].(map[string]any)
+			c := graphqlRequestFrom(p.Context).caller
+
+			now := time.Now().UTC()
+			entry := domain.Entry{
+				Model:     m.Slug,
+				Status:    domain.StatusDraft,
+				CreatedBy: c.policy.Owner,
+				CreatedAt: now,
+			}
+			if existing, err := b.content.GetSingle(m.Slug); err == nil {
+				entry = existing
+			}
+			written, err := c.policy.AuthorizeWrite(domain.ActionUpdate, entry, convert(data))
+			if err != nil {
+				return nil, err
+			}
+			entry.Data = written
+			entry.UpdatedAt = now
+
+			if entry, err = b.content.PutSingle(entry); err != nil {
+				return nil, err
+			}
+			return c.entryView(domain.ActionUpdate, entry), nil
+		},
+	}
+}
+
+// authorize rejects the calls of a root field made by callers that may not do
+// action on model.
+func authorize(action domain.Action, model string, f *graphql.Field) *graphql.Field {
+	resolve := f.Resolve
+	f.Resolve = func(p graphql.ResolveParams) (any, error) {
+		req := graphqlRequestFrom(p.Context)
+		if req == nil {
+			return nil, errAuthenticationRequired
+		}
+		if !req.caller.can(action, model) {
+			_, err := req.caller.denied(action, model)
+			return nil, err
+		}
+		return resolve(p)
+	}
+	return f
+}
+
+// graphqlRequest holds the state shared by the resolvers of a request.
+type graphqlRequest struct {
+	loader *entryLoader
+	caller caller
+}
+
+type graphqlRequestKey struct{}
+
+func graphqlRequestFrom(ctx context.Context) *graphqlRequest {
+	req, _ := ctx.Value(graphqlRequestKey{}).(*graphqlRequest)
+	return req
+}
+
+// entryLoader batches the loading of entries by ID. Resolvers register the
+// IDs they need and return a thunk; the executor runs the thunks of a level
+// of the response once all its resolvers ran, so the first thunk loads the
+// entries of every pending ID of the model with a single query. Entries the
+// policy does not allow reading, including related entries, are not loaded.
+type entryLoader struct {
+	content *application.ContentService
+	preview bool
+	policy  domain.Policy
+	pending map[string][]any
+	loaded  map[string]map[string]domain.Entry
+}
+
+func newEntryLoader(content *application.ContentService, preview bool, policy domain.Policy) *entryLoader {
+	return &entryLoader{
+		content: content,
+		preview: preview,
+		policy:  policy,
+		pending: map[string][]any{},
+		loaded:  map[string]map[string]domain.Entry{},
+	}
+}
+
+func (l *entryLoader) load(slug, id string) func() (any, error) {
+	if _, ok := l.loaded[slug][id]; !ok {
+		l.pending[slug] = append(l.pending[slug], id)
+	}
+
+	return func() (any, error) {
+		if err := l.flush(slug); err != nil {
+			return nil, err
+		}
+		entry, ok := l.loaded[slug][id]
+		if !ok || entry.ID == "" {
+			return nil, nil
+		}
+		return entry, nil
+	}
+}
+
+func (l *entryLoader) flush(slug string) error {
+	ids := l.pending[slug]
+	if len(ids) == 0 {
+		return nil
+	}
+	delete(l.pending, slug)
+
+	page, err := l.content.Query(slug, domain.Query{
+		Filter: &domain.Filter{Field: domain.SystemFieldID, Op: domain.OpIn, Value: ids},
+	}, l.preview, l.policy)
+	if err != nil && !errors.Is(err, domain.ErrForbidden) {
+		return err
+	}
+
+	loaded := l.loaded[slug]
+	if loaded == nil {
+		loaded = map[string]domain.Entry{}
+		l.loaded[slug] = loaded
+	}
+	// Missing entries are remembered as zero entries so they are not queried
+	// again.
+	for _, id := range ids {
+		if _, ok := loaded[id.(string)]; !ok {
+			loaded[id.(string)] = domain.Entry{}
+		}
+	}
+	for _, e := range page.Entries {
+		loaded[e.ID], _ = readView(l.policy, l.preview, e)
+	}
+	return nil
+}
+
+func nilIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nilIfNotFound turns not found errors into a null result, as GraphQL
+// clients expect for missing objects.
+func nilIfNotFound(err error) error {
+	if errors.Is(err, domain.ErrEntryNotFound) {
+		return nil
+	}
+	return err
+}
+
+var graphqlInvalidChars = regexp.MustCompile(`[^_0-9A-Za-z]`)
+
+// graphqlFieldName converts a field name to a valid GraphQL name.
+func graphqlFieldName(name string) string {
+	name = graphqlInvalidChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// loginPage is served in place of the admin panel to browsers without a
+// session. It posts to the login endpoint, which redirects back to the page
+// first requested.
+var loginPage = template.Must(template.New("login").Parse(`<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Log in · VectraG</title>
+<style>
+  body { font-family: system-ui, sans-serif; background: #f4f4f5; display: flex; align-items: center; justify-content: center; min-height: 100vh; margin: 0; }
+  form { background: #fff; padding: 2rem; border-radius: 0.5rem; box-shadow: 0 1px 3px rgba(0, 0, 0, 0.1); width: 20rem; }
+  h1 { font-size: 1.25rem; margin: 0 0 1.5rem; }
+  label { display: block; font-size: 0.875rem; margin-bottom: 1rem; }
+  input { display: block; width: 100%; box-sizing: border-box; margin-top: 0.25rem; padding: 0.5rem; border: 1px solid #d4d4d8; border-radius: 0.25rem; }
+  button { width: 100%; padding: 0.5rem; border: 0; border-radius: 0.25rem; background: #18181b; color: #fff; cursor: pointer; }
+  .error { color: #b91c1c; font-size: 0.875rem; margin: 0 0 1rem; }
+</style>
+</head>
+<body>
+<form method="post" action="/api/auth/login">
+  <h1>Log in to VectraG</h1>
+  {{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+  <input type="hidden" name="redirect" value="{{.Redirect}}">
+  <label>Username <input name="username" autocomplete="username" required autofocus></label>
+  <label>Password <input name="password" type="password" autocomplete="current-password" required></label>
+  <button type="submit">Log in</button>
+</form>
+</body>
+</html>
+`))
+
+func writeLoginPage(w http.ResponseWriter, status int, redirect, errMsg string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = loginPage.Execute(w, struct {
+		Redirect string
+		Error    string
+	}{redirect, errMsg})
+}
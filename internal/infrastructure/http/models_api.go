@@ -2,97 +2,133 @@ package http
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
-	"os"
-	"path/filepath"
-	"regexp"
+	"net/url"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/axarus/vectrag/internal/application"
 	"github.com/axarus/vectrag/internal/domain"
-	"github.com/axarus/vectrag/internal/infrastructure/filestore"
 	"github.com/google/uuid"
 )
 
 type ModelsAPI struct {
-	mu         sync.Mutex
-	modelsDir  string
+	tx         application.Transactor
+	project    *Project
 	modelSvc   *application.ModelService
+	migrations *application.MigrationService
+	auth       *Authenticator
 	enableCORS bool
 }
 
 type CreateModelRequest struct {
 	Name        string             `json:"name"`
 	Description string             `json:"description,omitempty"`
+	Kind        string             `json:"kind,omitempty"`
 	Status      string             `json:"status"`
 	Fields      []CreateFieldInput `json:"fields"`
 }
 
 type CreateFieldInput struct {
+	Name        string         `json:"name"`
+	Type        string         `json:"type"`
+	Target      string         `json:"target,omitempty"`
+	Options     map[string]any `json:"options,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Unique      bool           `json:"unique,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Status      string         `json:"status"`
+}
+
+type UpdateModelRequest struct {
 	Name        string `json:"name"`
-	Type        string `json:"type"`
 	Description string `json:"description,omitempty"`
-	Unique      bool   `json:"unique,omitempty"`
-	Required    bool   `json:"required,omitempty"`
-	Status      string `json:"status"`
+	// Kind is left unchanged when empty. It cannot change once the model
+	// has entries.
+	Kind   string             `json:"kind,omitempty"`
+	Status string             `json:"status"`
+	Fields []UpdateFieldInput `json:"fields"`
+	// DryRun reports the conversions of the values of fields whose type
+	// changes without updating the model.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Force applies type changes that are not safe, replacing the values
+	// that cannot be converted by the fallback of their field, keyed by
+	// name.
+	Force     bool           `json:"force,omitempty"`
+	Fallbacks map[string]any `json:"fallbacks,omitempty"`
 }
 
-type UpdateModelRequest struct {
-	Name        string             `json:"name"`
-	Description string             `json:"description,omitempty"`
-	Status      string             `json:"status"`
-	Fields      []UpdateFieldInput `json:"fields"`
+// TypeChangeReport is the response of dry runs, and of updates blocked by
+// unsafe type changes.
+type TypeChangeReport struct {
+	Error       string                        `json:"error,omitempty"`
+	Conversions []application.FieldConversion `json:"conversions"`
+}
+
+// RenameModelRequest changes the name and the slug of a model. Empty values
+// are left unchanged.
+type RenameModelRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// renameModelResponse is the renamed model, with the warnings of
+// Project.RenameModel.
+type renameModelResponse struct {
+	domain.Model
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 type UpdateFieldInput struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Type        string `json:"type"`
-	Description string `json:"description,omitempty"`
-	Unique      bool   `json:"unique,omitempty"`
-	Required    bool   `json:"required,omitempty"`
-	Status      string `json:"status"`
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Type        string         `json:"type"`
+	Target      string         `json:"target,omitempty"`
+	Options     map[string]any `json:"options,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Unique      bool           `json:"unique,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Status      string         `json:"status"`
 }
 
-func NewModelsAPI(projectRoot string) (*ModelsAPI, error) {
-	cfg, err := application.LoadProjectConfig(projectRoot)
-	if err != nil {
-		return nil, err
-	}
-	modelsDir, err := application.ResolveModelsDir(projectRoot, cfg)
-	if err != nil {
-		return nil, err
-	}
+// fieldTypeResponse describes a field type to the admin panel.
+type fieldTypeResponse struct {
+	Type      string          `json:"type"`
+	ValueType string          `json:"valueType"`
+	Column    string          `json:"column"`
+	Builtin   bool            `json:"builtin"`
+	UI        fieldUIResponse `json:"ui"`
+}
 
-	repo, err := filestore.NewYamlRepository(modelsDir)
-	if err != nil {
-		return nil, err
-	}
+type fieldUIResponse struct {
+	Widget      string `json:"widget"`
+	Placeholder string `json:"placeholder,omitempty"`
+}
 
+func NewModelsAPI(p *Project) *ModelsAPI {
 	return &ModelsAPI{
-		modelsDir:  modelsDir,
-		modelSvc:   application.NewModelService(repo),
-		enableCORS: cfg.Development.EnableCORS,
-	}, nil
+		tx:         p.tx,
+		project:    p,
+		modelSvc:   p.modelSvc,
+		migrations: p.migrations,
+		auth:       NewAuthenticator(p),
+		enableCORS: p.config.Development.EnableCORS,
+	}
 }
 
 func (api *ModelsAPI) Register(mux *http.ServeMux) {
-	mux.Handle("/api/models", api)
-	mux.Handle("/api/models/", api)
+	h := api.auth.Require(modelsPermission, api)
+	mux.Handle("/api/models", h)
+	mux.Handle("/api/models/", h)
+	mux.Handle("/api/migrations", h)
+	// Field types are not specific to a model, any caller may list them.
+	mux.Handle("/api/field-types", api.auth.Require(nil, api))
 }
 
 func (api *ModelsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if api.enableCORS {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
+	if api.enableCORS && writeCORS(w, r) {
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -112,13 +148,58 @@ func (api *ModelsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if path == "api/field-types" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		api.handleFieldTypes(w, r)
+		return
+	}
+
+	if path == "api/migrations" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		api.handleMigrations(w, r)
+		return
+	}
+
 	if strings.HasPrefix(path, "api/models/") {
-		slug := strings.TrimPrefix(path, "api/models/")
-		slug = strings.Trim(slug, "/")
+		rest := strings.Trim(strings.TrimPrefix(path, "api/models/"), "/")
+		slug, action, _ := strings.Cut(rest, "/")
 		if slug == "" {
 			writeError(w, http.StatusNotFound, "not found")
 			return
 		}
+		var target string
+		var alias bool
+		if err := api.tx.Tx(func() error {
+			target, alias = api.migrations.ResolveAlias(slug)
+			return nil
+		}); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if alias {
+			redirectAlias(w, r, "/api/models/", slug, target)
+			return
+		}
+
+		switch action {
+		case "":
+		case "rename":
+			if r.Method != http.MethodPost {
+				writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+				return
+			}
+			api.handleRename(w, r, slug)
+			return
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
 
 		switch r.Method {
 		case http.MethodGet:
@@ -140,10 +221,12 @@ func (api *ModelsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (api *ModelsAPI) handleList(w http.ResponseWriter, r *http.Request) {
-	api.mu.Lock()
-	defer api.mu.Unlock()
-	
-	models, err := api.modelSvc.List()
+	var models []domain.Model
+	err := api.tx.Tx(func() error {
+		var err error
+		models, err = api.modelSvc.List()
+		return err
+	})
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -153,10 +236,12 @@ func (api *ModelsAPI) handleList(w http.ResponseWriter, r *http.Request) {
 }
 
 func (api *ModelsAPI) handleGet(w http.ResponseWriter, r *http.Request, slug string) {
-	api.mu.Lock()
-	defer api.mu.Unlock()
-
-	model, err := api.modelSvc.Get(slug)
+	var model domain.Model
+	err := api.tx.Tx(func() error {
+		var err error
+		model, err = api.modelSvc.Get(slug)
+		return err
+	})
 	if err != nil {
 		writeError(w, http.StatusNotFound, err.Error())
 		return
@@ -166,26 +251,18 @@ func (api *ModelsAPI) handleGet(w http.ResponseWriter, r *http.Request, slug str
 }
 
 func (api *ModelsAPI) handleCreate(w http.ResponseWriter, r *http.Request) {
-	api.mu.Lock()
-	defer api.mu.Unlock()
-
 	var req CreateModelRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid JSON")
 		return
 	}
 
-	slug, err := slugify(req.Name)
+	slug, err := application.Slugify(req.Name)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if _, err := os.Stat(filepath.Join(api.modelsDir, slug+".yaml")); err == nil {
-		writeError(w, http.StatusConflict, "model already exists")
-		return
-	}
-
 	modelID := newID()
 
 	fields := make([]domain.Field, len(req.Fields))
@@ -196,6 +273,8 @@ func (api *ModelsAPI) handleCreate(w http.ResponseWriter, r *http.Request) {
 			ID:          fieldID,
 			Name:        f.Name,
 			Type:        domain.FieldType(f.Type),
+			Target:      f.Target,
+			Options:     f.Options,
 			Description: f.Description,
 			Unique:      f.Unique,
 			Required:    f.Required,
@@ -210,6 +289,7 @@ func (api *ModelsAPI) handleCreate(w http.ResponseWriter, r *http.Request) {
 		Name:          req.Name,
 		Slug:          slug,
 		Description:   req.Description,
+		Kind:          modelKind(req.Kind),
 		Fields:        fields,
 		Status:        domain.Status(req.Status),
 		SchemaVersion: 1,
@@ -220,8 +300,15 @@ func (api *ModelsAPI) handleCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := api.modelSvc.Create(model); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+	err = api.tx.Tx(func() error {
+		err := api.modelSvc.Create(model)
+		if err != nil && !errors.Is(err, domain.ErrModelAlreadyExists) {
+			return withStatus(http.StatusBadRequest, err)
+		}
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
 
@@ -229,71 +316,118 @@ func (api *ModelsAPI) handleCreate(w http.ResponseWriter, r *http.Request) {
 }
 
 func (api *ModelsAPI) handleUpdate(w http.ResponseWriter, r *http.Request, slug string) {
-	api.mu.Lock()
-	defer api.mu.Unlock()
-
-	existing, err := api.modelSvc.Get(slug)
-	if err != nil {
-		writeError(w, http.StatusNotFound, err.Error())
-		return
-	}
-
-	existingFieldByID := make(map[string]domain.Field, len(existing.Fields))
-	for _, f := range existing.Fields {
-		existingFieldByID[f.ID] = f
-	}
-
 	var req UpdateModelRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid JSON")
 		return
 	}
 
-	fields := make([]domain.Field, len(req.Fields))
-	for i, f := range req.Fields {
-		fieldID := strings.TrimSpace(f.ID)
-		if fieldID == "" {
-			fieldID = newID()
+	var updated domain.Model
+	var report *TypeChangeReport
+	status := http.StatusOK
+	err := api.tx.Tx(func() error {
+		existing, err := api.modelSvc.Get(slug)
+		if err != nil {
+			return withStatus(http.StatusNotFound, err)
 		}
 
-		createdAt := time.Time{}
-		if prev, ok := existingFieldByID[fieldID]; ok {
-			createdAt = prev.CreatedAt
+		existingFieldByID := make(map[string]domain.Field, len(existing.Fields))
+		for _, f := range existing.Fields {
+			existingFieldByID[f.ID] = f
 		}
-		if createdAt.IsZero() {
-			createdAt = time.Now().UTC()
+
+		fields := make([]domain.Field, len(req.Fields))
+		for i, f := range req.Fields {
+			fieldID := strings.TrimSpace(f.ID)
+			if fieldID == "" {
+				fieldID = newID()
+			}
+
+			createdAt := time.Time{}
+			if prev, ok := existingFieldByID[fieldID]; ok {
+				createdAt = prev.CreatedAt
+			}
+			if createdAt.IsZero() {
+				createdAt = time.Now().UTC()
+			}
+
+			fields[i] = domain.Field{
+				ID:          fieldID,
+				Name:        f.Name,
+				Type:        domain.FieldType(f.Type),
+				Target:      f.Target,
+				Options:     f.Options,
+				Description: f.Description,
+				Unique:      f.Unique,
+				Required:    f.Required,
+				Status:      domain.Status(f.Status),
+				CreatedAt:   createdAt,
+				UpdatedAt:   time.Now().UTC(),
+			}
 		}
 
-		fields[i] = domain.Field{
-			ID:          fieldID,
-			Name:        f.Name,
-			Type:        domain.FieldType(f.Type),
-			Description: f.Description,
-			Unique:      f.Unique,
-			Required:    f.Required,
-			Status:      domain.Status(f.Status),
-			CreatedAt:   createdAt,
-			UpdatedAt:   time.Now().UTC(),
+		updated = domain.Model{
+			ID:            existing.ID,
+			Name:          req.Name,
+			Slug:          existing.Slug,
+			Description:   req.Description,
+			Kind:          existing.Kind,
+			Fields:        fields,
+			Status:        domain.Status(req.Status),
+			SchemaVersion: existing.SchemaVersion,
+		}
+		if req.Kind != "" {
+			updated.Kind = domain.ModelKind(req.Kind)
 		}
-	}
 
-	updated := domain.Model{
-		ID:            existing.ID,
-		Name:          req.Name,
-		Slug:          existing.Slug,
-		Description:   req.Description,
-		Fields:        fields,
-		Status:        domain.Status(req.Status),
-		SchemaVersion: existing.SchemaVersion,
-	}
+		if err := domain.ValidateModel(updated); err != nil {
+			return withStatus(http.StatusBadRequest, err)
+		}
+		if err := api.migrations.CheckKindChange(existing, updated); err != nil {
+			var verr *domain.ValidationError
+			if errors.As(err, &verr) {
+				return withStatus(http.StatusConflict, err)
+			}
+			return withStatus(http.StatusInternalServerError, err)
+		}
 
-	if err := domain.ValidateModel(updated); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		plan, err := api.migrations.PlanTypeChanges(existing, updated, application.TypeChangeOptions{
+			Force:     req.Force,
+			Fallbacks: req.Fallbacks,
+		})
+		if err != nil && !errors.Is(err, domain.ErrUnsafeTypeChange) {
+			return withStatus(http.StatusInternalServerError, err)
+		}
+		if req.DryRun || err != nil {
+			report = &TypeChangeReport{Conversions: plan.Conversions}
+			if err != nil {
+				report.Error = err.Error()
+				if !req.DryRun {
+					status = http.StatusConflict
+				}
+			}
+			if report.Conversions == nil {
+				report.Conversions = []application.FieldConversion{}
+			}
+			return nil
+		}
+
+		// The entries are converted before the model is saved, so a failure
+		// rolls back the update without leaving the model ahead of its entries.
+		if err := api.migrations.ApplyTypeChanges(plan); err != nil {
+			return withStatus(http.StatusInternalServerError, err)
+		}
+		if updated, err = api.modelSvc.Update(updated); err != nil {
+			return withStatus(http.StatusBadRequest, err)
+		}
+		return nil
+	})
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
-
-	if err := api.modelSvc.Update(updated); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+	if report != nil {
+		writeJSON(w, status, report)
 		return
 	}
 
@@ -301,50 +435,118 @@ func (api *ModelsAPI) handleUpdate(w http.ResponseWriter, r *http.Request, slug
 }
 
 func (api *ModelsAPI) handleDelete(w http.ResponseWriter, r *http.Request, slug string) {
-	api.mu.Lock()
-	defer api.mu.Unlock()
-
-	if err := api.modelSvc.Delete(slug); err != nil {
-		writeError(w, http.StatusNotFound, err.Error())
+	err := api.tx.Tx(func() error {
+		if err := api.modelSvc.Delete(slug); err != nil {
+			return withStatus(http.StatusNotFound, err)
+		}
+		return nil
+	})
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]any{"deleted": true})
 }
 
-func writeJSON(w http.ResponseWriter, status int, v any) {
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(v)
+func (api *ModelsAPI) handleRename(w http.ResponseWriter, r *http.Request, slug string) {
+	var req RenameModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	model, warnings, err := api.project.RenameModel(slug, req.Name, req.Slug)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, renameModelResponse{Model: model, Warnings: warnings})
 }
 
-func writeError(w http.ResponseWriter, status int, msg string) {
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(map[string]any{"error": msg})
+func (api *ModelsAPI) handleMigrations(w http.ResponseWriter, r *http.Request) {
+	var migrations []domain.Migration
+	err := api.tx.Tx(func() error {
+		var err error
+		migrations, err = api.migrations.History()
+		return err
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, migrations)
 }
 
-var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+func (api *ModelsAPI) handleFieldTypes(w http.ResponseWriter, r *http.Request) {
+	types := []fieldTypeResponse{}
+	for _, name := range domain.ListFieldTypes() {
+		p, ok := domain.LookupFieldType(domain.FieldType(name))
+		if !ok {
+			continue
+		}
+		ui := p.UI()
+		types = append(types, fieldTypeResponse{
+			Type:      name,
+			ValueType: string(p.ValueType()),
+			Column:    string(p.Column()),
+			Builtin:   domain.IsBuiltinType(p.Type()),
+			UI:        fieldUIResponse{Widget: ui.Widget, Placeholder: ui.Placeholder},
+		})
+	}
+	writeJSON(w, http.StatusOK, types)
+}
 
-func slugify(name string) (string, error) {
-	s := strings.TrimSpace(name)
-	if s == "" {
-		return "", fmt.Errorf("name cannot be empty")
+// redirectAlias redirects a request using the former slug of a renamed model
+// to the same path with the new slug. The path keeps the prefix the API is
+// mounted under, which http.StripPrefix removed from r.URL but not from the
+// request URI.
+func redirectAlias(w http.ResponseWriter, r *http.Request, prefix, slug, target string) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), slug)
+	mount := ""
+	if original, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		if m, ok := strings.CutSuffix(original.Path, r.URL.Path); ok {
+			mount = m
+		}
 	}
+	u := *r.URL
+	u.Path = mount + prefix + target + rest
+	u.RawPath = ""
+	w.Header().Set("Location", u.RequestURI())
+	writeError(w, http.StatusPermanentRedirect, "model renamed to "+target)
+}
 
-	s = strings.ToLower(s)
-	s = slugRegex.ReplaceAllString(s, "-")
-	s = strings.Trim(s, "-")
-	if s == "" {
-		return "", fmt.Errorf("name results in empty slug")
+// modelKind defaults an omitted kind to a collection.
+func modelKind(kind string) domain.ModelKind {
+	if kind == "" {
+		return domain.KindCollection
 	}
+	return domain.ModelKind(kind)
+}
 
-	m, _ := regexp.MatchString(`^[a-z0-9-]+$`, s)
-	if !m {
-		return "", fmt.Errorf("invalid slug")
+// writeCORS sets the development CORS headers and reports whether the request
+// was a preflight that has been fully answered.
+func writeCORS(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return true
 	}
+	return false
+}
 
-	return s, nil
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"error": msg})
 }
 
 func newID() string {
 	id := uuid.New().String()
-	return id 
+	return id
 }
@@ -0,0 +1,1041 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// OpenAPIDocument is an OpenAPI 3.1 description of the HTTP API of a project.
+// It encodes to JSON with sorted keys, so the same models always produce the
+// same document.
+type OpenAPIDocument map[string]any
+
+// OpenAPIAPI serves the OpenAPI document of the current models.
+type OpenAPIAPI struct {
+	project    *Project
+	auth       *Authenticator
+	enableCORS bool
+}
+
+func NewOpenAPIAPI(p *Project) *OpenAPIAPI {
+	return &OpenAPIAPI{
+		project:    p,
+		auth:       NewAuthenticator(p),
+		enableCORS: p.config.Development.EnableCORS,
+	}
+}
+
+func (api *OpenAPIAPI) Register(mux *http.ServeMux) {
+	mux.Handle("/api/openapi.json", api.auth.Require(nil, api))
+}
+
+func (api *OpenAPIAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if api.enableCORS && writeCORS(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	doc, err := api.project.OpenAPI()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// OpenAPI describes the models API and the content API of every model.
+func (p *Project) OpenAPI() (OpenAPIDocument, error) {
+	var models []domain.Model
+	err := p.tx.Tx(func() error {
+		var err error
+		models, err = p.modelSvc.List()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewOpenAPIDocument(p.config.Project, models), nil
+}
+
+func NewOpenAPIDocument(info application.ProjectInfo, models []domain.Model) OpenAPIDocument {
+	title := "VectraG API"
+	if info.Name != "" {
+		title = info.Name + " API"
+	}
+	version := info.Version
+	if version == "" {
+		version = "1.0.0"
+	}
+
+	paths := map[string]any{}
+	schemas := openAPIBaseSchemas()
+	addAuthPaths(paths)
+	addTokensPaths(paths)
+	addWebhooksPaths(paths)
+	addEventsPaths(paths)
+	addChangesPaths(paths)
+	addModelsPaths(paths)
+
+	for _, m := range models {
+		name := application.ModelTypeName(m.Slug)
+		schemas[name+"Data"] = openAPIDataSchema(m, false)
+		schemas[name+"Entry"] = openAPIEntrySchema(name + "Data")
+		schemas[name+"DraftData"] = openAPIDataSchema(m, true)
+		schemas[name+"DraftEntry"] = openAPIEntrySchema(name + "DraftData")
+
+		if m.Kind == domain.KindSingle {
+			addSinglePaths(paths, m, name)
+		} else {
+			addCollectionPaths(paths, m, name)
+		}
+	}
+	requireCredentials(paths)
+
+	return OpenAPIDocument{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   title,
+			"version": version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"previewToken": map[string]any{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "The content.previewToken of the project, required to read drafts with status=draft.",
+				},
+				"adminSession": map[string]any{
+					"type":        "apiKey",
+					"in":          "cookie",
+					"name":        sessionCookie,
+					"description": "The session cookie set by POST /api/auth/login, required once the project has admin users.",
+				},
+				"apiToken": map[string]any{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "An API token created with vectrag token create or POST /api/tokens, limited to its scopes.",
+				},
+			},
+		},
+	}
+}
+
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{
+		"application/json": map[string]any{"schema": schema},
+	}
+}
+
+func jsonResponse(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content":     jsonContent(schema),
+	}
+}
+
+func jsonBody(schema map[string]any) map[string]any {
+	return map[string]any{
+		"required": true,
+		"content":  jsonContent(schema),
+	}
+}
+
+// withErrors adds the error responses an operation can return.
+func withErrors(responses map[string]any, statuses ...int) map[string]any {
+	descriptions := map[int]string{
+		http.StatusBadRequest:   "Invalid request",
+		http.StatusUnauthorized: "Missing or invalid preview token",
+		http.StatusForbidden:    "The role or API token of the caller does not allow the request",
+		http.StatusNotFound:     "Not found",
+		http.StatusConflict:     "Conflict",
+	}
+	for _, status := range statuses {
+		responses[fmt.Sprint(status)] = jsonResponse(descriptions[status], ref("Error"))
+	}
+	return responses
+}
+
+func pathParam(name, description string) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"in":          "path",
+		"required":    true,
+		"description": description,
+		"schema":      map[string]any{"type": "string"},
+	}
+}
+
+func queryParam(name, description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"in":          "query",
+		"description": description,
+		"schema":      schema,
+	}
+}
+
+var statusParam = queryParam("status", "Read the draft version of entries and draft fields. Requires the preview token.",
+	map[string]any{"type": "string", "enum": []string{"published", "draft"}, "default": "published"})
+
+// previewSecurity makes the preview token optional, it is only checked for
+// draft reads.
+var previewSecurity = []any{map[string]any{}, map[string]any{"previewToken": []string{}}, map[string]any{"apiToken": []string{}}}
+
+var adminSecurity = []any{map[string]any{"adminSession": []string{}}, map[string]any{"apiToken": []string{}}}
+
+func openAPIBaseSchemas() map[string]any {
+	timestamp := map[string]any{"type": "string", "format": "date-time"}
+	status := map[string]any{"type": "string", "enum": []string{string(domain.StatusDraft), string(domain.StatusPublish), string(domain.StatusDelete)}}
+	types := domain.ListFieldTypes()
+	sort.Strings(types)
+	fieldType := map[string]any{"type": "string", "enum": types}
+	kind := map[string]any{"type": "string", "enum": []string{string(domain.KindCollection), string(domain.KindSingle)}}
+	scopes := map[string]any{
+		"type":        "array",
+		"items":       map[string]any{"type": "string"},
+		"description": "Scopes written action:model, where either may be *, or the presets read-only and full-access. Actions: read, preview, create, update, delete, publish, schema, admin.",
+	}
+	eventTypes := make([]string, len(domain.EventTypes))
+	for i, t := range domain.EventTypes {
+		eventTypes[i] = string(t)
+	}
+	eventType := map[string]any{"type": "string", "enum": eventTypes}
+	apiToken := func(created bool) map[string]any {
+		props := map[string]any{
+			"id":         map[string]any{"type": "string"},
+			"name":       map[string]any{"type": "string"},
+			"prefix":     map[string]any{"type": "string", "description": "Start of the token, telling tokens apart."},
+			"scopes":     scopes,
+			"createdAt":  timestamp,
+			"expiresAt":  timestamp,
+			"lastUsedAt": timestamp,
+		}
+		required := []string{"id", "name", "prefix", "scopes", "createdAt"}
+		if created {
+			props["token"] = map[string]any{"type": "string", "description": "The token, sent as bearer token. It cannot be retrieved again."}
+			required = append(required, "token")
+		}
+		return map[string]any{"type": "object", "required": required, "properties": props}
+	}
+
+	fieldInput := func(withID bool) map[string]any {
+		props := map[string]any{
+			"name":        map[string]any{"type": "string"},
+			"type":        fieldType,
+			"target":      map[string]any{"type": "string", "description": "Slug of the target model of a relation field."},
+			"options":     map[string]any{"type": "object", "description": "Options of a field of a custom type, checked by the type."},
+			"description": map[string]any{"type": "string"},
+			"unique":      map[string]any{"type": "boolean"},
+			"required":    map[string]any{"type": "boolean"},
+			"status":      status,
+		}
+		if withID {
+			props["id"] = map[string]any{"type": "string", "description": "ID of an existing field, empty for new fields."}
+		}
+		return map[string]any{"type": "object", "required": []string{"name", "type", "status"}, "properties": props}
+	}
+	modelInput := func(field string) map[string]any {
+		return map[string]any{
+			"type":     "object",
+			"required": []string{"name", "status", "fields"},
+			"properties": map[string]any{
+				"name":        map[string]any{"type": "string"},
+				"description": map[string]any{"type": "string"},
+				"kind":        kind,
+				"status":      status,
+				"fields":      map[string]any{"type": "array", "items": ref(field)},
+			},
+		}
+	}
+
+	updateModel := modelInput("UpdateFieldInput")
+	updateProps := updateModel["properties"].(map[string]any)
+	updateProps["dryRun"] = map[string]any{"type": "boolean", "description": "Report the conversions of the values of fields whose type changes without updating the model."}
+	updateProps["force"] = map[string]any{"type": "boolean", "description": "Apply type changes that are not safe, replacing the values that cannot be converted by the fallback of their field."}
+	updateProps["fallbacks"] = map[string]any{"type": "object", "description": "Fallback values of forced type changes, keyed by field name."}
+
+	return map[string]any{
+		"Error": map[string]any{
+			"type":       "object",
+			"required":   []string{"error"},
+			"properties": map[string]any{"error": map[string]any{"type": "string"}},
+		},
+		"Field": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"ID":          map[string]any{"type": "string"},
+				"Name":        map[string]any{"type": "string"},
+				"Type":        fieldType,
+				"Target":      map[string]any{"type": "string"},
+				"Options":     map[string]any{"type": "object"},
+				"Description": map[string]any{"type": "string"},
+				"Unique":      map[string]any{"type": "boolean"},
+				"Required":    map[string]any{"type": "boolean"},
+				"Status":      status,
+				"CreatedAt":   timestamp,
+				"UpdatedAt":   timestamp,
+				"DeletedAt":   timestamp,
+			},
+		},
+		"Model": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"ID":            map[string]any{"type": "string"},
+				"Name":          map[string]any{"type": "string"},
+				"Slug":          map[string]any{"type": "string"},
+				"Description":   map[string]any{"type": "string"},
+				"Kind":          kind,
+				"Fields":        map[string]any{"type": "array", "items": ref("Field")},
+				"Status":        status,
+				"SchemaVersion": map[string]any{"type": "integer"},
+				"DeletedAt":     timestamp,
+			},
+		},
+		"FieldType": map[string]any{
+			"type":     "object",
+			"required": []string{"type", "valueType", "column", "builtin", "ui"},
+			"properties": map[string]any{
+				"type":      map[string]any{"type": "string"},
+				"valueType": map[string]any{"type": "string", "enum": []string{string(domain.ValueString), string(domain.ValueNumber), string(domain.ValueBoolean)}, "description": "JSON type of the values."},
+				"column":    map[string]any{"type": "string", "description": "Kind of SQL column storing the values."},
+				"builtin":   map[string]any{"type": "boolean"},
+				"ui": map[string]any{
+					"type":     "object",
+					"required": []string{"widget"},
+					"properties": map[string]any{
+						"widget":      map[string]any{"type": "string", "description": "Input editing the values in the admin panel."},
+						"placeholder": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+		"CreateFieldInput":   fieldInput(false),
+		"UpdateFieldInput":   fieldInput(true),
+		"CreateModelRequest": modelInput("CreateFieldInput"),
+		"UpdateModelRequest": updateModel,
+		"TypeChangeReport": map[string]any{
+			"type":     "object",
+			"required": []string{"conversions"},
+			"properties": map[string]any{
+				"error": map[string]any{"type": "string", "description": "Why the update is blocked, if it is."},
+				"conversions": map[string]any{"type": "array", "items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"Field":     map[string]any{"type": "string"},
+						"From":      fieldType,
+						"To":        fieldType,
+						"Safety":    map[string]any{"type": "string", "enum": []string{string(domain.ConversionSafe), string(domain.ConversionLossy), string(domain.ConversionImpossible)}},
+						"Converted": map[string]any{"type": "integer"},
+						"Failures": map[string]any{"type": "array", "items": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"EntryID":   map[string]any{"type": "string"},
+								"Published": map[string]any{"type": "boolean"},
+								"Value":     map[string]any{},
+								"Error":     map[string]any{"type": "string"},
+							},
+						}},
+					},
+				}},
+			},
+		},
+		"RenameModelRequest": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{"type": "string", "description": "New name, unchanged when empty."},
+				"slug": map[string]any{"type": "string", "description": "New slug, unchanged when empty."},
+			},
+		},
+		"LoginRequest": map[string]any{
+			"type":     "object",
+			"required": []string{"username", "password"},
+			"properties": map[string]any{
+				"username": map[string]any{"type": "string"},
+				"password": map[string]any{"type": "string", "format": "password"},
+			},
+		},
+		"SessionResponse": map[string]any{
+			"type":     "object",
+			"required": []string{"authRequired"},
+			"properties": map[string]any{
+				"authRequired": map[string]any{"type": "boolean", "description": "False for projects without admin users, which require no session."},
+				"username":     map[string]any{"type": "string"},
+				"role":         map[string]any{"type": "string", "description": "Role of the user, deciding what it may do."},
+				"expiresAt":    timestamp,
+			},
+		},
+		"CreateTokenRequest": map[string]any{
+			"type":     "object",
+			"required": []string{"name", "scopes"},
+			"properties": map[string]any{
+				"name":      map[string]any{"type": "string"},
+				"scopes":    scopes,
+				"expiresAt": map[string]any{"type": "string", "format": "date-time", "description": "Never expires when omitted."},
+			},
+		},
+		"APIToken":        apiToken(false),
+		"CreatedAPIToken": apiToken(true),
+		"Webhook": map[string]any{
+			"type":     "object",
+			"required": []string{"name", "url", "events", "signed"},
+			"properties": map[string]any{
+				"name": map[string]any{"type": "string"},
+				"url":  map[string]any{"type": "string", "format": "uri"},
+				"events": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Event types sent to the webhook, or model.*, entry.* and * for several of them.",
+				},
+				"models": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Models whose events are sent, all models when omitted."},
+				"signed": map[string]any{"type": "boolean", "description": "Whether requests carry an X-Vectrag-Signature header."},
+			},
+		},
+		"WebhookEvent": map[string]any{
+			"type":     "object",
+			"required": []string{"type", "model", "occurredAt"},
+			"properties": map[string]any{
+				"type":          eventType,
+				"model":         map[string]any{"type": "string"},
+				"entryId":       map[string]any{"type": "string", "description": "Omitted for model events."},
+				"previousModel": map[string]any{"type": "string", "description": "Former slug of a renamed model."},
+				"scheduled":     map[string]any{"type": "boolean", "description": "Whether a scheduled transition caused the event."},
+				"occurredAt":    timestamp,
+			},
+		},
+		"WebhookDelivery": map[string]any{
+			"type":     "object",
+			"required": []string{"id", "webhook", "event", "status", "attempts", "createdAt"},
+			"properties": map[string]any{
+				"id":             map[string]any{"type": "string", "description": "Also sent in the X-Vectrag-Delivery header, unchanged across attempts."},
+				"webhook":        map[string]any{"type": "string"},
+				"event":          ref("WebhookEvent"),
+				"status":         map[string]any{"type": "string", "enum": []string{string(domain.DeliveryPending), string(domain.DeliverySucceeded), string(domain.DeliveryFailed)}},
+				"attempts":       map[string]any{"type": "integer"},
+				"nextAttemptAt":  timestamp,
+				"lastAttemptAt":  timestamp,
+				"responseStatus": map[string]any{"type": "integer", "description": "Status code of the last response, omitted when none was received."},
+				"lastError":      map[string]any{"type": "string"},
+				"createdAt":      timestamp,
+			},
+		},
+		"ChangePage": map[string]any{
+			"type":     "object",
+			"required": []string{"changes", "next", "more", "complete"},
+			"properties": map[string]any{
+				"changes": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type":     "object",
+						"required": []string{"seq", "event"},
+						"properties": map[string]any{
+							"seq":   map[string]any{"type": "integer", "description": "Number of the change in the change log, increasing and never reused."},
+							"event": ref("WebhookEvent"),
+						},
+					},
+				},
+				"next":     map[string]any{"type": "integer", "description": "Offset to read the next page after."},
+				"more":     map[string]any{"type": "boolean", "description": "Whether changes follow the page."},
+				"complete": map[string]any{"type": "boolean", "description": "False when changes after the offset were dropped from the log, the content should then be reloaded."},
+			},
+		},
+		"Migration": map[string]any{
+			"type":     "object",
+			"required": []string{"ID", "Type", "Model", "From", "To", "At"},
+			"properties": map[string]any{
+				"ID":    map[string]any{"type": "string"},
+				"Type":  map[string]any{"type": "string", "enum": []string{string(domain.MigrationRenameModel), string(domain.MigrationChangeFieldType)}},
+				"Model": map[string]any{"type": "string"},
+				"Field": map[string]any{"type": "string"},
+				"From":  map[string]any{"type": "string"},
+				"To":    map[string]any{"type": "string"},
+				"At":    map[string]any{"type": "string", "format": "date-time"},
+			},
+		},
+		"ScheduleRequest": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"publishAt":   map[string]any{"type": []string{"string", "null"}, "format": "date-time"},
+				"unpublishAt": map[string]any{"type": []string{"string", "null"}, "format": "date-time"},
+			},
+		},
+		"ListMeta": map[string]any{
+			"type":     "object",
+			"required": []string{"total"},
+			"properties": map[string]any{
+				"total":      map[string]any{"type": "integer"},
+				"limit":      map[string]any{"type": "integer"},
+				"offset":     map[string]any{"type": "integer"},
+				"nextCursor": map[string]any{"type": "string"},
+			},
+		},
+		"AggregateGroup": map[string]any{
+			"type":     "object",
+			"required": []string{"group", "count"},
+			"properties": map[string]any{
+				"group": map[string]any{"type": "object", "description": "Value of each groupBy field."},
+				"count": map[string]any{"type": "integer"},
+				"sum":   map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "number"}},
+				"avg":   map[string]any{"type": "object", "additionalProperties": map[string]any{"type": []string{"number", "null"}}},
+				"min":   map[string]any{"type": "object"},
+				"max":   map[string]any{"type": "object"},
+			},
+		},
+	}
+}
+
+// openAPIDataSchema describes the data of the entries of a model, as
+// exported by vectrag model export, with the description of each field
+// completed for readers of the API reference. Deleted fields are left out:
+// their values can no longer be read or written. Draft fields are only
+// described by the draft schema, so public consumers are not affected by
+// the fields being staged.
+func openAPIDataSchema(m domain.Model, draft bool) map[string]any {
+	schema := application.DataJSONSchema(m)
+	props := schema["properties"].(map[string]any)
+	var required []string
+	for _, f := range m.ActiveFields() {
+		if !f.IsVisible(draft) {
+			delete(props, f.Name)
+			continue
+		}
+		if f.Required {
+			required = append(required, f.Name)
+		}
+
+		field := props[f.Name].(map[string]any)
+		description := f.Description
+		if f.Type == domain.FieldRelation {
+			description = joinSentences(description, fmt.Sprintf("ID of an entry of the %s model.", f.Target))
+		}
+		if f.Unique {
+			description = joinSentences(description, "Unique across entries.")
+		}
+		if f.Status == domain.StatusDraft {
+			description = joinSentences(description, "Draft field, only visible with status=draft.")
+		}
+		if description != "" {
+			field["description"] = description
+		}
+	}
+	delete(schema, "required")
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// readEntrySchema is the entry returned by reads, the draft entry when read
+// with status=draft.
+func readEntrySchema(name string) map[string]any {
+	return map[string]any{
+		"anyOf":       []any{ref(name + "Entry"), ref(name + "DraftEntry")},
+		"description": fmt.Sprintf("A %sEntry, or a %sDraftEntry when read with status=draft.", name, name),
+	}
+}
+
+func joinSentences(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + " " + b
+}
+
+func openAPIEntrySchema(data string) map[string]any {
+	timestamp := map[string]any{"type": "string", "format": "date-time"}
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"data", "status", "createdAt", "updatedAt"},
+		"properties": map[string]any{
+			"id":          map[string]any{"type": "string", "description": "Omitted for single models."},
+			"data":        ref(data),
+			"status":      map[string]any{"type": "string", "enum": []string{string(domain.StatusDraft), string(domain.StatusPublish)}},
+			"publishedAt": timestamp,
+			"publishAt":   timestamp,
+			"unpublishAt": timestamp,
+			"createdBy":   map[string]any{"type": "string", "description": `Admin user, or "token:" and the ID of the API token, that created the entry. Only returned with drafts.`},
+			"createdAt":   timestamp,
+			"updatedAt":   timestamp,
+		},
+	}
+}
+
+func addAuthPaths(paths map[string]any) {
+	tags := []string{"auth"}
+
+	paths["/api/auth/login"] = map[string]any{
+		"post": map[string]any{
+			"operationId": "login",
+			"tags":        tags,
+			"summary":     "Open an admin session",
+			"description": "Sets the session cookie. Form submissions are redirected to their redirect field instead.",
+			"requestBody": jsonBody(ref("LoginRequest")),
+			"responses": map[string]any{
+				"200": jsonResponse("The session", ref("SessionResponse")),
+				"401": jsonResponse("Invalid username or password", ref("Error")),
+			},
+		},
+	}
+	paths["/api/auth/logout"] = map[string]any{
+		"post": map[string]any{
+			"operationId": "logout",
+			"tags":        tags,
+			"summary":     "End the admin session",
+			"responses": map[string]any{
+				"204": map[string]any{"description": "The session ended"},
+			},
+		},
+	}
+	paths["/api/auth/session"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "getSession",
+			"tags":        tags,
+			"summary":     "Get the admin session of the caller",
+			"responses": map[string]any{
+				"200": jsonResponse("The session", ref("SessionResponse")),
+				"401": jsonResponse("Missing or invalid admin session", ref("Error")),
+			},
+		},
+	}
+}
+
+// requireCredentials marks the operations requiring an admin session or an
+// API token: every operation of the models, tokens and webhooks APIs, and the
+// operations changing content.
+func requireCredentials(paths map[string]any) {
+	for path, item := range paths {
+		models := strings.HasPrefix(path, "/api/models") || path == "/api/migrations" || strings.HasPrefix(path, "/api/tokens") ||
+			strings.HasPrefix(path, "/api/webhooks")
+		if !models && !strings.HasPrefix(path, "/api/content/") {
+			continue
+		}
+		for method, op := range item.(map[string]any) {
+			operation, ok := op.(map[string]any)
+			if !ok || (!models && method == "get") {
+				continue
+			}
+			operation["security"] = adminSecurity
+			responses := operation["responses"].(map[string]any)
+			responses["401"] = jsonResponse("Missing or invalid admin session or API token", ref("Error"))
+			responses["403"] = jsonResponse("The role of the user or the scopes of the API token do not allow the request", ref("Error"))
+		}
+	}
+}
+
+func addTokensPaths(paths map[string]any) {
+	tags := []string{"tokens"}
+
+	paths["/api/tokens"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "listTokens",
+			"tags":        tags,
+			"summary":     "List the API tokens",
+			"responses": map[string]any{
+				"200": jsonResponse("The tokens", map[string]any{"type": "array", "items": ref("APIToken")}),
+			},
+		},
+		"post": map[string]any{
+			"operationId": "createToken",
+			"tags":        tags,
+			"summary":     "Create an API token",
+			"description": "The value of the token is only returned by this request. API tokens may only grant the scopes they hold.",
+			"requestBody": jsonBody(ref("CreateTokenRequest")),
+			"responses": withErrors(map[string]any{
+				"201": jsonResponse("The created token", ref("CreatedAPIToken")),
+			}, http.StatusBadRequest),
+		},
+	}
+	paths["/api/tokens/{id}"] = map[string]any{
+		"parameters": []any{pathParam("id", "ID or prefix of the token.")},
+		"delete": map[string]any{
+			"operationId": "revokeToken",
+			"tags":        tags,
+			"summary":     "Revoke an API token",
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The token was revoked", map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"deleted": map[string]any{"type": "boolean"}},
+				}),
+			}, http.StatusNotFound),
+		},
+	}
+}
+
+func addEventsPaths(paths map[string]any) {
+	paths["/api/events"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "streamEvents",
+			"tags":        []string{"events"},
+			"summary":     "Stream the changes of models and entries",
+			"description": "Server-sent events named after the type of the change, with its number in the change log as ID and a WebhookEvent as data. " +
+				"Resume a stream with the Last-Event-ID header or the lastEventId parameter; a reset event is sent first when the changes after it are no longer logged. " +
+				"The stream includes the changes of drafts, so it requires the preview token or a session or API token allowed to preview the models.",
+			"security": []any{map[string]any{"previewToken": []string{}}, map[string]any{"adminSession": []string{}}, map[string]any{"apiToken": []string{}}},
+			"parameters": []any{
+				queryParam("model", "Only stream the changes of these models, separated by commas.", map[string]any{"type": "string"}),
+				queryParam("lastEventId", "ID of the last event received, for clients that cannot send Last-Event-ID.", map[string]any{"type": "integer", "minimum": 0}),
+			},
+			"responses": withErrors(map[string]any{
+				"200": map[string]any{
+					"description": "The event stream",
+					"content":     map[string]any{"text/event-stream": map[string]any{"schema": map[string]any{"type": "string"}}},
+				},
+			}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden),
+		},
+	}
+}
+
+func addChangesPaths(paths map[string]any) {
+	paths["/api/changes"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "listChanges",
+			"tags":        []string{"events"},
+			"summary":     "Read the change log from an offset",
+			"description": "Every write of models and entries is recorded in the change log in the same transaction. " +
+				"Consumers read the changes after the last offset they processed, then store the next offset of the response. " +
+				"The changes the caller may not see are skipped but still move the offset. Requires what /api/events does.",
+			"security": []any{map[string]any{"previewToken": []string{}}, map[string]any{"adminSession": []string{}}, map[string]any{"apiToken": []string{}}},
+			"parameters": []any{
+				queryParam("after", "Offset to read the changes after, 0 for the oldest change logged.", map[string]any{"type": "integer", "minimum": 0, "default": 0}),
+				queryParam("limit", "Maximum number of changes read.", map[string]any{"type": "integer", "minimum": 0, "maximum": maxChangeLimit, "default": defaultChangeLimit}),
+				queryParam("model", "Only return the changes of these models, separated by commas.", map[string]any{"type": "string"}),
+			},
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The changes", ref("ChangePage")),
+			}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden),
+		},
+	}
+}
+
+func addWebhooksPaths(paths map[string]any) {
+	tags := []string{"webhooks"}
+	id := pathParam("id", "ID of the delivery.")
+
+	paths["/api/webhooks"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "listWebhooks",
+			"tags":        tags,
+			"summary":     "List the webhooks",
+			"description": "Webhooks are defined in vectrag.config.yaml. Their secrets are not returned.",
+			"responses": map[string]any{
+				"200": jsonResponse("The webhooks", map[string]any{"type": "array", "items": ref("Webhook")}),
+			},
+		},
+	}
+	paths["/api/webhooks/deliveries"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "listWebhookDeliveries",
+			"tags":        tags,
+			"summary":     "List webhook deliveries, newest first",
+			"parameters": []any{
+				queryParam("webhook", "Only list the deliveries of this webhook.", map[string]any{"type": "string"}),
+				queryParam("status", "Only list the deliveries with this status.",
+					map[string]any{"type": "string", "enum": []string{string(domain.DeliveryPending), string(domain.DeliverySucceeded), string(domain.DeliveryFailed)}}),
+				queryParam("limit", "Maximum number of deliveries.", map[string]any{"type": "integer", "minimum": 0, "default": defaultDeliveryLimit}),
+			},
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The deliveries", map[string]any{"type": "array", "items": ref("WebhookDelivery")}),
+			}, http.StatusBadRequest),
+		},
+	}
+	paths["/api/webhooks/deliveries/{id}"] = map[string]any{
+		"parameters": []any{id},
+		"get": map[string]any{
+			"operationId": "getWebhookDelivery",
+			"tags":        tags,
+			"summary":     "Get a webhook delivery",
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The delivery", ref("WebhookDelivery")),
+			}, http.StatusNotFound),
+		},
+	}
+	paths["/api/webhooks/deliveries/{id}/retry"] = map[string]any{
+		"parameters": []any{id},
+		"post": map[string]any{
+			"operationId": "retryWebhookDelivery",
+			"tags":        tags,
+			"summary":     "Send a webhook delivery again",
+			"description": "The delivery becomes pending and is sent as soon as possible, with a new set of attempts.",
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The pending delivery", ref("WebhookDelivery")),
+			}, http.StatusNotFound),
+		},
+	}
+}
+
+func addModelsPaths(paths map[string]any) {
+	slug := pathParam("slug", "Slug of the model.")
+	tags := []string{"models"}
+
+	paths["/api/models"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "listModels",
+			"tags":        tags,
+			"summary":     "List the models",
+			"responses": map[string]any{
+				"200": jsonResponse("The models", map[string]any{"type": "array", "items": ref("Model")}),
+			},
+		},
+		"post": map[string]any{
+			"operationId": "createModel",
+			"tags":        tags,
+			"summary":     "Create a model",
+			"requestBody": jsonBody(ref("CreateModelRequest")),
+			"responses": withErrors(map[string]any{
+				"201": jsonResponse("The created model", ref("Model")),
+			}, http.StatusBadRequest, http.StatusConflict),
+		},
+	}
+	paths["/api/models/{slug}"] = map[string]any{
+		"parameters": []any{slug},
+		"get": map[string]any{
+			"operationId": "getModel",
+			"tags":        tags,
+			"summary":     "Get a model",
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The model", ref("Model")),
+			}, http.StatusNotFound),
+		},
+		"put": map[string]any{
+			"operationId": "updateModel",
+			"tags":        tags,
+			"summary":     "Update a model",
+			"description": "Fields missing from the request are moved to the trash. Stored values of fields whose type changes are converted; changes that are not safe are blocked with 409 unless forced with a fallback value.",
+			"requestBody": jsonBody(ref("UpdateModelRequest")),
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The updated model, or the conversion report of a dry run", map[string]any{"oneOf": []any{ref("Model"), ref("TypeChangeReport")}}),
+				"409": jsonResponse("The update is blocked by unsafe type changes", ref("TypeChangeReport")),
+			}, http.StatusBadRequest, http.StatusNotFound),
+		},
+		"delete": map[string]any{
+			"operationId": "deleteModel",
+			"tags":        tags,
+			"summary":     "Move a model to the trash",
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The model was deleted", map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"deleted": map[string]any{"type": "boolean"}},
+				}),
+			}, http.StatusNotFound),
+		},
+	}
+	paths["/api/models/{slug}/rename"] = map[string]any{
+		"parameters": []any{slug},
+		"post": map[string]any{
+			"operationId": "renameModel",
+			"tags":        tags,
+			"summary":     "Change the name and slug of a model",
+			"description": "Entries and relation fields targeting the model follow the new slug. Requests using the former slug are redirected with 308 until models.aliasDays elapse.",
+			"requestBody": jsonBody(ref("RenameModelRequest")),
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The renamed model", ref("Model")),
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+		},
+	}
+	paths["/api/field-types"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "listFieldTypes",
+			"tags":        tags,
+			"summary":     "List the field types, built-in and registered",
+			"responses": map[string]any{
+				"200": jsonResponse("The field types", map[string]any{"type": "array", "items": ref("FieldType")}),
+			},
+		},
+	}
+	paths["/api/migrations"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "listMigrations",
+			"tags":        tags,
+			"summary":     "List the migration history, oldest first",
+			"responses": map[string]any{
+				"200": jsonResponse("The migrations", map[string]any{"type": "array", "items": ref("Migration")}),
+			},
+		},
+	}
+}
+
+func addCollectionPaths(paths map[string]any, m domain.Model, name string) {
+	base := "/api/content/" + m.Slug
+	tags := []string{m.Slug}
+	entry := ref(name + "DraftEntry")
+	read := readEntrySchema(name)
+	id := pathParam("id", "ID of the entry.")
+
+	paths[base] = map[string]any{
+		"get": map[string]any{
+			"operationId": "list" + name,
+			"tags":        tags,
+			"summary":     fmt.Sprintf("List %s entries", m.Name),
+			"security":    previewSecurity,
+			"parameters": []any{
+				statusParam,
+				map[string]any{
+					"name":        "filter",
+					"in":          "query",
+					"style":       "deepObject",
+					"explode":     true,
+					"description": "Conditions as filter[field][operator]=value. Operators: eq, ne, lt, gt, in, contains, startsWith, null. Relation sub-fields are addressed as relation.field, groups as filter[or][0][field][operator].",
+					"schema":      map[string]any{"type": "object"},
+				},
+				queryParam("sort", "Comma separated fields, prefixed with - for descending order.", map[string]any{"type": "string"}),
+				queryParam("fields", "Comma separated fields to return.", map[string]any{"type": "string"}),
+				queryParam("limit", "Maximum number of entries.", map[string]any{"type": "integer", "minimum": 0}),
+				queryParam("offset", "Number of entries to skip.", map[string]any{"type": "integer", "minimum": 0}),
+				queryParam("after", "Cursor returned as meta.nextCursor.", map[string]any{"type": "string"}),
+			},
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("A page of entries", map[string]any{
+					"type":     "object",
+					"required": []string{"data", "meta"},
+					"properties": map[string]any{
+						"data": map[string]any{"type": "array", "items": read},
+						"meta": ref("ListMeta"),
+					},
+				}),
+			}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden),
+		},
+		"post": map[string]any{
+			"operationId": "create" + name,
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Create a %s draft", m.Name),
+			"requestBody": jsonBody(ref(name + "DraftData")),
+			"responses": withErrors(map[string]any{
+				"201": jsonResponse("The created entry", entry),
+			}, http.StatusBadRequest),
+		},
+	}
+
+	paths[base+"/aggregate"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "aggregate" + name,
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Aggregate %s entries", m.Name),
+			"description": "Accepts the filter parameters of the list operation. Entries are always counted.",
+			"security":    previewSecurity,
+			"parameters": []any{
+				statusParam,
+				queryParam("groupBy", "Comma separated fields to group entries by.", map[string]any{"type": "string"}),
+				queryParam("sum", "Comma separated fields with number values to sum.", map[string]any{"type": "string"}),
+				queryParam("avg", "Comma separated fields with number values to average.", map[string]any{"type": "string"}),
+				queryParam("min", "Comma separated fields to get the minimum of.", map[string]any{"type": "string"}),
+				queryParam("max", "Comma separated fields to get the maximum of.", map[string]any{"type": "string"}),
+			},
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The groups", map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"data": map[string]any{"type": "array", "items": ref("AggregateGroup")}},
+				}),
+			}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden),
+		},
+	}
+
+	paths[base+"/{id}"] = map[string]any{
+		"parameters": []any{id},
+		"get": map[string]any{
+			"operationId": "get" + name,
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Get a %s entry", m.Name),
+			"security":    previewSecurity,
+			"parameters":  []any{statusParam},
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The entry", read),
+			}, http.StatusUnauthorized, http.StatusNotFound),
+		},
+		"put": map[string]any{
+			"operationId": "update" + name,
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Replace the draft of a %s entry", m.Name),
+			"requestBody": jsonBody(ref(name + "DraftData")),
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The updated entry", entry),
+			}, http.StatusBadRequest, http.StatusNotFound),
+		},
+		"delete": map[string]any{
+			"operationId": "delete" + name,
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Move a %s entry to the trash", m.Name),
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The entry was deleted", map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"deleted": map[string]any{"type": "boolean"}},
+				}),
+			}, http.StatusNotFound),
+		},
+	}
+
+	addLifecyclePaths(paths, base+"/{id}", []any{id}, m, name)
+}
+
+func addSinglePaths(paths map[string]any, m domain.Model, name string) {
+	base := "/api/content/" + m.Slug
+	tags := []string{m.Slug}
+	entry := ref(name + "DraftEntry")
+
+	paths[base] = map[string]any{
+		"get": map[string]any{
+			"operationId": "get" + name,
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Get the %s entry", m.Name),
+			"security":    previewSecurity,
+			"parameters":  []any{statusParam},
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The entry", readEntrySchema(name)),
+			}, http.StatusUnauthorized, http.StatusNotFound),
+		},
+		"put": map[string]any{
+			"operationId": "put" + name,
+			"tags":        tags,
+			"summary":     fmt.Sprintf("Create or replace the %s draft", m.Name),
+			"requestBody": jsonBody(ref(name + "DraftData")),
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The entry", entry),
+			}, http.StatusBadRequest),
+		},
+	}
+
+	addLifecyclePaths(paths, base, nil, m, name)
+}
+
+func addLifecyclePaths(paths map[string]any, base string, params []any, m domain.Model, name string) {
+	tags := []string{m.Slug}
+	entry := ref(name + "DraftEntry")
+
+	operation := func(id, summary string, body map[string]any) map[string]any {
+		op := map[string]any{
+			"operationId": id,
+			"tags":        tags,
+			"summary":     summary,
+			"responses": withErrors(map[string]any{
+				"200": jsonResponse("The entry", entry),
+			}, http.StatusBadRequest, http.StatusNotFound),
+		}
+		if body != nil {
+			op["requestBody"] = body
+		}
+		item := map[string]any{"post": op}
+		if params != nil {
+			item["parameters"] = params
+		}
+		return item
+	}
+
+	paths[base+"/publish"] = operation("publish"+name, fmt.Sprintf("Publish the draft of a %s entry", m.Name), nil)
+	paths[base+"/unpublish"] = operation("unpublish"+name, fmt.Sprintf("Unpublish a %s entry", m.Name), nil)
+	paths[base+"/schedule"] = operation("schedule"+name, fmt.Sprintf("Schedule the publication of a %s entry", m.Name), jsonBody(ref("ScheduleRequest")))
+}
@@ -0,0 +1,357 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+	"github.com/axarus/vectrag/internal/infrastructure/filestore"
+)
+
+// Project wires the repositories and services of a VectraG project. The APIs
+// and background workers of a server share a single Project so they operate
+// on the same repositories.
+type Project struct {
+	root       string
+	config     application.ProjectConfig
+	modelsDir  string
+	modelSvc   *application.ModelService
+	contentSvc *application.ContentService
+	trashSvc   *application.TrashService
+	scheduler  *application.Scheduler
+	purger     *application.TrashPurger
+	migrations *application.MigrationService
+	events     *application.EventBus
+	webhooks   *application.WebhookDispatcher
+	changes    *application.ChangeFeed
+	hooks      *application.Hooks
+	outbox     *application.Outbox
+	auth       *application.AuthService
+	tokens     *application.TokenService
+	roles      *application.RoleService
+	extAuth    ExternalAuth
+
+	// tx serializes the writes of models, content, tokens, users and
+	// sessions across APIs and workers, and with the vectrag commands writing
+	// to the project. The writes of a transaction, along with the change log
+	// recording them, are committed together. Repositories sharing the
+	// journal are only used inside its transactions.
+	tx *filestore.TxLock
+}
+
+// ProjectOptions customizes a Project for the programs embedding it. The zero
+// ProjectOptions loads the project as vectrag develop does.
+type ProjectOptions struct {
+	// Models and Content replace the repositories storing models and
+	// entries in the files of the project. They do not share its journal:
+	// their writes are not rolled back with the transactions of the project.
+	Models  domain.Repository
+	Content domain.ContentRepository
+	// Hooks are the entry hooks run by the content service.
+	Hooks *application.Hooks
+	// Publishers are added to the publishers of the outbox configured in
+	// vectrag.config.yaml.
+	Publishers []application.ChangePublisher
+	// Auth identifies callers before admin sessions and API tokens do.
+	Auth ExternalAuth
+}
+
+func LoadProject(projectRoot string) (*Project, error) {
+	return LoadProjectWith(projectRoot, ProjectOptions{})
+}
+
+// LoadProjectWith loads the project in projectRoot, customized by opts.
+func LoadProjectWith(projectRoot string, opts ProjectOptions) (*Project, error) {
+	cfg, err := application.LoadProjectConfig(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	modelsDir, err := application.ResolveModelsDir(projectRoot, cfg)
+	if err != nil {
+		return nil, err
+	}
+	contentDir, err := application.ResolveContentDir(projectRoot, cfg)
+	if err != nil {
+		return nil, err
+	}
+	stateDir := application.ResolveStateDir(projectRoot)
+	journal, err := filestore.NewJournal(application.ResolveJournalFile(projectRoot))
+	if err != nil {
+		return nil, err
+	}
+
+	var repo domain.Repository = opts.Models
+	if repo == nil {
+		if repo, err = filestore.NewYamlRepository(modelsDir, journal); err != nil {
+			return nil, err
+		}
+	}
+	var contentRepo domain.ContentRepository = opts.Content
+	if contentRepo == nil {
+		if contentRepo, err = filestore.NewJSONContentRepository(contentDir, journal); err != nil {
+			return nil, err
+		}
+	}
+	scheduleRepo, err := filestore.NewJSONScheduleRepository(filepath.Join(stateDir, "schedule.json"), journal)
+	if err != nil {
+		return nil, err
+	}
+	aliasRepo, err := filestore.NewJSONAliasRepository(filepath.Join(stateDir, "aliases.json"), journal)
+	if err != nil {
+		return nil, err
+	}
+	migrationRepo, err := filestore.NewJSONMigrationRepository(filepath.Join(stateDir, "migrations.json"), journal)
+	if err != nil {
+		return nil, err
+	}
+	userRepo, err := filestore.NewJSONUserRepository(filepath.Join(stateDir, "users.json"))
+	if err != nil {
+		return nil, err
+	}
+	sessionRepo, err := filestore.NewJSONSessionRepository(filepath.Join(stateDir, "sessions.json"))
+	if err != nil {
+		return nil, err
+	}
+	tokenRepo, err := filestore.NewJSONTokenRepository(filepath.Join(stateDir, "tokens.json"), journal)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := filestore.LoadOrCreateSecret(filepath.Join(stateDir, "secret"))
+	if err != nil {
+		return nil, err
+	}
+	deliveryRepo, err := filestore.NewJSONDeliveryRepository(filepath.Join(stateDir, "webhook_deliveries.json"), journal)
+	if err != nil {
+		return nil, err
+	}
+	changeRepo, err := filestore.NewJSONChangeRepository(filepath.Join(stateDir, "changes.json"), journal)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := application.NewRoleService(cfg.Roles)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := application.WebhooksFromConfig(cfg.Webhooks)
+	if err != nil {
+		return nil, err
+	}
+	publishers, err := changePublishersFromConfig(projectRoot, cfg.Outbox)
+	if err != nil {
+		return nil, err
+	}
+	publishers = append(publishers, opts.Publishers...)
+	lock, err := filestore.NewFileLock(application.ResolveLockFile(projectRoot))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Project{
+		root:      projectRoot,
+		config:    cfg,
+		modelsDir: modelsDir,
+		events:    application.NewEventBus(),
+		hooks:     opts.Hooks,
+		extAuth:   opts.Auth,
+		roles:     roles,
+		tx:        filestore.NewTxLock(lock, journal),
+	}
+	if p.hooks == nil {
+		p.hooks = application.NewHooks()
+	}
+	p.modelSvc = application.NewModelService(repo, p.events)
+	p.contentSvc = application.NewContentService(repo, contentRepo, p.events, p.hooks, p.tx)
+	p.trashSvc = application.NewTrashService(repo, contentRepo, aliasRepo, scheduleRepo, p.events)
+	p.scheduler = application.NewScheduler(p.contentSvc, scheduleRepo, p.events, p.tx)
+	retention := time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour
+	p.purger = application.NewTrashPurger(p.trashSvc, retention, p.tx)
+	aliasTTL := time.Duration(cfg.Models.AliasDays) * 24 * time.Hour
+	p.migrations = application.NewMigrationService(repo, contentRepo, scheduleRepo, aliasRepo, migrationRepo, tokenRepo, aliasTTL, p.events)
+	sessionTTL := time.Duration(cfg.Auth.SessionDays) * 24 * time.Hour
+	p.auth = application.NewAuthService(userRepo, sessionRepo, roles, secret, sessionTTL)
+	p.tokens = application.NewTokenService(tokenRepo)
+
+	p.webhooks = application.NewWebhookDispatcher(hooks, deliveryRepo, NewWebhookSender(), p.tx)
+	p.changes = application.NewChangeFeed(changeRepo, cfg.Events.LogSize, p.tx)
+	p.outbox = application.NewOutbox(changeRepo, publishers, p.tx)
+
+	p.events.Subscribe(p.changes.Publish)
+	p.events.Subscribe(p.webhooks.Publish)
+	p.events.Subscribe(p.outbox.Publish)
+
+	return p, nil
+}
+
+// changePublishersFromConfig creates the publishers of the outbox, sorted by
+// name.
+func changePublishersFromConfig(projectRoot string, cfg application.ProjectOutbox) ([]application.ChangePublisher, error) {
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	publishers := make([]application.ChangePublisher, 0, len(names))
+	for _, name := range names {
+		pc := cfg[name]
+		switch pc.Type {
+		case "file":
+			path, err := application.ResolvePublisherPath(projectRoot, pc)
+			if err != nil {
+				return nil, fmt.Errorf("outbox publisher %s: %w", name, err)
+			}
+			publisher, err := filestore.NewFileChangePublisher(name, path)
+			if err != nil {
+				return nil, fmt.Errorf("outbox publisher %s: %w", name, err)
+			}
+			publishers = append(publishers, publisher)
+		default:
+			return nil, fmt.Errorf("outbox publisher %s: unknown type %q, expected file", name, pc.Type)
+		}
+	}
+	return publishers, nil
+}
+
+// Register registers the API routes of the project on mux.
+func (p *Project) Register(mux *http.ServeMux) {
+	NewAuthAPI(p).Register(mux)
+	NewTokensAPI(p).Register(mux)
+	NewWebhooksAPI(p).Register(mux)
+	NewEventsAPI(p).Register(mux)
+	NewChangesAPI(p).Register(mux)
+	NewModelsAPI(p).Register(mux)
+	NewContentAPI(p).Register(mux)
+	NewTrashAPI(p).Register(mux)
+	NewGraphQLAPI(p).Register(mux)
+	NewOpenAPIAPI(p).Register(mux)
+}
+
+// Workers returns the background workers of the project, to run while its
+// API is served.
+func (p *Project) Workers() []application.BackgroundWorker {
+	return []application.BackgroundWorker{p.scheduler, p.purger, p.webhooks, p.changes, p.outbox}
+}
+
+// RolePolicy returns the policy of the callers given a role of the project,
+// creating entries as owner.
+func (p *Project) RolePolicy(role, owner string) (domain.Policy, error) {
+	r, err := p.roles.Role(role)
+	if err != nil {
+		return domain.Policy{}, err
+	}
+	return domain.Policy{Owner: owner, Permissions: r.Permissions}, nil
+}
+
+// Hooks returns the entry hooks of the project, run by its content service.
+func (p *Project) Hooks() *application.Hooks {
+	return p.hooks
+}
+
+// Changes returns a page of the change log, see
+// application.ChangeFeed.Changes.
+func (p *Project) Changes(after int64, limit int) (application.ChangePage, error) {
+	return p.changes.Changes(after, limit)
+}
+
+// RenameModel changes the name and slug of a model, see
+// application.MigrationService.RenameModel. It warns about the settings of
+// vectrag.config.yaml still naming the former slug, to be changed by hand.
+func (p *Project) RenameModel(slug, name, newSlug string) (domain.Model, []string, error) {
+	var model domain.Model
+	err := p.tx.Tx(func() error {
+		var err error
+		model, err = p.migrations.RenameModel(slug, name, newSlug)
+		return err
+	})
+	if err != nil || model.Slug == slug {
+		return model, nil, err
+	}
+
+	var warnings []string
+	for _, ref := range p.config.ModelReferences(slug) {
+		warnings = append(warnings, fmt.Sprintf("%s in vectrag.config.yaml still names %s, change it to %s", ref, slug, model.Slug))
+	}
+	return model, warnings, nil
+}
+
+// Migrations returns the migration history of the project.
+func (p *Project) Migrations() ([]domain.Migration, error) {
+	var migrations []domain.Migration
+	err := p.tx.Tx(func() error {
+		var err error
+		migrations, err = p.migrations.History()
+		return err
+	})
+	return migrations, err
+}
+
+// CreateUser adds an admin user to the project, see
+// application.AuthService.CreateUser.
+func (p *Project) CreateUser(username, password, role string) (domain.AdminUser, error) {
+	var user domain.AdminUser
+	err := p.tx.Tx(func() error {
+		var err error
+		user, err = p.auth.CreateUser(username, password, role)
+		return err
+	})
+	return user, err
+}
+
+// SetUserRole changes the role of an admin user.
+func (p *Project) SetUserRole(username, role string) (domain.AdminUser, error) {
+	var user domain.AdminUser
+	err := p.tx.Tx(func() error {
+		var err error
+		user, err = p.auth.SetRole(username, role)
+		return err
+	})
+	return user, err
+}
+
+// Roles returns the roles of the project, including the built-in ones.
+func (p *Project) Roles() []domain.Role {
+	return p.roles.Roles()
+}
+
+// Users returns the admin users of the project.
+func (p *Project) Users() ([]domain.AdminUser, error) {
+	return p.auth.ListUsers()
+}
+
+// CreateToken creates an API token, see application.TokenService.CreateToken.
+func (p *Project) CreateToken(name string, scopes []domain.Scope, expiresAt time.Time) (domain.APIToken, string, error) {
+	var token domain.APIToken
+	var secret string
+	err := p.tx.Tx(func() error {
+		var err error
+		token, secret, err = p.tokens.CreateToken(name, scopes, expiresAt)
+		return err
+	})
+	return token, secret, err
+}
+
+// RevokeToken deletes the API token with the given ID or prefix.
+func (p *Project) RevokeToken(idOrPrefix string) (domain.APIToken, error) {
+	var token domain.APIToken
+	err := p.tx.Tx(func() error {
+		var err error
+		token, err = p.tokens.RevokeToken(idOrPrefix)
+		return err
+	})
+	return token, err
+}
+
+// Tokens returns the API tokens of the project.
+func (p *Project) Tokens() ([]domain.APIToken, error) {
+	var tokens []domain.APIToken
+	err := p.tx.Tx(func() error {
+		var err error
+		tokens, err = p.tokens.ListTokens()
+		return err
+	})
+	return tokens, err
+}
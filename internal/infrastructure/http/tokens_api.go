@@ -0,0 +1,167 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// TokensAPI creates, lists and revokes the API tokens of machine clients:
+//
+//	GET    /api/tokens
+//	POST   /api/tokens
+//	DELETE /api/tokens/{id}
+type TokensAPI struct {
+	tx         application.Transactor
+	tokens     *application.TokenService
+	auth       *Authenticator
+	enableCORS bool
+}
+
+// CreateTokenRequest creates a token allowed the given scopes, written
+// action:model, or named presets such as read-only and full-access.
+type CreateTokenRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type tokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// createdTokenResponse holds the value of a new token, only returned once.
+type createdTokenResponse struct {
+	tokenResponse
+	Token string `json:"token"`
+}
+
+func NewTokensAPI(p *Project) *TokensAPI {
+	return &TokensAPI{
+		tx:         p.tx,
+		tokens:     p.tokens,
+		auth:       NewAuthenticator(p),
+		enableCORS: p.config.Development.EnableCORS,
+	}
+}
+
+func (api *TokensAPI) Register(mux *http.ServeMux) {
+	h := api.auth.Require(adminPermission, api)
+	mux.Handle("/api/tokens", h)
+	mux.Handle("/api/tokens/", h)
+}
+
+func (api *TokensAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if api.enableCORS && writeCORS(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/tokens"), "/")
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		api.handleList(w)
+	case id == "" && r.Method == http.MethodPost:
+		api.handleCreate(w, r)
+	case id != "" && !strings.Contains(id, "/") && r.Method == http.MethodDelete:
+		api.handleRevoke(w, id)
+	case id == "" || !strings.Contains(id, "/"):
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (api *TokensAPI) handleList(w http.ResponseWriter) {
+	var tokens []domain.APIToken
+	err := api.tx.Tx(func() error {
+		var err error
+		tokens, err = api.tokens.ListTokens()
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := make([]tokenResponse, len(tokens))
+	for i, t := range tokens {
+		resp[i] = newTokenResponse(t)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (api *TokensAPI) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req CreateTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	scopes, err := application.ParseScopes(req.Scopes)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	// Callers cannot create tokens with more access than their own.
+	if c := callerFrom(r.Context()); !c.open {
+		for _, s := range scopes {
+			if !c.policy.Grants(s) {
+				writeError(w, http.StatusForbidden, fmt.Sprintf("cannot grant the %s scope, it is not fully allowed to the caller", s))
+				return
+			}
+		}
+	}
+
+	var token domain.APIToken
+	var secret string
+	err = api.tx.Tx(func() error {
+		var err error
+		token, secret, err = api.tokens.CreateToken(req.Name, scopes, timeValue(req.ExpiresAt))
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, createdTokenResponse{tokenResponse: newTokenResponse(token), Token: secret})
+}
+
+func (api *TokensAPI) handleRevoke(w http.ResponseWriter, id string) {
+	err := api.tx.Tx(func() error {
+		_, err := api.tokens.RevokeToken(id)
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"deleted": true})
+}
+
+func newTokenResponse(t domain.APIToken) tokenResponse {
+	resp := tokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     make([]string, len(t.Scopes)),
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  timePtr(t.ExpiresAt),
+		LastUsedAt: timePtr(t.LastUsedAt),
+	}
+	for i, s := range t.Scopes {
+		resp.Scopes[i] = s.String()
+	}
+	return resp
+}
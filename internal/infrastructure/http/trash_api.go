@@ -0,0 +1,160 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// TrashAPI lists, restores and purges deleted models, fields and entries:
+//
+//	GET    /api/trash/models
+//	POST   /api/trash/models/{slug}/restore
+//	DELETE /api/trash/models/{slug}
+//	GET    /api/trash/fields
+//	POST   /api/trash/fields/{slug}/{fieldID}/restore
+//	DELETE /api/trash/fields/{slug}/{fieldID}
+//	GET    /api/trash/entries/{slug}
+//	POST   /api/trash/entries/{slug}/{id}/restore
+//	DELETE /api/trash/entries/{slug}/{id}
+type TrashAPI struct {
+	tx         application.Transactor
+	trashSvc   *application.TrashService
+	auth       *Authenticator
+	enableCORS bool
+}
+
+type trashedFieldResponse struct {
+	Model string       `json:"model"`
+	Field domain.Field `json:"field"`
+}
+
+type trashedEntryResponse struct {
+	entryResponse
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+func NewTrashAPI(p *Project) *TrashAPI {
+	return &TrashAPI{
+		tx:         p.tx,
+		trashSvc:   p.trashSvc,
+		auth:       NewAuthenticator(p),
+		enableCORS: p.config.Development.EnableCORS,
+	}
+}
+
+func (api *TrashAPI) Register(mux *http.ServeMux) {
+	mux.Handle("/api/trash/", api.auth.Require(trashPermission, api))
+}
+
+func (api *TrashAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if api.enableCORS && writeCORS(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/trash/"), "/")
+	parts := strings.Split(path, "/")
+	restore := len(parts) > 1 && parts[len(parts)-1] == "restore"
+	if restore {
+		parts = parts[:len(parts)-1]
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+	}
+
+	handle := api.route(r.Method, parts, restore)
+	if handle == nil {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	var resp any
+	err := api.tx.Tx(func() error {
+		var err error
+		resp, err = handle()
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// route returns the function handling a request for parts of the path, run
+// in a transaction, or nil when there is none.
+func (api *TrashAPI) route(method string, parts []string, restore bool) func() (any, error) {
+	switch {
+	case parts[0] == "models" && len(parts) == 1 && method == http.MethodGet:
+		return func() (any, error) {
+			return api.trashSvc.ListModels()
+		}
+	case parts[0] == "models" && len(parts) == 2 && restore:
+		return func() (any, error) {
+			return api.trashSvc.RestoreModel(parts[1])
+		}
+	case parts[0] == "models" && len(parts) == 2 && method == http.MethodDelete:
+		return func() (any, error) {
+			return purged(api.trashSvc.PurgeModel(parts[1]))
+		}
+
+	case parts[0] == "fields" && len(parts) == 1 && method == http.MethodGet:
+		return func() (any, error) {
+			fields, err := api.trashSvc.ListFields()
+			if err != nil {
+				return nil, err
+			}
+			resp := make([]trashedFieldResponse, len(fields))
+			for i, f := range fields {
+				resp[i] = trashedFieldResponse{Model: f.Model, Field: f.Field}
+			}
+			return resp, nil
+		}
+	case parts[0] == "fields" && len(parts) == 3 && restore:
+		return func() (any, error) {
+			return api.trashSvc.RestoreField(parts[1], parts[2])
+		}
+	case parts[0] == "fields" && len(parts) == 3 && method == http.MethodDelete:
+		return func() (any, error) {
+			return purged(api.trashSvc.PurgeField(parts[1], parts[2]))
+		}
+
+	case parts[0] == "entries" && len(parts) == 2 && method == http.MethodGet:
+		return func() (any, error) {
+			entries, err := api.trashSvc.ListEntries(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			resp := make([]trashedEntryResponse, len(entries))
+			for i, e := range entries {
+				resp[i] = trashedEntryResponse{entryResponse: newEntryResponse(e), DeletedAt: e.DeletedAt}
+			}
+			return resp, nil
+		}
+	case parts[0] == "entries" && len(parts) == 3 && restore:
+		return func() (any, error) {
+			entry, err := api.trashSvc.RestoreEntry(parts[1], parts[2])
+			if err != nil {
+				return nil, err
+			}
+			return newEntryResponse(entry), nil
+		}
+	case parts[0] == "entries" && len(parts) == 3 && method == http.MethodDelete:
+		return func() (any, error) {
+			return purged(api.trashSvc.PurgeEntry(parts[1], parts[2]))
+		}
+	}
+	return nil
+}
+
+func purged(err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"purged": true}, nil
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+)
+
+// defaultDeliveryLimit is how many deliveries are listed when no limit is
+// given.
+const defaultDeliveryLimit = 50
+
+// WebhooksAPI lists the webhooks of the project and their delivery log:
+//
+//	GET  /api/webhooks
+//	GET  /api/webhooks/deliveries?webhook=&status=&limit=
+//	GET  /api/webhooks/deliveries/{id}
+//	POST /api/webhooks/deliveries/{id}/retry
+type WebhooksAPI struct {
+	tx         application.Transactor
+	webhooks   *application.WebhookDispatcher
+	auth       *Authenticator
+	enableCORS bool
+}
+
+// webhookResponse describes a webhook without its secret.
+type webhookResponse struct {
+	Name   string             `json:"name"`
+	URL    string             `json:"url"`
+	Events []domain.EventType `json:"events"`
+	Models []string           `json:"models,omitempty"`
+	Signed bool               `json:"signed"`
+}
+
+type deliveryResponse struct {
+	ID             string                `json:"id"`
+	Webhook        string                `json:"webhook"`
+	Event          eventResponse         `json:"event"`
+	Status         domain.DeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  *time.Time            `json:"lastAttemptAt,omitempty"`
+	ResponseStatus int                   `json:"responseStatus,omitempty"`
+	LastError      string                `json:"lastError,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt"`
+}
+
+func NewWebhooksAPI(p *Project) *WebhooksAPI {
+	return &WebhooksAPI{
+		tx:         p.tx,
+		webhooks:   p.webhooks,
+		auth:       NewAuthenticator(p),
+		enableCORS: p.config.Development.EnableCORS,
+	}
+}
+
+func (api *WebhooksAPI) Register(mux *http.ServeMux) {
+	h := api.auth.Require(adminPermission, api)
+	mux.Handle("/api/webhooks", h)
+	mux.Handle("/api/webhooks/", h)
+}
+
+func (api *WebhooksAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if api.enableCORS && writeCORS(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/webhooks"), "/")
+	parts := strings.Split(path, "/")
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		api.handleList(w)
+	case parts[0] == "deliveries" && len(parts) == 1 && r.Method == http.MethodGet:
+		api.handleDeliveries(w, r)
+	case parts[0] == "deliveries" && len(parts) == 2 && r.Method == http.MethodGet:
+		api.handleDelivery(w, parts[1])
+	case parts[0] == "deliveries" && len(parts) == 3 && parts[2] == "retry" && r.Method == http.MethodPost:
+		api.handleRetry(w, parts[1])
+	case path == "" || (parts[0] == "deliveries" && len(parts) <= 2) || (len(parts) == 3 && parts[2] == "retry"):
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (api *WebhooksAPI) handleList(w http.ResponseWriter) {
+	hooks := api.webhooks.Webhooks()
+	resp := make([]webhookResponse, len(hooks))
+	for i, h := range hooks {
+		resp[i] = webhookResponse{Name: h.Name, URL: h.URL, Events: h.Events, Models: h.Models, Signed: h.Secret != ""}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (api *WebhooksAPI) handleDeliveries(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	filter := domain.DeliveryFilter{
+		Webhook: values.Get("webhook"),
+		Status:  domain.DeliveryStatus(values.Get("status")),
+	}
+	switch filter.Status {
+	case "", domain.DeliveryPending, domain.DeliverySucceeded, domain.DeliveryFailed:
+	default:
+		writeError(w, http.StatusBadRequest, "status must be pending, succeeded or failed")
+		return
+	}
+	limit, err := intParam(values, "limit")
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if limit == 0 {
+		limit = defaultDeliveryLimit
+	}
+
+	var deliveries []domain.WebhookDelivery
+	err = api.tx.Tx(func() error {
+		var err error
+		deliveries, err = api.webhooks.Deliveries(filter, limit)
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	resp := make([]deliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		resp[i] = newDeliveryResponse(d)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (api *WebhooksAPI) handleDelivery(w http.ResponseWriter, id string) {
+	var delivery domain.WebhookDelivery
+	err := api.tx.Tx(func() error {
+		var err error
+		delivery, err = api.webhooks.Delivery(id)
+		return err
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newDeliveryResponse(delivery))
+}
+
+func (api *WebhooksAPI) handleRetry(w http.ResponseWriter, id string) {
+	// Retry runs its own transaction.
+	delivery, err := api.webhooks.Retry(id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newDeliveryResponse(delivery))
+}
+
+func newDeliveryResponse(d domain.WebhookDelivery) deliveryResponse {
+	return deliveryResponse{
+		ID:             d.ID,
+		Webhook:        d.Webhook,
+		Event:          newEventResponse(d.Event),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  timePtr(d.NextAttemptAt),
+		LastAttemptAt:  timePtr(d.LastAttemptAt),
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+	}
+}
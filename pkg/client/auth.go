@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const sessionCookie = "vectrag_session"
+
+// Session is the admin session of the client. AuthRequired is false for
+// projects without admin users.
+type Session struct {
+	AuthRequired bool       `json:"authRequired"`
+	Username     string     `json:"username,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Login opens an admin session, required to change the models and the
+// content of projects with admin users. The client sends the session with
+// its following requests.
+func (c *Client) Login(ctx context.Context, username, password string) (Session, error) {
+	body := struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}{username, password}
+
+	var session Session
+	err := c.do(ctx, http.MethodPost, "/api/auth/login", nil, body, &session)
+	return session, err
+}
+
+// Logout ends the admin session of the client.
+func (c *Client) Logout(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/api/auth/logout", nil, nil, nil)
+}
+
+// Session returns the admin session of the client.
+func (c *Client) Session(ctx context.Context) (Session, error) {
+	var session Session
+	err := c.do(ctx, http.MethodGet, "/api/auth/session", nil, nil, &session)
+	return session, err
+}
+
+func (c *Client) sessionToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.session
+}
+
+// storeSession keeps the session cookie set or cleared by a response.
+func (c *Client) storeSession(resp *http.Response) {
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name != sessionCookie {
+			continue
+		}
+		c.mu.Lock()
+		if cookie.MaxAge < 0 {
+			c.session = ""
+		} else {
+			c.session = cookie.Value
+		}
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,212 @@
+// Package client is a Go client for the HTTP API of a VectraG server: the
+// models API, which manages the content schema, and the content API, which
+// reads and writes the entries of each model.
+//
+//	c := client.New("http://localhost:3000", client.WithPreviewToken(token))
+//	page, err := c.ListEntries(ctx, "article", client.Query{
+//		Filter: &client.Filter{Field: "views", Op: client.OpGt, Value: 10},
+//		Sort:   []string{"-publishedAt"},
+//		Limit:  20,
+//	})
+//
+// Projects with admin users require a session, opened with Login, or an API
+// token set with WithAPIToken, to manage the models and change content.
+//
+// Requests that are safe to repeat are retried with exponential backoff when
+// the server is unreachable or temporarily unavailable.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	previewToken string
+	apiToken     string
+	retry        RetryPolicy
+
+	// mu guards session, the admin session opened by Login.
+	mu      sync.Mutex
+	session string
+}
+
+// RetryPolicy controls how failed requests are retried. Only GET, PUT and
+// DELETE requests are retried, after network errors and 429, 502, 503 and
+// 504 responses.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, 1 disables retries.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry. It doubles on every
+	// retry, up to MaxBackoff, with random jitter.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  100 * time.Millisecond,
+	MaxBackoff:  2 * time.Second,
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithPreviewToken sets the preview token of the project, required to read
+// drafts.
+func WithPreviewToken(token string) Option {
+	return func(c *Client) { c.previewToken = token }
+}
+
+// WithAPIToken sets the API token the client authenticates with. It is sent
+// in place of the preview token, drafts require the preview scope.
+func WithAPIToken(token string) Option {
+	return func(c *Client) { c.apiToken = token }
+}
+
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// New returns a client for the server at baseURL, e.g.
+// "http://localhost:3000".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("vectrag: encoding request: %w", err)
+		}
+	}
+
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	attempts := c.retry.MaxAttempts
+	if attempts < 1 || method == http.MethodPost {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.retry.backoff(attempt)); err != nil {
+				return err
+			}
+		}
+
+		var retry bool
+		retry, err = c.send(ctx, method, u, payload, out)
+		if !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// send performs a single attempt of a request and reports whether it may be
+// retried.
+func (c *Client) send(ctx context.Context, method, u string, payload []byte, out any) (bool, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return false, fmt.Errorf("vectrag: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case c.apiToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	case c.previewToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.previewToken)
+	}
+	if session := c.sessionToken(); session != "" {
+		req.AddCookie(&http.Cookie{Name: sessionCookie, Value: session})
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return true, fmt.Errorf("vectrag: %w", err)
+	}
+	defer resp.Body.Close()
+	c.storeSession(resp)
+
+	if resp.StatusCode >= 400 {
+		return retryable(resp.StatusCode), newError(resp)
+	}
+	if out == nil {
+		return false, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("vectrag: decoding response: %w", err)
+	}
+	return false, nil
+}
+
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MinBackoff << (attempt - 1)
+	if p.MaxBackoff > 0 && (d > p.MaxBackoff || d <= 0) {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Full jitter over the upper half of the delay.
+	return d/2 + rand.N(d/2+1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Entry is an entry of a model. ID is empty for single models. CreatedBy is
+// the admin user, or "token:" and the ID of the API token, that created it.
+type Entry struct {
+	ID          string         `json:"id,omitempty"`
+	Data        map[string]any `json:"data"`
+	Status      string         `json:"status"`
+	CreatedBy   string         `json:"createdBy,omitempty"`
+	PublishedAt *time.Time     `json:"publishedAt,omitempty"`
+	PublishAt   *time.Time     `json:"publishAt,omitempty"`
+	UnpublishAt *time.Time     `json:"unpublishAt,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+// Decode stores the data of the entry in the value pointed to by v, e.g. a
+// struct generated by vectrag codegen.
+func (e Entry) Decode(v any) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+type Page struct {
+	Entries    []Entry
+	Total      int
+	NextCursor string
+}
+
+// ReadOptions selects the version of the content to read.
+type ReadOptions struct {
+	// Draft reads the draft version of entries and draft fields. It
+	// requires the client to have the preview token.
+	Draft bool
+}
+
+func (o ReadOptions) values() url.Values {
+	values := url.Values{}
+	if o.Draft {
+		values.Set("status", "draft")
+	}
+	return values
+}
+
+func contentPath(slug string, parts ...string) string {
+	p := "/api/content/" + url.PathEscape(slug)
+	for _, part := range parts {
+		p += "/" + url.PathEscape(part)
+	}
+	return p
+}
+
+// ListEntries returns a page of the entries of a collection model matching q.
+func (c *Client) ListEntries(ctx context.Context, slug string, q Query) (Page, error) {
+	var resp struct {
+		Data []Entry `json:"data"`
+		Meta struct {
+			Total      int    `json:"total"`
+			NextCursor string `json:"nextCursor"`
+		} `json:"meta"`
+	}
+	values, err := q.values()
+	if err != nil {
+		return Page{}, err
+	}
+	if err := c.do(ctx, http.MethodGet, contentPath(slug), values, nil, &resp); err != nil {
+		return Page{}, err
+	}
+	return Page{Entries: resp.Data, Total: resp.Meta.Total, NextCursor: resp.Meta.NextCursor}, nil
+}
+
+// AllEntries iterates over every entry matching q, fetching pages of q.Limit
+// entries (100 when unset) with cursor pagination. Iteration stops at the
+// first error.
+func (c *Client) AllEntries(ctx context.Context, slug string, q Query) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		if q.Limit == 0 {
+			q.Limit = 100
+		}
+		q.Offset = 0
+
+		for {
+			page, err := c.ListEntries(ctx, slug, q)
+			if err != nil {
+				yield(Entry{}, err)
+				return
+			}
+			for _, e := range page.Entries {
+				if !yield(e, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			q.After = page.NextCursor
+		}
+	}
+}
+
+func (c *Client) GetEntry(ctx context.Context, slug, id string, opts ReadOptions) (Entry, error) {
+	var entry Entry
+	err := c.do(ctx, http.MethodGet, contentPath(slug, id), opts.values(), nil, &entry)
+	return entry, err
+}
+
+// CreateEntry creates a draft entry in a collection model.
+func (c *Client) CreateEntry(ctx context.Context, slug string, data any) (Entry, error) {
+	var entry Entry
+	err := c.do(ctx, http.MethodPost, contentPath(slug), nil, data, &entry)
+	return entry, err
+}
+
+// UpdateEntry replaces the draft data of an entry.
+func (c *Client) UpdateEntry(ctx context.Context, slug, id string, data any) (Entry, error) {
+	var entry Entry
+	err := c.do(ctx, http.MethodPut, contentPath(slug, id), nil, data, &entry)
+	return entry, err
+}
+
+// DeleteEntry moves an entry to the trash.
+func (c *Client) DeleteEntry(ctx context.Context, slug, id string) error {
+	return c.do(ctx, http.MethodDelete, contentPath(slug, id), nil, nil, nil)
+}
+
+// GetSingle returns the entry of a single model.
+func (c *Client) GetSingle(ctx context.Context, slug string, opts ReadOptions) (Entry, error) {
+	var entry Entry
+	err := c.do(ctx, http.MethodGet, contentPath(slug), opts.values(), nil, &entry)
+	return entry, err
+}
+
+// PutSingle creates or replaces the draft data of the entry of a single model.
+func (c *Client) PutSingle(ctx context.Context, slug string, data any) (Entry, error) {
+	var entry Entry
+	err := c.do(ctx, http.MethodPut, contentPath(slug), nil, data, &entry)
+	return entry, err
+}
+
+// Publish publishes the draft of an entry. The id is ignored for single
+// models and may be empty.
+func (c *Client) Publish(ctx context.Context, slug, id string) (Entry, error) {
+	return c.lifecycle(ctx, slug, id, "publish", nil)
+}
+
+func (c *Client) Unpublish(ctx context.Context, slug, id string) (Entry, error) {
+	return c.lifecycle(ctx, slug, id, "unpublish", nil)
+}
+
+// Schedule sets when an entry is published and unpublished. Zero times clear
+// the corresponding transition.
+func (c *Client) Schedule(ctx context.Context, slug, id string, publishAt, unpublishAt time.Time) (Entry, error) {
+	body := struct {
+		PublishAt   *time.Time `json:"publishAt"`
+		UnpublishAt *time.Time `json:"unpublishAt"`
+	}{}
+	if !publishAt.IsZero() {
+		body.PublishAt = &publishAt
+	}
+	if !unpublishAt.IsZero() {
+		body.UnpublishAt = &unpublishAt
+	}
+	return c.lifecycle(ctx, slug, id, "schedule", body)
+}
+
+func (c *Client) lifecycle(ctx context.Context, slug, id, action string, body any) (Entry, error) {
+	path := contentPath(slug, id, action)
+	if id == "" {
+		path = contentPath(slug, action)
+	}
+
+	var entry Entry
+	err := c.do(ctx, http.MethodPost, path, nil, body, &entry)
+	return entry, err
+}
+
+// AggregateGroup holds the aggregations of the entries sharing the values of
+// the GroupBy fields in Key. Aggregated values are keyed by field.
+type AggregateGroup struct {
+	Key   map[string]any      `json:"group"`
+	Count int                 `json:"count"`
+	Sum   map[string]float64  `json:"sum"`
+	Avg   map[string]*float64 `json:"avg"`
+	Min   map[string]any      `json:"min"`
+	Max   map[string]any      `json:"max"`
+}
+
+type AggregateQuery struct {
+	Filter  *Filter
+	GroupBy []string
+	Sum     []string
+	Avg     []string
+	Min     []string
+	Max     []string
+	Draft   bool
+}
+
+// Aggregate counts the entries of a collection model matching q and computes
+// the requested aggregations, per group.
+func (c *Client) Aggregate(ctx context.Context, slug string, q AggregateQuery) ([]AggregateGroup, error) {
+	values := url.Values{}
+	if q.Filter != nil {
+		if err := q.Filter.encode("filter", values); err != nil {
+			return nil, err
+		}
+	}
+	for name, fields := range map[string][]string{"groupBy": q.GroupBy, "sum": q.Sum, "avg": q.Avg, "min": q.Min, "max": q.Max} {
+		for _, f := range fields {
+			values.Add(name, f)
+		}
+	}
+	if q.Draft {
+		values.Set("status", "draft")
+	}
+
+	var resp struct {
+		Data []AggregateGroup `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, contentPath(slug, "aggregate"), values, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+var (
+	ErrInvalid      = errors.New("invalid request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+)
+
+// Error is an error response of the server. It matches ErrInvalid,
+// ErrUnauthorized, ErrNotFound and ErrConflict with errors.Is according to
+// its status code.
+type Error struct {
+	StatusCode int
+	Message    string
+	// Field is the field a validation error is about, when the server
+	// reported one.
+	Field string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("vectrag: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrInvalid:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	}
+	return false
+}
+
+var validationField = regexp.MustCompile(`^validation error on field '([^']*)'`)
+
+func newError(resp *http.Response) *Error {
+	e := &Error{StatusCode: resp.StatusCode}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	var payload struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+		e.Message = payload.Error
+	} else {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+
+	if m := validationField.FindStringSubmatch(e.Message); m != nil {
+		e.Field = m[1]
+	}
+	return e
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Model is a content model as returned by the models API.
+type Model struct {
+	ID            string
+	Name          string
+	Slug          string
+	Description   string
+	Kind          string
+	Fields        []Field
+	Status        string
+	SchemaVersion int
+	DeletedAt     time.Time
+}
+
+type Field struct {
+	ID          string
+	Name        string
+	Type        string
+	Target      string
+	Options     map[string]any
+	Description string
+	Unique      bool
+	Required    bool
+	Status      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   time.Time
+}
+
+// Model kinds, field types and statuses.
+const (
+	KindCollection = "collection"
+	KindSingle     = "single"
+
+	TypeString   = "string"
+	TypeText     = "text"
+	TypeNumber   = "number"
+	TypeBoolean  = "boolean"
+	TypeDate     = "date"
+	TypeDateTime = "datetime"
+	TypeRelation = "relation"
+
+	StatusDraft   = "draft"
+	StatusPublish = "publish"
+	StatusDelete  = "delete"
+)
+
+type CreateModelRequest struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Kind        string             `json:"kind,omitempty"`
+	Status      string             `json:"status"`
+	Fields      []CreateFieldInput `json:"fields"`
+}
+
+type CreateFieldInput struct {
+	Name        string         `json:"name"`
+	Type        string         `json:"type"`
+	Target      string         `json:"target,omitempty"`
+	Options     map[string]any `json:"options,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Unique      bool           `json:"unique,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Status      string         `json:"status"`
+}
+
+// UpdateModelRequest replaces the definition of a model. Existing fields are
+// matched by ID; fields left out are moved to the trash.
+//
+// Stored values of fields whose type changes are converted. Changes that are
+// not safe fail with ErrConflict unless Force is set and Fallbacks holds the
+// value replacing the values that cannot be converted, keyed by field name.
+type UpdateModelRequest struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Kind        string             `json:"kind,omitempty"`
+	Status      string             `json:"status"`
+	Fields      []UpdateFieldInput `json:"fields"`
+	Force       bool               `json:"force,omitempty"`
+	Fallbacks   map[string]any     `json:"fallbacks,omitempty"`
+}
+
+type UpdateFieldInput struct {
+	// ID is empty for new fields.
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Type        string         `json:"type"`
+	Target      string         `json:"target,omitempty"`
+	Options     map[string]any `json:"options,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Unique      bool           `json:"unique,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Status      string         `json:"status"`
+}
+
+func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
+	var models []Model
+	if err := c.do(ctx, http.MethodGet, "/api/models", nil, nil, &models); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+func (c *Client) GetModel(ctx context.Context, slug string) (Model, error) {
+	var model Model
+	err := c.do(ctx, http.MethodGet, "/api/models/"+url.PathEscape(slug), nil, nil, &model)
+	return model, err
+}
+
+func (c *Client) CreateModel(ctx context.Context, req CreateModelRequest) (Model, error) {
+	var model Model
+	err := c.do(ctx, http.MethodPost, "/api/models", nil, req, &model)
+	return model, err
+}
+
+func (c *Client) UpdateModel(ctx context.Context, slug string, req UpdateModelRequest) (Model, error) {
+	var model Model
+	err := c.do(ctx, http.MethodPut, "/api/models/"+url.PathEscape(slug), nil, req, &model)
+	return model, err
+}
+
+// DeleteModel moves a model to the trash.
+func (c *Client) DeleteModel(ctx context.Context, slug string) error {
+	return c.do(ctx, http.MethodDelete, "/api/models/"+url.PathEscape(slug), nil, nil, nil)
+}
+
+// RenameModel changes the name and the slug of a model. Empty values are left
+// unchanged. The server redirects requests using the former slug for a grace
+// period.
+func (c *Client) RenameModel(ctx context.Context, slug, name, newSlug string) (Model, error) {
+	body := struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	}{name, newSlug}
+
+	var model Model
+	err := c.do(ctx, http.MethodPost, "/api/models/"+url.PathEscape(slug)+"/rename", nil, body, &model)
+	return model, err
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter operators.
+const (
+	OpEq         = "eq"
+	OpNe         = "ne"
+	OpLt         = "lt"
+	OpGt         = "gt"
+	OpIn         = "in"
+	OpContains   = "contains"
+	OpStartsWith = "startsWith"
+	// OpNull matches entries where the field is missing, with Value true,
+	// or present, with Value false.
+	OpNull = "null"
+)
+
+// Filter is either a condition on a field or a group of filters combined
+// with And or Or. Fields of related entries are addressed as
+// "relation.field", e.g. "author.name".
+type Filter struct {
+	Field string
+	Op    string
+	// Value is compared to the field. OpIn takes a slice.
+	Value any
+
+	And []Filter
+	Or  []Filter
+}
+
+// Query selects entries of a collection model.
+type Query struct {
+	Filter *Filter
+	// Sort lists the fields to sort by, prefixed with "-" for descending
+	// order.
+	Sort []string
+	// Fields restricts the data of the returned entries to these fields.
+	Fields []string
+	Limit  int
+	Offset int
+	// After is the NextCursor of the previous page.
+	After string
+	Draft bool
+}
+
+func (q Query) values() (url.Values, error) {
+	values := url.Values{}
+	if q.Filter != nil {
+		if err := q.Filter.encode("filter", values); err != nil {
+			return nil, err
+		}
+	}
+	if len(q.Sort) > 0 {
+		values.Set("sort", strings.Join(q.Sort, ","))
+	}
+	if len(q.Fields) > 0 {
+		values.Set("fields", strings.Join(q.Fields, ","))
+	}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Offset > 0 {
+		values.Set("offset", strconv.Itoa(q.Offset))
+	}
+	if q.After != "" {
+		values.Set("after", q.After)
+	}
+	if q.Draft {
+		values.Set("status", "draft")
+	}
+	return values, nil
+}
+
+// encode adds the filter to values in the bracketed syntax of the content
+// API, e.g. filter[and][0][views][gt]=10.
+func (f Filter) encode(prefix string, values url.Values) error {
+	switch {
+	case len(f.And) > 0 || len(f.Or) > 0:
+		for i, child := range f.And {
+			if err := child.encode(fmt.Sprintf("%s[and][%d]", prefix, i), values); err != nil {
+				return err
+			}
+		}
+		for i, child := range f.Or {
+			if err := child.encode(fmt.Sprintf("%s[or][%d]", prefix, i), values); err != nil {
+				return err
+			}
+		}
+		return nil
+	case f.Field == "" || f.Op == "":
+		return fmt.Errorf("vectrag: filter needs a field and an operator, or a group")
+	}
+
+	key := fmt.Sprintf("%s[%s][%s]", prefix, f.Field, f.Op)
+	if f.Op == OpIn {
+		items, ok := f.Value.([]any)
+		if !ok {
+			items = toAnySlice(f.Value)
+		}
+		list := make([]string, len(items))
+		for i, item := range items {
+			list[i] = formatValue(item)
+		}
+		values.Set(key, strings.Join(list, ","))
+		return nil
+	}
+	values.Set(key, formatValue(f.Value))
+	return nil
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toAnySlice(v any) []any {
+	switch v := v.(type) {
+	case []string:
+		items := make([]any, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items
+	case []float64:
+		items := make([]any, len(v))
+		for i, f := range v {
+			items[i] = f
+		}
+		return items
+	case []int:
+		items := make([]any, len(v))
+		for i, n := range v {
+			items[i] = n
+		}
+		return items
+	}
+	return []any{v}
+}
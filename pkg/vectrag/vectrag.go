@@ -0,0 +1,285 @@
+// Package vectrag runs a VectraG project inside another Go program. A
+// Project serves the HTTP API of vectrag develop, without the admin panel:
+// the models, content, GraphQL, trash, events, changes and webhooks APIs.
+//
+//	project, err := vectrag.New("./cms",
+//		vectrag.WithPrefix("/cms"),
+//		vectrag.WithAuth(authenticate),
+//	)
+//	project.Hooks().BeforeCreate("article", setSlug)
+//	go project.Run(ctx)
+//	mux.Handle("/cms/", project)
+//
+// The project is configured by the vectrag.config.yaml of its directory, and
+// shares its files and lock with the vectrag commands and servers working on
+// the same directory.
+//
+// Custom field types are registered with RegisterFieldType before the project
+// is loaded. The vectrag commands do not know them: only the program
+// registering a type saves the models and writes the entries using it.
+package vectrag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/axarus/vectrag/internal/application"
+	"github.com/axarus/vectrag/internal/domain"
+	infrahttp "github.com/axarus/vectrag/internal/infrastructure/http"
+)
+
+// The types of the models, entries and hooks of a project.
+type (
+	Model     = domain.Model
+	Field     = domain.Field
+	Entry     = domain.Entry
+	EntryPage = domain.EntryPage
+	Query     = domain.Query
+	Filter    = domain.Filter
+	Event     = domain.Event
+	Change    = domain.Change
+	Policy    = domain.Policy
+	Action    = domain.Action
+	Identity  = infrahttp.Identity
+
+	// Permission allows actions on models. Fields limits them to the given
+	// fields, and Own to the entries created by the caller.
+	Permission = domain.Permission
+
+	// ModelRepository stores the models of a project, see WithModelRepository.
+	ModelRepository = domain.Repository
+	// ContentRepository stores the entries of a project, see
+	// WithContentRepository.
+	ContentRepository = domain.ContentRepository
+
+	Hooks       = application.Hooks
+	HookContext = application.HookContext
+	HookAction  = application.HookAction
+	EntryHook   = application.EntryHook
+
+	ChangePublisher       = application.ChangePublisher
+	MemoryChangePublisher = application.MemoryChangePublisher
+
+	// ValidationError rejects a write with a bad request, e.g. from an
+	// entry hook.
+	ValidationError = domain.ValidationError
+
+	// FieldTypePlugin defines a custom field type, see RegisterFieldType.
+	FieldTypePlugin = domain.FieldTypePlugin
+	FieldType       = domain.FieldType
+	ValueType       = domain.ValueType
+	ColumnType      = domain.ColumnType
+	FieldUI         = domain.FieldUI
+)
+
+const (
+	ActionRead    = domain.ActionRead
+	ActionPreview = domain.ActionPreview
+	ActionCreate  = domain.ActionCreate
+	ActionUpdate  = domain.ActionUpdate
+	ActionDelete  = domain.ActionDelete
+	ActionPublish = domain.ActionPublish
+	ActionSchema  = domain.ActionSchema
+	ActionAdmin   = domain.ActionAdmin
+
+	// AllModels stands for every model in permissions and hook
+	// registrations.
+	AllModels = application.AllModels
+)
+
+// The JSON types of the values of field types, and the kinds of SQL columns
+// storing them.
+const (
+	ValueString  = domain.ValueString
+	ValueNumber  = domain.ValueNumber
+	ValueBoolean = domain.ValueBoolean
+
+	ColumnText      = domain.ColumnText
+	ColumnFloat     = domain.ColumnFloat
+	ColumnInteger   = domain.ColumnInteger
+	ColumnBoolean   = domain.ColumnBoolean
+	ColumnDate      = domain.ColumnDate
+	ColumnTimestamp = domain.ColumnTimestamp
+)
+
+// Errors returned by repositories for missing models and entries, answered
+// 404 Not Found.
+var (
+	ErrModelNotFound = domain.ErrModelNotFound
+	ErrEntryNotFound = domain.ErrEntryNotFound
+)
+
+// FullAccess returns the policy of a caller allowed everything, creating
+// entries as owner.
+func FullAccess(owner string) Policy {
+	return domain.FullAccess(owner)
+}
+
+// RegisterFieldType adds a field type, e.g. money or color, that the fields
+// of models can then be declared with. Field types are shared by the projects
+// of the program, and must be registered before the projects using them are
+// loaded. It fails when a type of the same name exists.
+func RegisterFieldType(p FieldTypePlugin) error {
+	return domain.RegisterFieldType(p)
+}
+
+// NewHooks returns an empty set of entry hooks, see WithHooks.
+func NewHooks() *Hooks {
+	return application.NewHooks()
+}
+
+// NewMemoryChangePublisher returns a publisher keeping the changes of the
+// project in memory, see WithPublisher.
+func NewMemoryChangePublisher(name string) *MemoryChangePublisher {
+	return application.NewMemoryChangePublisher(name)
+}
+
+// AuthFunc identifies the callers of the API, e.g. from the sessions of the
+// embedding program. It returns a nil Identity to leave the request to the
+// admin sessions and API tokens of the project, and an error to answer it
+// 401 Unauthorized.
+type AuthFunc func(r *http.Request) (*Identity, error)
+
+type Option func(*options)
+
+type options struct {
+	prefix  string
+	project infrahttp.ProjectOptions
+}
+
+// WithPrefix serves the API under prefix, e.g. /cms for /cms/api/content/...,
+// for programs mounting the project in their own router.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = "/" + strings.Trim(prefix, "/")
+	}
+}
+
+// WithModelRepository stores the models in repo instead of the YAML files of
+// the project.
+//
+// The writes to repo are not part of the transactions of the project: they
+// are kept when a later step of the same write fails, e.g. converting the
+// entries of an updated model, and are not committed along with the change
+// log, so the outbox may miss or relay changes repo does not hold. Programs
+// needing those guarantees keep the files of the project.
+func WithModelRepository(repo ModelRepository) Option {
+	return func(o *options) {
+		o.project.Models = repo
+	}
+}
+
+// WithContentRepository stores the entries in repo instead of the JSON files
+// of the project. Like those of WithModelRepository, the writes to repo are
+// not part of the transactions of the project, and are not committed along
+// with the change log relayed by the outbox.
+func WithContentRepository(repo ContentRepository) Option {
+	return func(o *options) {
+		o.project.Content = repo
+	}
+}
+
+// WithHooks runs hooks around the writes of entries. Hooks can also be
+// registered on Project.Hooks.
+func WithHooks(hooks *Hooks) Option {
+	return func(o *options) {
+		o.project.Hooks = hooks
+	}
+}
+
+// WithPublisher relays the change log of the project to p, along with the
+// publishers of the outbox configured in vectrag.config.yaml. Changes are
+// relayed while Run runs.
+func WithPublisher(p ChangePublisher) Option {
+	return func(o *options) {
+		o.project.Publishers = append(o.project.Publishers, p)
+	}
+}
+
+// WithAuth identifies the callers of the API with fn before the admin
+// sessions and API tokens of the project do.
+func WithAuth(fn AuthFunc) Option {
+	return func(o *options) {
+		o.project.Auth = infrahttp.ExternalAuth(fn)
+	}
+}
+
+// Project is a VectraG project served by an embedding program. It is an
+// http.Handler serving the API of the project.
+type Project struct {
+	project *infrahttp.Project
+	handler http.Handler
+
+	runMu   sync.Mutex
+	running bool
+}
+
+// New loads the project in root, the directory holding its
+// vectrag.config.yaml.
+func New(root string, opts ...Option) (*Project, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	project, err := infrahttp.LoadProjectWith(root, o.project)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	project.Register(mux)
+	var handler http.Handler = mux
+	if o.prefix != "" && o.prefix != "/" {
+		handler = http.StripPrefix(o.prefix, mux)
+	}
+	return &Project{project: project, handler: handler}, nil
+}
+
+func (p *Project) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.handler.ServeHTTP(w, r)
+}
+
+// Hooks returns the entry hooks of the project, to register hooks on.
+func (p *Project) Hooks() *Hooks {
+	return p.project.Hooks()
+}
+
+// RolePolicy returns the policy of a role of vectrag.config.yaml, creating
+// entries as owner, for an AuthFunc to give its callers.
+func (p *Project) RolePolicy(role, owner string) (Policy, error) {
+	return p.project.RolePolicy(role, owner)
+}
+
+// Run runs the background work of the project until ctx is cancelled:
+// scheduled publications, trash purges, webhook deliveries, the event
+// stream and the outbox. It must run while the API is served, and only once
+// at a time.
+func (p *Project) Run(ctx context.Context) error {
+	p.runMu.Lock()
+	if p.running {
+		p.runMu.Unlock()
+		return fmt.Errorf("project is already running")
+	}
+	p.running = true
+	p.runMu.Unlock()
+	defer func() {
+		p.runMu.Lock()
+		p.running = false
+		p.runMu.Unlock()
+	}()
+
+	var wg sync.WaitGroup
+	for _, w := range p.project.Workers() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Run(ctx)
+		}()
+	}
+	wg.Wait()
+	return nil
+}